@@ -0,0 +1,192 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Where a rule of an A/B comparison was found
+const (
+	abOnlyA = "only-a"
+	abOnlyB = "only-b"
+	abBoth  = "both"
+)
+
+// RuleDifference compares a rule between two rule sets. Measures of the set
+// lacking the rule are zero, as are the test results.
+type RuleDifference struct {
+	Antecedent []string `json:"antecedent"`
+	Consequent []string `json:"consequent"`
+	Status     string   `json:"status"`
+	A          Rule     `json:"a"`
+	B          Rule     `json:"b"`
+	// CohenH is the effect size of the confidence difference, 2·asin√cB −
+	// 2·asin√cA; about 0.2 is small, 0.5 medium and 0.8 large
+	CohenH float64 `json:"cohenH"`
+	// LiftRatio is the lift in B over that in A
+	LiftRatio float64 `json:"liftRatio"`
+	// PValue tests equal confidences with a two-proportion z-test over the
+	// transactions containing the antecedent
+	PValue         float64 `json:"pValue"`
+	AdjustedPValue float64 `json:"adjustedPValue"`
+	Significant    bool    `json:"significant"`
+}
+
+// confidenceTest returns the two-sided p-value of a two-proportion z-test of
+// the confidences xyA/xA and xyB/xB
+func confidenceTest(xyA, xA, xyB, xB float64) float64 {
+	if xA == 0 || xB == 0 {
+		return 1
+	}
+	pooled := (xyA + xyB) / (xA + xB)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/xA + 1/xB))
+	if se == 0 {
+		return 1
+	}
+	z := (xyB/xB - xyA/xA) / se
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// compareRuleSets matches the rules of A and B, tests the confidence of the
+// rules in both and adjusts those p-values by method. nA and nB are the
+// transaction counts behind the rule sets.
+func compareRuleSets(a, b []Rule, nA, nB int, method string, alpha float64) ([]RuleDifference, error) {
+	inB := make(map[string]Rule, len(b))
+	for _, rule := range b {
+		inB[rule.String()] = rule
+	}
+	var out, both []RuleDifference
+	seen := make(map[string]bool)
+	for _, ra := range a {
+		key := ra.String()
+		seen[key] = true
+		rb, ok := inB[key]
+		if !ok {
+			out = append(out, RuleDifference{Antecedent: ra.Antecedent, Consequent: ra.Consequent, Status: abOnlyA, A: ra})
+			continue
+		}
+		d := RuleDifference{Antecedent: ra.Antecedent, Consequent: ra.Consequent, Status: abBoth, A: ra, B: rb}
+		d.CohenH = 2*math.Asin(math.Sqrt(rb.Confidence)) - 2*math.Asin(math.Sqrt(ra.Confidence))
+		d.LiftRatio = rb.Lift / ra.Lift
+		// Supports and confidences give back the counts of the table
+		xyA, xyB := ra.Support*float64(nA), rb.Support*float64(nB)
+		d.PValue = confidenceTest(xyA, xyA/ra.Confidence, xyB, xyB/rb.Confidence)
+		both = append(both, d)
+	}
+	for _, rb := range b {
+		if !seen[rb.String()] {
+			out = append(out, RuleDifference{Antecedent: rb.Antecedent, Consequent: rb.Consequent, Status: abOnlyB, B: rb})
+		}
+	}
+
+	p := make([]float64, len(both))
+	for i := range both {
+		p[i] = both[i].PValue
+	}
+	adjusted, err := adjustPValues(p, method)
+	if err != nil {
+		return nil, err
+	}
+	for i := range both {
+		both[i].AdjustedPValue = adjusted[i]
+		both[i].Significant = adjusted[i] <= alpha
+	}
+	// Significant differences lead, by effect size, then the unique rules
+	sort.SliceStable(both, func(i, j int) bool {
+		if both[i].Significant != both[j].Significant {
+			return both[i].Significant
+		}
+		return math.Abs(both[i].CohenH) > math.Abs(both[j].CohenH)
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		return math.Max(out[i].A.Support, out[i].B.Support) > math.Max(out[j].A.Support, out[j].B.Support)
+	})
+	return append(both, out...), nil
+}
+
+// writeABCSV writes the comparison to path
+func writeABCSV(path string, diffs []RuleDifference) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Antecedent,Consequent,Status,SupportA,ConfidenceA,LiftA,SupportB,ConfidenceB,LiftB,ConfidenceDiff,CohenH,LiftRatio,PValue,AdjustedPValue,Significant")
+	for _, d := range diffs {
+		fmt.Fprintf(f, "\"%s\",\"%s\",%s,%f,%f,%f,%f,%f,%f",
+			strings.Join(d.Antecedent, ","), strings.Join(d.Consequent, ","), d.Status,
+			d.A.Support, d.A.Confidence, d.A.Lift, d.B.Support, d.B.Confidence, d.B.Lift)
+		// Differences are only defined for rules found on both sides
+		if d.Status != abBoth {
+			fmt.Fprintln(f, ",,,,,,")
+			continue
+		}
+		fmt.Fprintf(f, ",%f,%f,%f,%g,%g,%t\n", d.B.Confidence-d.A.Confidence, d.CohenH, d.LiftRatio,
+			d.PValue, d.AdjustedPValue, d.Significant)
+	}
+	return f.Close()
+}
+
+// runABTest implements the abtest subcommand, which compares the rules of two
+// saved results, such as control and treatment stores
+func runABTest(args []string) error {
+	fs := flag.NewFlagSet("abtest", flag.ExitOnError)
+	modelA := fs.String("a", "", "saved result of the control, e.g. results/control")
+	modelB := fs.String("b", "", "saved result of the treatment")
+	transactionsA := fs.Int("transactions-a", 0, "transactions behind -a (default: from its run manifest)")
+	transactionsB := fs.Int("transactions-b", 0, "transactions behind -b (default: from its run manifest)")
+	minConfidence := fs.Float64("minconfidence", 0.5, "minimum confidence of the compared rules")
+	pAdjust := fs.String("p-adjust", AdjustBenjaminiHochberg, "correction of the p-values across rules: bh or bonferroni")
+	alpha := fs.Float64("alpha", 0.05, "adjusted p-value below which a difference is significant")
+	output := fs.String("output", "", "comparison file (default: <a>_vs_<b>_ab.csv next to -a)")
+	fs.Parse(args)
+	if *modelA == "" || *modelB == "" || fs.NArg() != 0 {
+		return fmt.Errorf("usage: abtest -a results/<run> -b results/<run> [flags]")
+	}
+	if _, err := adjustPValues(nil, *pAdjust); err != nil {
+		return err
+	}
+
+	pathA, pathB := modelSummaryPath(*modelA), modelSummaryPath(*modelB)
+	minerA, _, err := loadSavedItemsets(pathA, *transactionsA)
+	if err != nil {
+		return err
+	}
+	minerB, _, err := loadSavedItemsets(pathB, *transactionsB)
+	if err != nil {
+		return err
+	}
+	diffs, err := compareRuleSets(minerA.GenerateRules(*minConfidence), minerB.GenerateRules(*minConfidence),
+		minerA.transactionLen, minerB.transactionLen, *pAdjust, *alpha)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	significant := 0
+	for _, d := range diffs {
+		counts[d.Status]++
+		if d.Significant {
+			significant++
+		}
+	}
+	if *output == "" {
+		baseA := strings.TrimSuffix(filepath.Base(pathA), "_summary.csv")
+		baseB := strings.TrimSuffix(filepath.Base(pathB), "_summary.csv")
+		*output = filepath.Join(filepath.Dir(pathA), baseA+"_vs_"+baseB+"_ab.csv")
+	}
+	if err := writeABCSV(*output, diffs); err != nil {
+		return err
+	}
+	fmt.Printf("%d rules only in A, %d only in B, %d in both of which %d differ significantly\n",
+		counts[abOnlyA], counts[abOnlyB], counts[abBoth], significant)
+	fmt.Printf("Comparison written to %s\n", *output)
+	return nil
+}
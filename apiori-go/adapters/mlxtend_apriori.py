@@ -0,0 +1,34 @@
+import argparse
+
+import pandas as pd
+from mlxtend.frequent_patterns import apriori
+from mlxtend.preprocessing import TransactionEncoder
+
+
+def main():
+    """
+    Mine a dataset of space-separated transactions with mlxtend's Apriori
+    and write the itemsets in SPMF layout ("items #SUP: count") for the
+    crossbench subcommand.
+    """
+    parser = argparse.ArgumentParser(description='mlxtend adapter for crossbench')
+    parser.add_argument('input', help='dataset, one transaction per line')
+    parser.add_argument('output', help='file receiving the frequent itemsets')
+    parser.add_argument('minsupport', type=float, help='minimum support as a fraction')
+    args = parser.parse_args()
+
+    with open(args.input) as f:
+        transactions = [line.split() for line in f]
+
+    encoder = TransactionEncoder()
+    frame = pd.DataFrame(encoder.fit(transactions).transform(transactions), columns=encoder.columns_)
+    itemsets = apriori(frame, min_support=args.minsupport, use_colnames=True)
+
+    with open(args.output, 'w') as out:
+        for support, items in zip(itemsets['support'], itemsets['itemsets']):
+            count = round(support * len(transactions))
+            out.write(f"{' '.join(sorted(items))} #SUP: {count}\n")
+
+
+if __name__ == '__main__':
+    main()
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ItemAttributes maps an attribute name to the value of each item having it
+type ItemAttributes map[string]map[string]float64
+
+// LoadItemAttributes reads a table of numeric item attributes. Its first
+// line names the columns, "item" then the attributes, e.g. "item price
+// margin"; every further line gives an item and its values. Blank lines and
+// lines starting with # are ignored, and "-" leaves a value out.
+func LoadItemAttributes(filename string) (ItemAttributes, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	attributes := make(ItemAttributes)
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if names == nil {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%s:%d: expected a header \"item attribute...\"", filename, i+1)
+			}
+			names = fields[1:]
+			for _, name := range names {
+				attributes[name] = make(map[string]float64)
+			}
+			continue
+		}
+		if len(fields) != len(names)+1 {
+			return nil, fmt.Errorf("%s:%d: expected %d values", filename, i+1, len(names))
+		}
+		for j, field := range fields[1:] {
+			if field == "-" {
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid %s %q", filename, i+1, names[j], field)
+			}
+			attributes[names[j]][fields[0]] = v
+		}
+	}
+	return attributes, nil
+}
+
+// AggregateConstraint bounds an aggregate of an attribute over the items of
+// an itemset, such as sum(price) <= 50
+type AggregateConstraint struct {
+	Func      string
+	Attribute string
+	Op        string
+	Value     float64
+	// AntiMonotone constraints fail for every superset of an itemset failing
+	// them, so candidates failing them are pruned before counting; the others
+	// only filter the mined itemsets
+	AntiMonotone bool
+}
+
+func (c AggregateConstraint) String() string {
+	return fmt.Sprintf("%s(%s) %s %g", c.Func, c.Attribute, c.Op, c.Value)
+}
+
+var aggregatePattern = regexp.MustCompile(`^(sum|avg|min|max)\(\s*(\w+)\s*\)\s*(<=|>=|<|>)\s*(-?[0-9.eE+-]+)$`)
+
+// ParseAggregateConstraints parses constraints joined by && against the
+// attributes, deciding which of them are anti-monotone: upper bounds on sum
+// over non-negative values and on max, and lower bounds on min
+func ParseAggregateConstraints(expr string, attributes ItemAttributes) ([]AggregateConstraint, error) {
+	var constraints []AggregateConstraint
+	for _, part := range strings.Split(expr, "&&") {
+		m := aggregatePattern.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return nil, fmt.Errorf("invalid constraint %q: expected e.g. sum(price) <= 50 with sum, avg, min or max", strings.TrimSpace(part))
+		}
+		values, ok := attributes[m[2]]
+		if !ok {
+			return nil, fmt.Errorf("constraint %q: unknown attribute %q", strings.TrimSpace(part), m[2])
+		}
+		v, err := strconv.ParseFloat(m[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("constraint %q: invalid number %q", strings.TrimSpace(part), m[4])
+		}
+		c := AggregateConstraint{Func: m[1], Attribute: m[2], Op: m[3], Value: v}
+		upper := c.Op == "<" || c.Op == "<="
+		switch c.Func {
+		case "sum":
+			nonNegative := true
+			for _, x := range values {
+				nonNegative = nonNegative && x >= 0
+			}
+			c.AntiMonotone = upper && nonNegative
+		case "max":
+			c.AntiMonotone = upper
+		case "min":
+			c.AntiMonotone = !upper
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// satisfied evaluates the constraint over the items having the attribute. An
+// itemset with none of them passes anti-monotone constraints, so that values
+// added by its supersets still decide, and fails the others.
+func (c AggregateConstraint) satisfied(set ItemSet, attributes ItemAttributes) bool {
+	values := attributes[c.Attribute]
+	sum, low, high, n := 0.0, math.Inf(1), math.Inf(-1), 0
+	for item := range set {
+		if v, ok := values[item]; ok {
+			sum += v
+			low, high = math.Min(low, v), math.Max(high, v)
+			n++
+		}
+	}
+	if n == 0 {
+		return c.AntiMonotone
+	}
+	var x float64
+	switch c.Func {
+	case "sum":
+		x = sum
+	case "avg":
+		x = sum / float64(n)
+	case "min":
+		x = low
+	case "max":
+		x = high
+	}
+	switch c.Op {
+	case "<":
+		return x < c.Value
+	case "<=":
+		return x <= c.Value
+	case ">":
+		return x > c.Value
+	default:
+		return x >= c.Value
+	}
+}
+
+// SetAggregateConstraints restricts the mined itemsets to those satisfying
+// every constraint over attributes
+func (am *AprioriMiner) SetAggregateConstraints(constraints []AggregateConstraint, attributes ItemAttributes) {
+	am.aggregates, am.attributes = constraints, attributes
+}
+
+// pruneByAggregates drops the candidates failing an anti-monotone constraint
+func (am *AprioriMiner) pruneByAggregates(candidates []ItemSet) []ItemSet {
+	kept := candidates[:0]
+	for _, candidate := range candidates {
+		ok := true
+		for _, c := range am.aggregates {
+			if c.AntiMonotone && !c.satisfied(candidate, am.attributes) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// filterByAggregates removes the mined itemsets failing any constraint once
+// mining ends; the anti-monotone ones never made it that far
+func (am *AprioriMiner) filterByAggregates() {
+	for k, sets := range am.frequentSets {
+		kept := sets[:0]
+		for _, set := range sets {
+			ok := true
+			for _, c := range am.aggregates {
+				if !c.AntiMonotone && !c.satisfied(set, am.attributes) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				kept = append(kept, set)
+			}
+		}
+		if len(kept) == 0 {
+			delete(am.frequentSets, k)
+		} else {
+			am.frequentSets[k] = kept
+		}
+	}
+}
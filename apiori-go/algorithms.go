@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MiningAlgorithm mines the frequent itemsets of a dataset, returned in the
+// order of sortResults
+type MiningAlgorithm func(dataset Dataset, minSupport float64) []ItemsetResult
+
+// miningAlgorithms are the algorithms selectable by name
+var miningAlgorithms = map[string]MiningAlgorithm{
+	"apriori":       aprioriWorkers(1),
+	"eclat":         MineEclat,
+	"eclat-roaring": MineEclatRoaring,
+}
+
+// aprioriWorkers is Apriori counting each level with the given number of
+// workers
+func aprioriWorkers(workers int) MiningAlgorithm {
+	return func(dataset Dataset, minSupport float64) []ItemsetResult {
+		miner := NewAprioriMiner(dataset, minSupport)
+		miner.SetWorkers(workers)
+		miner.Mine()
+		return miner.Results()
+	}
+}
+
+// lookupAlgorithm returns the algorithm registered under name
+func lookupAlgorithm(name string) (MiningAlgorithm, error) {
+	if algorithm, ok := miningAlgorithms[name]; ok {
+		return algorithm, nil
+	}
+	names := make([]string, 0, len(miningAlgorithms))
+	for n := range miningAlgorithms {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown algorithm %q: must be one of %s", name, strings.Join(names, ", "))
+}
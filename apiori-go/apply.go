@@ -0,0 +1,184 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AppliedTransaction is a transaction with the rules whose antecedent it
+// holds, in rule order, and the items they predict that it lacks
+type AppliedTransaction struct {
+	// ID is the 1-based line of the transaction in its file
+	ID        int      `json:"id"`
+	Items     []string `json:"items"`
+	Matched   []Rule   `json:"matched"`
+	Predicted []string `json:"predicted"`
+	// Confirmed counts the matched rules whose consequent the transaction
+	// also holds
+	Confirmed int `json:"confirmed"`
+}
+
+// applyRules matches rules against every transaction, predicting up to n
+// items per transaction as Recommend does
+func applyRules(rules []Rule, dataset Dataset, n int) []AppliedTransaction {
+	out := make([]AppliedTransaction, len(dataset))
+	for i, t := range dataset {
+		a := AppliedTransaction{ID: i + 1, Items: t}
+		for _, rule := range rules {
+			if !isSubset(toItemSet(rule.Antecedent), t) {
+				continue
+			}
+			a.Matched = append(a.Matched, rule)
+			if isSubset(toItemSet(rule.Consequent), t) {
+				a.Confirmed++
+			}
+		}
+		for _, r := range Recommend(a.Matched, t, n) {
+			a.Predicted = append(a.Predicted, r.Item)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// writeAppliedCSV writes a line per transaction with its matched rules joined
+// by semicolons
+func writeAppliedCSV(path string, applied []AppliedTransaction) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "Transaction,Items,Matched,Confirmed,Predicted,Rules")
+	for _, a := range applied {
+		rules := make([]string, len(a.Matched))
+		for i, rule := range a.Matched {
+			rules[i] = rule.String()
+		}
+		fmt.Fprintf(w, "%d,\"%s\",%d,%d,\"%s\",\"%s\"\n", a.ID, strings.Join(a.Items, ","), len(a.Matched), a.Confirmed,
+			strings.Join(a.Predicted, ","), strings.Join(rules, ";"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// loadRulesFile reads a rules CSV keeping the rules meeting minConfidence and
+// minLift, ordered by rankBy
+func loadRulesFile(path string, minConfidence, minLift float64, rankBy string) ([]Rule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rules, err := ReadRulesCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	kept := rules[:0]
+	for _, rule := range rules {
+		if rule.Confidence >= minConfidence && rule.Lift >= minLift {
+			kept = append(kept, rule)
+		}
+	}
+	return kept, SortRulesBy(kept, rankBy)
+}
+
+// runApply implements the apply subcommand, which matches the rules of a
+// rules file against new transactions to audit them on fresh data
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	minConfidence := fs.Float64("minconfidence", 0, "ignore rules with a lower confidence")
+	minLift := fs.Float64("min-lift", 0, "ignore rules with a lower lift")
+	rankBy := fs.String("rank-by", RankConfidence, "order of the matched rules and predictions: confidence, lift, leverage or normalized-leverage")
+	n := fs.Int("n", 5, "items predicted per transaction")
+	evaluate := fs.Bool("evaluate", false, "score the rules as a classifier of hidden items, writing <transactions>_classifier.csv and _rule_coverage.csv")
+	labels := fs.String("labels", "", "file of the items to predict for each transaction, a line each (default: hide items of the transactions)")
+	hide := fs.Int("hide", 1, "items hidden from each transaction to predict when evaluating without -labels")
+	seed := fs.Int64("seed", 1, "seed choosing the hidden items")
+	outputDir := fs.String("output-dir", "results", "directory receiving <transactions>_applied.csv")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: apply [flags] <rules.csv> <transactions>")
+	}
+	if *n < 1 {
+		return fmt.Errorf("n must be at least 1")
+	}
+	if *hide < 1 {
+		return fmt.Errorf("hide must be at least 1")
+	}
+	if *labels != "" && !*evaluate {
+		return fmt.Errorf("labels needs -evaluate")
+	}
+	if err := SortRulesBy(nil, *rankBy); err != nil {
+		return err
+	}
+	rules, err := loadRulesFile(fs.Arg(0), *minConfidence, *minLift, *rankBy)
+	if err != nil {
+		return err
+	}
+	filename := fs.Arg(1)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	// Evaluation predicts the targets from the rest of each transaction
+	var targets Dataset
+	if *evaluate {
+		if *labels != "" {
+			if targets, err = loadLabels(*labels, len(dataset)); err != nil {
+				return err
+			}
+			dataset = withoutTargets(dataset, targets)
+		} else {
+			dataset, targets = hideItems(dataset, *hide, *seed)
+		}
+	}
+	applied := applyRules(rules, dataset, *n)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	base := filepath.Join(*outputDir, getOutputBasename(filename))
+	path := base + "_applied.csv"
+	if err := writeAppliedCSV(path, applied); err != nil {
+		return err
+	}
+	covered, matched, confirmed := 0, 0, 0
+	for _, a := range applied {
+		if len(a.Matched) > 0 {
+			covered++
+		}
+		matched += len(a.Matched)
+		confirmed += a.Confirmed
+	}
+	fmt.Printf("Applied %d rules to %d transactions: %d matched at least one rule\n", len(rules), len(applied), covered)
+	if matched > 0 {
+		fmt.Printf("%d rule matches, %.1f%% with the consequent present\n", matched, 100*float64(confirmed)/float64(matched))
+	}
+	fmt.Printf("Matches written to %s\n", path)
+	if !*evaluate {
+		return nil
+	}
+
+	metrics, coverage := evaluateClassifier(rules, applied, targets)
+	if err := writeClassifierCSV(base+"_classifier.csv", metrics); err != nil {
+		return err
+	}
+	if err := writeRuleCoverageCSV(base+"_rule_coverage.csv", coverage); err != nil {
+		return err
+	}
+	fmt.Printf("\nPredicted %d items for %d targets: %d correct\n", metrics.Predicted, metrics.Targets, metrics.TruePositives)
+	fmt.Printf("Precision %.4f, recall %.4f, F1 %.4f\n", metrics.Precision, metrics.Recall, metrics.F1)
+	fmt.Printf("%.1f%% of transactions covered by a rule, %.1f%% given a prediction\n", 100*metrics.CoveredShare, 100*metrics.PredictedShare)
+	fmt.Printf("Metrics written to %s_classifier.csv and %s_rule_coverage.csv\n", base, base)
+	return nil
+}
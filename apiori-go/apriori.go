@@ -2,10 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ItemSet represents a set of items
@@ -19,10 +25,44 @@ type Dataset []Transaction
 
 // AprioriMiner implements the Apriori algorithm
 type AprioriMiner struct {
-	minSupport     float64
-	dataset        Dataset
-	frequentSets   map[int][]ItemSet
-	transactionLen int
+	minSupport      float64
+	dataset         Dataset
+	frequentSets    map[int][]ItemSet
+	supportCounts   map[string]int
+	transactionLen  int
+	progress        func(LevelProgress)
+	minLift         float64
+	pairCounts      map[string]int
+	multiset        bool
+	outputFilter    *Filter
+	measureDocs     bool
+	candidateHook   func(CandidateStep)
+	memoryLimit     int64
+	memoryPolicy    string
+	memoryNote      string
+	supportSchedule SupportSchedule
+	levelSupports   map[int]float64
+	workers         int
+	mustContain     ItemSet
+	aggregates      []AggregateConstraint
+	attributes      ItemAttributes
+	bloomMinSets    int
+	bloomRejections int
+	releaseLevels   bool
+	released        map[int][]string
+	prunedItems     []PrunedItem
+	warm            *WarmCounts
+	warmPairs       bool
+	warmStats       WarmStartStats
+}
+
+// LevelProgress reports the outcome of counting one level of candidates
+type LevelProgress struct {
+	Level      int `json:"level"`
+	Candidates int `json:"candidates"`
+	Frequent   int `json:"frequent"`
+	// MinSupport is the threshold the level was counted against
+	MinSupport float64 `json:"minSupport"`
 }
 
 // NewAprioriMiner creates a new instance of AprioriMiner
@@ -31,15 +71,74 @@ func NewAprioriMiner(dataset Dataset, minSupport float64) *AprioriMiner {
 		minSupport:     minSupport,
 		dataset:        dataset,
 		frequentSets:   make(map[int][]ItemSet),
+		supportCounts:  make(map[string]int),
 		transactionLen: len(dataset),
 	}
 }
 
-// generateCandidates generates candidate itemsets of size k+1 from frequent itemsets of size k
-func (am *AprioriMiner) generateCandidates(frequentSets []ItemSet, size int) []ItemSet {
+// SetProgressFunc registers a callback invoked after each level is counted
+func (am *AprioriMiner) SetProgressFunc(fn func(LevelProgress)) {
+	am.progress = fn
+}
+
+// SetMinLift requires every pair of items in a frequent itemset to have a
+// lift of at least minLift. The constraint is applied while mining 2-itemsets,
+// so supersets of weakly associated pairs are never generated or counted.
+func (am *AprioriMiner) SetMinLift(minLift float64) {
+	am.minLift = minLift
+}
+
+// SetRecordPairs keeps the counts of every candidate pair, frequent or not,
+// for ItemSimilarity
+func (am *AprioriMiner) SetRecordPairs(record bool) {
+	if record {
+		am.pairCounts = make(map[string]int)
+	} else {
+		am.pairCounts = nil
+	}
+}
+
+// SetMultiset makes the multiplicity of items within a transaction count:
+// the miner then reports itemsets such as {bread×2}, contained in the
+// transactions holding at least two breads. Call it before mining.
+func (am *AprioriMiner) SetMultiset(multiset bool) {
+	if multiset && !am.multiset {
+		am.dataset = ExpandMultiset(am.dataset)
+	}
+	am.multiset = multiset
+}
+
+// SetOutputFilter restricts the itemsets written to the summary file to those
+// matching f. The distribution files still describe every frequent itemset.
+func (am *AprioriMiner) SetOutputFilter(f *Filter) {
+	am.outputFilter = f
+}
+
+// SetMeasureDocs makes the summary file start with comment lines giving the
+// formula of each measure column; see writeMeasureDocs
+func (am *AprioriMiner) SetMeasureDocs(enabled bool) {
+	am.measureDocs = enabled
+}
+
+// generateCandidates generates candidate itemsets of size k+1 from frequent
+// itemsets of size k, returning the context error if ctx ends first
+func (am *AprioriMiner) generateCandidates(ctx context.Context, frequentSets []ItemSet, size int) ([]ItemSet, error) {
+	if am.mustContain != nil {
+		return am.generateConstrainedCandidates(ctx, frequentSets, size)
+	}
 	candidates := make([]ItemSet, 0)
+
+	// The join pairs sets sharing their first size-1 items, and only finds
+	// every such pair when the sets are in lexicographic order
+	sort.Slice(frequentSets, func(i, j int) bool {
+		return itemsetKey(frequentSets[i]) < itemsetKey(frequentSets[j])
+	})
+	filter := am.frequentSetFilter(frequentSets)
 	
 	for i := 0; i < len(frequentSets); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		items1 := sortedItems(frequentSets[i])
 		for j := i + 1; j < len(frequentSets); j++ {
 			items2 := sortedItems(frequentSets[j])
@@ -63,17 +162,29 @@ func (am *AprioriMiner) generateCandidates(frequentSets []ItemSet, size int) []I
 				newSet[items2[size-1]] = true
 				
 				// Add only if all subsets are frequent
-				if am.isValidCandidate(newSet, frequentSets) {
+				if am.multiset && hasRepeatedItem(newSet) {
+					continue
+				}
+				if am.candidateHook != nil {
+					am.candidateHook(CandidateStep{Level: size + 1, Items: sortedItems(newSet), Outcome: OutcomeCreated})
+				}
+				subset := am.infrequentSubset(newSet, frequentSets, filter)
+				if subset == nil {
 					candidates = append(candidates, newSet)
+				} else if am.candidateHook != nil {
+					am.candidateHook(CandidateStep{Level: size + 1, Items: sortedItems(newSet),
+						Outcome: OutcomePruned, PrunedBy: sortedItems(subset)})
 				}
 			}
 		}
 	}
-	return candidates
+	return candidates, nil
 }
 
-// isValidCandidate checks if all subsets of candidate are frequent
-func (am *AprioriMiner) isValidCandidate(candidate ItemSet, frequentSets []ItemSet) bool {
+// infrequentSubset returns the first subset of candidate one item smaller
+// that is not among frequentSets, or nil when they all are. A non-nil filter
+// of frequentSets settles most infrequent subsets before the exact lookup.
+func (am *AprioriMiner) infrequentSubset(candidate ItemSet, frequentSets []ItemSet, filter *bloomFilter) ItemSet {
 	items := sortedItems(candidate)
 	
 	// Generate all subsets of size k-1
@@ -85,6 +196,12 @@ func (am *AprioriMiner) isValidCandidate(candidate ItemSet, frequentSets []ItemS
 			}
 		}
 		
+		// A subset missing from the filter is certainly infrequent
+		if filter != nil && !filter.mayContain(itemsetKey(subset)) {
+			am.bloomRejections++
+			return subset
+		}
+
 		// Check if subset exists in frequent itemsets
 		found := false
 		for _, freqSet := range frequentSets {
@@ -94,73 +211,254 @@ func (am *AprioriMiner) isValidCandidate(candidate ItemSet, frequentSets []ItemS
 			}
 		}
 		if !found {
-			return false
+			return subset
 		}
 	}
-	return true
+	return nil
 }
 
 // calculateSupport calculates support for a candidate itemset
 func (am *AprioriMiner) calculateSupport(candidate ItemSet) float64 {
+	return float64(am.countSupport(candidate)) / float64(am.transactionLen)
+}
+
+// countSupport counts the transactions containing the candidate itemset,
+// reusing the count recorded for frequent itemsets during mining
+func (am *AprioriMiner) countSupport(candidate ItemSet) int {
+	key := itemsetKey(candidate)
+	if count, ok := am.supportCounts[key]; ok {
+		return count
+	}
 	count := 0
 	for _, transaction := range am.dataset {
 		if isSubset(candidate, transaction) {
 			count++
 		}
 	}
-	return float64(count) / float64(am.transactionLen)
+	return count
 }
 
 // Mine performs the Apriori algorithm
 func (am *AprioriMiner) Mine() {
+	am.MineContext(context.Background())
+}
+
+// MineContext performs the Apriori algorithm until it completes or ctx is
+// cancelled. On cancellation the levels finished so far are kept and the
+// context error is returned.
+func (am *AprioriMiner) MineContext(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "mine", trace.WithAttributes(
+		attribute.Int("apriori.transactions", am.transactionLen),
+		attribute.Float64("apriori.min_support", am.minSupport)))
+	defer func() {
+		am.restoreLevels()
+		if am.aggregates != nil {
+			am.filterByAggregates()
+		}
+		span.SetAttributes(attribute.Int("apriori.itemsets", am.getTotalFrequentItemsets()))
+		endSpanError(span, err)
+		span.End()
+	}()
+
 	// Generate frequent 1-itemsets
 	candidates := am.generateInitialCandidates()
 	k := 1
 	
 	for len(candidates) > 0 {
-		frequent := make([]ItemSet, 0)
-		
-		// Calculate support for each candidate
-		for _, candidate := range candidates {
-			support := am.calculateSupport(candidate)
-			if support >= am.minSupport {
-				frequent = append(frequent, candidate)
-			}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if k == 2 && am.minLift > 0 {
+			candidates = am.pruneByLiftBound(candidates)
+		}
+		if am.aggregates != nil {
+			candidates = am.pruneByAggregates(candidates)
+		}
+		levelCtx, levelSpan := tracer.Start(ctx, "level", trace.WithAttributes(
+			attribute.Int("apriori.level", k),
+			attribute.Int("apriori.candidates", len(candidates))))
+		frequent, err := am.countLevel(levelCtx, candidates)
+		if err != nil {
+			endSpanError(levelSpan, err)
+			levelSpan.End()
+			return err
+		}
+		if k == 2 && am.minLift > 0 {
+			frequent = am.filterByLift(frequent)
+		}
+		levelSpan.SetAttributes(attribute.Int("apriori.frequent", len(frequent)))
+		if am.progress != nil {
+			am.progress(LevelProgress{Level: k, Candidates: len(candidates), Frequent: len(frequent),
+				MinSupport: am.levelSupports[k]})
 		}
 		
-		if len(frequent) > 0 {
-			am.frequentSets[k] = frequent
+		// Single items without a required item are only kept for joining
+		stored := frequent
+		if am.mustContain != nil {
+			stored = am.withRequiredItem(frequent)
+		}
+		if len(stored) > 0 {
+			am.frequentSets[k] = stored
+			// A progress callback may have cancelled the run after this level
+			if err := ctx.Err(); err != nil {
+				levelSpan.End()
+				return err
+			}
+			if am.memoryLimit > 0 {
+				if frequent, err = am.fitMemory(frequent, k); frequent == nil {
+					levelSpan.End()
+					return err
+				}
+			}
 			// Generate candidates for next iteration
-			candidates = am.generateCandidates(frequent, k)
+			_, genSpan := tracer.Start(levelCtx, "generate_candidates")
+			candidates, err = am.generateCandidates(ctx, frequent, k)
+			genSpan.SetAttributes(attribute.Int("apriori.candidates", len(candidates)))
+			genSpan.End()
+			if err != nil {
+				levelSpan.End()
+				return err
+			}
+			if am.releaseLevels {
+				am.releaseLevel(k)
+			}
+			levelSpan.End()
 			k++
 		} else {
+			levelSpan.End()
 			break
 		}
 	}
+	return nil
+}
+
+// countLevel counts the support of one level of candidates and returns the
+// frequent ones. On cancellation nothing of the level is kept.
+func (am *AprioriMiner) countLevel(ctx context.Context, candidates []ItemSet) ([]ItemSet, error) {
+	_, span := tracer.Start(ctx, "count_support")
+	defer span.End()
+	frequent := make([]ItemSet, 0)
+	minSupport := am.minSupport
+	if len(candidates) > 0 {
+		minSupport = am.levelMinSupport(len(candidates[0]))
+	}
+	counts := am.warmPairCounts(candidates)
+	if counts == nil {
+		var err error
+		counts, err = am.countCandidates(ctx, candidates)
+		if err != nil {
+			// Drop the unfinished level so only complete levels remain
+			return nil, err
+		}
+		am.recordWarmCounts(candidates, counts)
+	}
+	for i, candidate := range candidates {
+		count := counts[i]
+		if am.pairCounts != nil && len(candidate) == 2 {
+			am.pairCounts[itemsetKey(candidate)] = count
+		}
+		support := float64(count) / float64(am.transactionLen)
+		if am.candidateHook != nil {
+			outcome := OutcomeInfrequent
+			if support >= minSupport {
+				outcome = OutcomeFrequent
+			}
+			am.candidateHook(CandidateStep{Level: len(candidate), Items: sortedItems(candidate),
+				Outcome: outcome, Count: count, Support: support})
+		}
+		if support >= minSupport {
+			am.supportCounts[itemsetKey(candidate)] = count
+			frequent = append(frequent, candidate)
+		}
+	}
+	return frequent, nil
+}
+
+// pairLift returns the lift of a 2-itemset with the given support from the
+// cached supports of its items, along with the larger item support
+func (am *AprioriMiner) pairLift(pair ItemSet, support float64) (float64, float64) {
+	product, largest := 1.0, 0.0
+	for item := range pair {
+		s := am.calculateSupport(ItemSet{item: true})
+		product *= s
+		largest = math.Max(largest, s)
+	}
+	return support / product, largest
+}
+
+// pruneByLiftBound drops candidate pairs that cannot reach the minimum lift
+// before they are counted: a pair's support is at most that of its rarer
+// item, so its lift is at most 1 / the larger item support
+func (am *AprioriMiner) pruneByLiftBound(candidates []ItemSet) []ItemSet {
+	kept := candidates[:0]
+	for _, candidate := range candidates {
+		if _, largest := am.pairLift(candidate, 0); 1/largest >= am.minLift {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// filterByLift drops counted pairs whose lift is below the minimum
+func (am *AprioriMiner) filterByLift(pairs []ItemSet) []ItemSet {
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		if lift, _ := am.pairLift(pair, am.calculateSupport(pair)); lift >= am.minLift {
+			kept = append(kept, pair)
+		}
+	}
+	return kept
 }
 
 // generateInitialCandidates generates 1-itemsets from the dataset
 func (am *AprioriMiner) generateInitialCandidates() []ItemSet {
-	itemCounts := make(map[string]int)
-	
-	// Count occurrences of each item
-	for _, transaction := range am.dataset {
-		for _, item := range transaction {
-			itemCounts[item]++
+	itemCounts := am.warmItemCounts()
+	if itemCounts == nil {
+		itemCounts = make(map[string]int)
+		// Count the transactions containing each item; repeated items are
+		// counted once, as in support counting
+		for _, transaction := range am.dataset {
+			seen := make(map[string]bool, len(transaction))
+			for _, item := range transaction {
+				if !seen[item] {
+					seen[item] = true
+					itemCounts[item]++
+				}
+			}
+		}
+		if am.warm != nil {
+			am.warm.Items = itemCounts
 		}
 	}
 	
 	// Generate candidates meeting minimum support
 	candidates := make([]ItemSet, 0)
+	am.prunedItems = nil
+	minSupport := am.levelMinSupport(1)
 	for item, count := range itemCounts {
 		support := float64(count) / float64(am.transactionLen)
-		if support >= am.minSupport {
+		if am.candidateHook != nil {
+			am.candidateHook(CandidateStep{Level: 1, Items: []string{item}, Outcome: OutcomeCreated})
+		}
+		if support >= minSupport {
 			itemset := make(ItemSet)
 			itemset[item] = true
+			am.supportCounts[item] = count
 			candidates = append(candidates, itemset)
+		} else {
+			am.prunedItems = append(am.prunedItems, PrunedItem{Item: item, Count: count, Support: support})
+			if am.candidateHook != nil {
+				am.candidateHook(CandidateStep{Level: 1, Items: []string{item}, Outcome: OutcomeInfrequent, Count: count, Support: support})
+			}
 		}
 	}
 	
+	// Counting the levels in item order makes every later level and the
+	// output independent of map iteration
+	sort.Slice(candidates, func(i, j int) bool {
+		return itemsetKey(candidates[i]) < itemsetKey(candidates[j])
+	})
+	
 	return candidates
 }
 
@@ -174,6 +472,11 @@ func sortedItems(set ItemSet) []string {
 	return items
 }
 
+// itemsetKey returns a canonical string identifying the itemset
+func itemsetKey(set ItemSet) string {
+	return strings.Join(sortedItems(set), "\x00")
+}
+
 func setsEqual(set1, set2 ItemSet) bool {
 	if len(set1) != len(set2) {
 		return false
@@ -204,40 +507,67 @@ func isSubset(set ItemSet, transaction Transaction) bool {
 
 // TimingMetrics stores timing information for the mining process
 type TimingMetrics struct {
-    DataLoadTime    float64
-    ProcessingTime  float64
-    TotalTime      float64
+    DataLoadTime    float64 `json:"dataLoadTime"`
+    // LoadShards is the number of byte ranges loaded concurrently, 0 or 1
+    // for a sequential load
+    LoadShards      int     `json:"loadShards,omitempty"`
+    ProcessingTime  float64 `json:"processingTime"`
+    TotalTime      float64 `json:"totalTime"`
 }
 
 // OutputResults writes the mining results and timing metrics to CSV files
 func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics) error {
+    return am.OutputResultsTo("results", baseFilename, metrics)
+}
+
+// OutputResultsTo writes the mining results and timing metrics to CSV files in dir
+func (am *AprioriMiner) OutputResultsTo(dir, baseFilename string, metrics TimingMetrics) error {
     // Create a directory for the output if it doesn't exist
-    err := os.MkdirAll("results", 0755)
+    err := os.MkdirAll(dir, 0755)
     if err != nil {
         return fmt.Errorf("failed to create results directory: %v", err)
     }
 
     // Create summary file with all itemsets
-    summaryFile, err := os.Create(fmt.Sprintf("results/%s_summary.csv", baseFilename))
+    summaryFile, err := os.Create(filepath.Join(dir, baseFilename+"_summary.csv"))
     if err != nil {
         return fmt.Errorf("failed to create summary file: %v", err)
     }
     defer summaryFile.Close()
 
     // Write summary header
-    summaryFile.WriteString("Size,Items,Support\n")
+    summaryColumns := []string{"Size", "Items", "Count", "Support", "AllConfidence", "Kulczynski", "Cosine"}
+    if am.measureDocs {
+        writeMeasureDocs(summaryFile, summaryColumns)
+    }
+    summaryFile.WriteString(strings.Join(summaryColumns, ",") + "\n")
 
     // Write each itemset to the summary file
-    for k, itemsets := range am.frequentSets {
+    for _, k := range am.levels() {
+        itemsets := am.frequentSets[k]
         for _, itemset := range itemsets {
             items := strings.Join(sortedItems(itemset), ",")
+            count := am.countSupport(itemset)
             support := am.calculateSupport(itemset)
-            summaryFile.WriteString(fmt.Sprintf("%d,\"%s\",%f\n", k, items, support))
+            allConfidence, kulczynski, cosine := am.itemsetMeasures(itemset, support)
+            if am.outputFilter != nil {
+                match, err := am.outputFilter.MatchItemset(ItemsetResult{
+                    Size: k, Items: sortedItems(itemset), Count: count, Support: support,
+                    AllConfidence: allConfidence, Kulczynski: kulczynski, Cosine: cosine,
+                })
+                if err != nil {
+                    return err
+                }
+                if !match {
+                    continue
+                }
+            }
+            summaryFile.WriteString(fmt.Sprintf("%d,\"%s\",%d,%f,%f,%f,%f\n", k, items, count, support, allConfidence, kulczynski, cosine))
         }
     }
 
     // Create size distribution file
-    sizeFile, err := os.Create(fmt.Sprintf("results/%s_size_distribution.csv", baseFilename))
+    sizeFile, err := os.Create(filepath.Join(dir, baseFilename+"_size_distribution.csv"))
     if err != nil {
         return fmt.Errorf("failed to create size distribution file: %v", err)
     }
@@ -247,12 +577,13 @@ func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics
     sizeFile.WriteString("Size,Count\n")
 
     // Write size distribution data
-    for k, itemsets := range am.frequentSets {
+    for _, k := range am.levels() {
+        itemsets := am.frequentSets[k]
         sizeFile.WriteString(fmt.Sprintf("%d,%d\n", k, len(itemsets)))
     }
 
     // Create support distribution file
-    supportFile, err := os.Create(fmt.Sprintf("results/%s_support_distribution.csv", baseFilename))
+    supportFile, err := os.Create(filepath.Join(dir, baseFilename+"_support_distribution.csv"))
     if err != nil {
         return fmt.Errorf("failed to create support distribution file: %v", err)
     }
@@ -262,7 +593,8 @@ func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics
     supportFile.WriteString("ItemsetSize,Items,Support\n")
 
     // Write support distribution data
-    for k, itemsets := range am.frequentSets {
+    for _, k := range am.levels() {
+        itemsets := am.frequentSets[k]
         for _, itemset := range itemsets {
             items := strings.Join(sortedItems(itemset), ",")
             support := am.calculateSupport(itemset)
@@ -271,7 +603,7 @@ func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics
     }
 
     // Create performance metrics file
-    perfFile, err := os.Create(fmt.Sprintf("results/%s_performance.csv", baseFilename))
+    perfFile, err := os.Create(filepath.Join(dir, baseFilename+"_performance.csv"))
     if err != nil {
         return fmt.Errorf("failed to create performance file: %v", err)
     }
@@ -282,6 +614,9 @@ func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics
     
     // Write timing metrics
     perfFile.WriteString(fmt.Sprintf("Data Loading,%f\n", metrics.DataLoadTime))
+    if metrics.LoadShards > 1 {
+        perfFile.WriteString(fmt.Sprintf("Load Shards,%d\n", metrics.LoadShards))
+    }
     perfFile.WriteString(fmt.Sprintf("Processing,%f\n", metrics.ProcessingTime))
     perfFile.WriteString(fmt.Sprintf("Total,%f\n", metrics.TotalTime))
     
@@ -292,6 +627,16 @@ func (am *AprioriMiner) OutputResults(baseFilename string, metrics TimingMetrics
     return nil
 }
 
+// levels returns the sizes of the frequent itemsets found, ascending
+func (am *AprioriMiner) levels() []int {
+    levels := make([]int, 0, len(am.frequentSets))
+    for k := range am.frequentSets {
+        levels = append(levels, k)
+    }
+    sort.Ints(levels)
+    return levels
+}
+
 // getTotalFrequentItemsets returns the total number of frequent itemsets found
 func (am *AprioriMiner) getTotalFrequentItemsets() int {
     total := 0
@@ -301,9 +646,9 @@ func (am *AprioriMiner) getTotalFrequentItemsets() int {
     return total
 }
 
-// LoadDataset loads transactions from a file
+// LoadDataset loads transactions from a UTF-8 file
 func LoadDataset(filename string) (Dataset, error) {
-	file, err := os.Open(filename)
+	file, err := openInput(filename, "")
 	if err != nil {
 		return nil, err
 	}
@@ -322,4 +667,13 @@ func LoadDataset(filename string) (Dataset, error) {
 	}
 
 	return dataset, nil
-}
\ No newline at end of file
+}
+
+// writeFileAtomic replaces path with data so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// The join only pairs two sets when the first's last item sorts before the
+// second's, so a level that comes out of counting unsorted must still yield
+// every candidate
+func TestGenerateCandidatesUnsortedLevel(t *testing.T) {
+	miner := NewAprioriMiner(nil, 0.5)
+	level := []ItemSet{
+		{"a": true, "c": true},
+		{"a": true, "b": true},
+		{"b": true, "c": true},
+	}
+	candidates, err := miner.generateCandidates(context.Background(), level, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || itemsetKey(candidates[0]) != itemsetKey(ItemSet{"a": true, "b": true, "c": true}) {
+		t.Fatalf("candidates = %v, want only {a, b, c}", candidates)
+	}
+}
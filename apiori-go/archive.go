@@ -0,0 +1,111 @@
+//go:build !js
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// runLog collects what a run prints to standard output and through the log
+// package for its archive
+type runLog struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *runLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Write(p)
+}
+
+// Bytes returns the log collected so far
+func (l *runLog) Bytes() []byte {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return bytes.Clone(l.buf.Bytes())
+}
+
+// captureStdout copies everything printed to standard output into w as well,
+// until the returned function restores it once the copy is complete
+func captureStdout(w io.Writer) (func(), error) {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout := os.Stdout
+	os.Stdout = pw
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, w), r)
+		close(done)
+	}()
+	return func() {
+		os.Stdout = stdout
+		pw.Close()
+		<-done
+		r.Close()
+	}, nil
+}
+
+// runResultFiles returns the names of the result files of base present in
+// dir, in the order of resultFileSuffixes
+func runResultFiles(dir, base string) []string {
+	var files []string
+	for _, suffix := range resultFileSuffixes {
+		name := base + suffix
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// removeRunResults deletes the result files of base in dir
+func removeRunResults(dir, base string) error {
+	for _, name := range runResultFiles(dir, base) {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRunArchive zips the result files of base in dir, manifest included,
+// with the run's log as <base>.log into dir/<runID>.zip, replacing it
+// atomically, and returns its path
+func writeRunArchive(dir, base, runID string, runLog []byte) (string, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	for _, name := range runResultFiles(dir, base) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		if err := add(name, data); err != nil {
+			return "", err
+		}
+	}
+	if err := add(base+".log", runLog); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, runID+".zip")
+	return path, writeFileAtomic(path, buf.Bytes())
+}
@@ -0,0 +1,51 @@
+//go:build !js
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveRunResultsOnlyRemovesTheBasename(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"s_summary.csv", "s_bootstrap.csv", "s-r1_summary.csv", "s_notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := removeRunResults(dir, "s"); err != nil {
+		t.Fatal(err)
+	}
+	if files := runResultFiles(dir, "s"); len(files) != 0 {
+		t.Errorf("result files left behind: %v", files)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, entry := range entries {
+		kept = append(kept, entry.Name())
+	}
+	if want := []string{"s-r1_summary.csv", "s_notes.txt"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+}
+
+func TestCaptureStdoutKeepsTheRunsOutput(t *testing.T) {
+	var l runLog
+	restore, err := captureStdout(&l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("Frequent Itemsets:")
+	restore()
+	fmt.Println("printed after the archive")
+	if got := string(l.Bytes()); got != "Frequent Itemsets:\n" {
+		t.Errorf("captured %q", got)
+	}
+}
@@ -0,0 +1,284 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// API key scopes. Read covers all queries, submit covers uploading datasets
+// and starting jobs, admin covers namespace management.
+const (
+	ScopeRead   = "read"
+	ScopeSubmit = "submit"
+	ScopeAdmin  = "admin"
+)
+
+// APIKey is one entry of the API keys file
+type APIKey struct {
+	Name   string   `json:"name"`
+	Key    string   `json:"key"`
+	Scopes []string `json:"scopes"`
+	// Namespaces restricts the key to the listed namespaces; empty allows all
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// hasScope reports whether the key grants scope; admin implies every scope
+// and submit implies read
+func (k *APIKey) hasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin || (s == ScopeSubmit && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsNamespace reports whether the key may access ns
+func (k *APIKey) allowsNamespace(ns string) bool {
+	if len(k.Namespaces) == 0 {
+		return true
+	}
+	for _, allowed := range k.Namespaces {
+		if allowed == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyAuth authenticates requests against a set of API keys
+type apiKeyAuth struct {
+	// keys maps the SHA-256 digest of each key to its entry so lookups do
+	// not compare secrets byte by byte
+	keys map[[sha256.Size]byte]*APIKey
+}
+
+type apiKeyContextKey struct{}
+
+// apiKeyFromContext returns the authenticated key of a request, if any
+func apiKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
+
+// loadAPIKeys reads a JSON file of the form {"keys": [APIKey, ...]}
+func loadAPIKeys(path string) (*apiKeyAuth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Keys []*APIKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys: %v", err)
+	}
+	auth := &apiKeyAuth{keys: make(map[[sha256.Size]byte]*APIKey)}
+	for _, key := range file.Keys {
+		if key.Key == "" || len(key.Scopes) == 0 {
+			return nil, fmt.Errorf("API key %q needs a key and at least one scope", key.Name)
+		}
+		for _, scope := range key.Scopes {
+			if scope != ScopeRead && scope != ScopeSubmit && scope != ScopeAdmin {
+				return nil, fmt.Errorf("API key %q has unknown scope %q", key.Name, scope)
+			}
+		}
+		auth.keys[sha256.Sum256([]byte(key.Key))] = key
+	}
+	if len(auth.keys) == 0 {
+		return nil, fmt.Errorf("no API keys defined in %s", path)
+	}
+	return auth, nil
+}
+
+// lookup returns the entry for a presented key
+func (a *apiKeyAuth) lookup(presented string) *APIKey {
+	if presented == "" {
+		return nil
+	}
+	return a.keys[sha256.Sum256([]byte(presented))]
+}
+
+// authorize checks that key may perform an operation needing scope in ns
+func authorize(key *APIKey, scope, ns string) error {
+	if key == nil {
+		return errUnauthenticated
+	}
+	if !key.hasScope(scope) {
+		return fmt.Errorf("API key %q lacks the %s scope", key.Name, scope)
+	}
+	if ns != "" && !key.allowsNamespace(ns) {
+		return fmt.Errorf("API key %q may not access namespace %s", key.Name, ns)
+	}
+	return nil
+}
+
+var errUnauthenticated = fmt.Errorf("missing or invalid API key")
+
+// presentedKey extracts an API key from an Authorization bearer token or X-API-Key header
+func presentedKey(authorization, apiKey string) string {
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(apiKey)
+}
+
+// requestScope returns the scope an HTTP request needs and the namespace it targets
+func requestScope(r *http.Request) (string, string) {
+	ns := ""
+	path := r.URL.Path
+	if rest, ok := strings.CutPrefix(path, "/namespaces/"); ok {
+		ns, rest, _ = strings.Cut(rest, "/")
+		if rest == "" && r.Method == http.MethodPut {
+			return ScopeAdmin, ns
+		}
+	} else if strings.HasPrefix(path, "/datasets") || strings.HasPrefix(path, "/jobs") {
+		ns = DefaultNamespace
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return ScopeRead, ns
+	}
+	return ScopeSubmit, ns
+}
+
+// Middleware rejects HTTP requests without a key granting the needed scope.
+// Health probes and the API description stay unauthenticated so
+// orchestrators and integrators can reach them.
+func (a *apiKeyAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" || r.URL.Path == "/openapi.json" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := a.lookup(presentedKey(r.Header.Get("Authorization"), r.Header.Get("X-API-Key")))
+		scope, ns := requestScope(r)
+		if err := authorize(key, scope, ns); err != nil {
+			if err == errUnauthenticated {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, err.Error())
+			} else {
+				writeError(w, http.StatusForbidden, err.Error())
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	})
+}
+
+// grpcMethodScopes lists the scope each gRPC method requires
+var grpcMethodScopes = map[string]string{
+	"UploadDataset":      ScopeSubmit,
+	"RegisterDataset":    ScopeSubmit,
+	"SubmitJob":          ScopeSubmit,
+	"IngestTransactions": ScopeSubmit,
+	"ListDatasets":       ScopeRead,
+	"GetJob":             ScopeRead,
+	"ListJobs":           ScopeRead,
+	"StreamItemsets":     ScopeRead,
+	"ListNamespaces":     ScopeRead,
+	// Arrow Flight; namespaces are checked against the ticket
+	"Handshake":     ScopeRead,
+	"ListFlights":   ScopeRead,
+	"GetFlightInfo": ScopeRead,
+	"GetSchema":     ScopeRead,
+	"DoGet":         ScopeRead,
+}
+
+// namespacedRequest is implemented by gRPC requests addressing a namespace
+type namespacedRequest interface {
+	GetNamespace() string
+}
+
+// grpcKey authenticates the key carried in the call's metadata
+func (a *apiKeyAuth) grpcKey(ctx context.Context) *APIKey {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(name string) string {
+		if values := md.Get(name); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	return a.lookup(presentedKey(first("authorization"), first("x-api-key")))
+}
+
+// authorizeGRPC checks a gRPC call for method with request message req
+func (a *apiKeyAuth) authorizeGRPC(ctx context.Context, fullMethod string, req interface{}) (*APIKey, error) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	scope, ok := grpcMethodScopes[method]
+	if !ok {
+		scope = ScopeAdmin
+	}
+	ns := ""
+	if nr, ok := req.(namespacedRequest); ok {
+		ns = grpcNamespace(nr.GetNamespace())
+	}
+	key := a.grpcKey(ctx)
+	if err := authorize(key, scope, ns); err != nil {
+		if err == errUnauthenticated {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return key, nil
+}
+
+// UnaryInterceptor authorizes unary gRPC calls
+func (a *apiKeyAuth) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	key, err := a.authorizeGRPC(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, apiKeyContextKey{}, key), req)
+}
+
+// StreamInterceptor authorizes streaming gRPC calls once their first request
+// message, which carries the namespace, has been received
+func (a *apiKeyAuth) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if a.grpcKey(ss.Context()) == nil {
+		return status.Error(codes.Unauthenticated, errUnauthenticated.Error())
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, auth: a, method: info.FullMethod})
+}
+
+// authorizedStream checks authorization against the first received message
+type authorizedStream struct {
+	grpc.ServerStream
+	auth       *apiKeyAuth
+	method     string
+	authorized bool
+	ctx        context.Context
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		key, err := s.auth.authorizeGRPC(s.ServerStream.Context(), s.method, m)
+		if err != nil {
+			return err
+		}
+		s.authorized = true
+		s.ctx = context.WithValue(s.ServerStream.Context(), apiKeyContextKey{}, key)
+	}
+	return nil
+}
+
+func (s *authorizedStream) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return s.ServerStream.Context()
+}
@@ -0,0 +1,188 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// autotuneProbe is the itemset count found on the sample at one threshold
+type autotuneProbe struct {
+	MinSupport float64
+	Itemsets   int
+	// Truncated is set when mining was stopped after exceeding the limit or
+	// the time allowed, so Itemsets is only a lower bound
+	Truncated bool
+	Seconds   float64
+}
+
+// probeThreshold mines sample at minSupport, giving up once more than limit
+// itemsets have been found or timeout has passed
+func probeThreshold(sample Dataset, minSupport float64, limit int, timeout time.Duration) autotuneProbe {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	probe := autotuneProbe{MinSupport: minSupport}
+	miner := NewAprioriMiner(sample, minSupport)
+	miner.SetProgressFunc(func(p LevelProgress) {
+		probe.Itemsets += p.Frequent
+		if probe.Itemsets > limit {
+			probe.Truncated = true
+			cancel()
+		}
+	})
+	if miner.MineContext(ctx) != nil {
+		probe.Truncated = true
+	}
+	probe.Seconds = time.Since(start).Seconds()
+	return probe
+}
+
+// autotuneSupport searches for the threshold at which sample has about
+// target frequent itemsets. It descends the item support curve, doubling the
+// number of frequent items with each probe so that an overshoot stays cheap,
+// until a probe finds more than target itemsets, and then bisects between the
+// last two thresholds on a log scale.
+func autotuneSupport(sample Dataset, curve []float64, target, probes int, timeout time.Duration) []autotuneProbe {
+	n := float64(len(sample))
+	// The count is at most target at hi and above it at lo once lo is set
+	hi, lo := curve[0], 0.0
+	next := 2
+	var out []autotuneProbe
+	for len(out) < probes {
+		var t float64
+		if lo == 0 {
+			for next <= len(curve) && curve[next-1] >= hi {
+				next *= 2
+			}
+			if next <= len(curve) {
+				t = curve[next-1]
+			} else {
+				t = hi / 2
+			}
+		} else {
+			t = math.Sqrt(lo * hi)
+		}
+		probe := probeThreshold(sample, t, 10*target, timeout)
+		out = append(out, probe)
+		if probe.Truncated || probe.Itemsets > target {
+			lo = t
+		} else {
+			hi = t
+		}
+		// Thresholds closer than one transaction find the same itemsets
+		if (lo > 0 && (hi-lo)*n < 1) || (lo == 0 && hi*n < 1) {
+			break
+		}
+	}
+	return out
+}
+
+// bestProbe returns the probe whose count is closest to target by ratio,
+// preferring complete probes
+func bestProbe(probes []autotuneProbe, target int) autotuneProbe {
+	best := probes[0]
+	distance := func(p autotuneProbe) float64 {
+		d := math.Abs(math.Log(float64(p.Itemsets+1) / float64(target+1)))
+		if p.Truncated {
+			d += math.Inf(1)
+		}
+		return d
+	}
+	for _, p := range probes[1:] {
+		if distance(p) < distance(best) {
+			best = p
+		}
+	}
+	return best
+}
+
+// writeAutotuneCSV writes the probes in the order they were made
+func writeAutotuneCSV(path string, probes []autotuneProbe) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Probe,MinSupport,Itemsets,Truncated,Seconds")
+	for i, p := range probes {
+		fmt.Fprintf(f, "%d,%f,%d,%t,%f\n", i+1, p.MinSupport, p.Itemsets, p.Truncated, p.Seconds)
+	}
+	return f.Close()
+}
+
+// runAutotune implements the autotune subcommand, which recommends a minimum
+// support giving about a target number of frequent itemsets by mining a
+// sample of the dataset at a few thresholds
+func runAutotune(args []string) error {
+	fs := flag.NewFlagSet("autotune", flag.ExitOnError)
+	target := fs.Int("target", 1000, "approximate number of frequent itemsets wanted")
+	sampleSize := fs.Int("sample", 2000, "transactions sampled for the probes (0 uses them all)")
+	probes := fs.Int("probes", 8, "thresholds probed on the sample")
+	seed := fs.Int64("seed", 1, "seed of the sampling")
+	probeTimeout := fs.Duration("probe-timeout", 10*time.Second, "time allowed per probe before its threshold counts as too low")
+	check := fs.Bool("check", false, "mine the full dataset at the recommended threshold and report the actual count")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_autotune.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: autotune [flags] <dataset>")
+	}
+	if *target < 1 {
+		return fmt.Errorf("target must be at least 1")
+	}
+	if *probes < 1 {
+		return fmt.Errorf("probes must be at least 1")
+	}
+	if *probeTimeout <= 0 {
+		return fmt.Errorf("probe-timeout must be positive")
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	if len(dataset) == 0 {
+		return fmt.Errorf("%s has no transactions", filename)
+	}
+	sample := dataset
+	if *sampleSize > 0 && *sampleSize < len(dataset) {
+		_, sample = splitDataset(dataset, float64(*sampleSize)/float64(len(dataset)), *seed)
+	}
+	fmt.Printf("%d transactions, %d items; probing a sample of %d\n", len(dataset), len(distinctItems(dataset)), len(sample))
+
+	tried := autotuneSupport(sample, itemSupportCurve(sample), *target, *probes, *probeTimeout)
+	for _, p := range tried {
+		bound := ""
+		if p.Truncated {
+			bound = "more than "
+		}
+		fmt.Printf("minsupport %.4f: %s%d itemsets on the sample (%.3f seconds)\n", p.MinSupport, bound, p.Itemsets, p.Seconds)
+	}
+	best := bestProbe(tried, *target)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_autotune.csv")
+	if err := writeAutotuneCSV(path, tried); err != nil {
+		return err
+	}
+	fmt.Printf("Recommended minimum support: %.4f (about %d itemsets)\n", best.MinSupport, best.Itemsets)
+	if reliable := MinReliableSupport(len(dataset), 0.95, 0.5); best.MinSupport < reliable {
+		fmt.Printf("Warning: it is below the minimum reliable support %.4f\n", reliable)
+	}
+	if *check {
+		miner := NewAprioriMiner(dataset, best.MinSupport)
+		miner.Mine()
+		fmt.Printf("Full dataset at %.4f: %d itemsets\n", best.MinSupport, miner.getTotalFrequentItemsets())
+	}
+	fmt.Printf("Probes written to %s\n", path)
+	return nil
+}
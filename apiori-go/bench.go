@@ -0,0 +1,132 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// TidsetBenchmark is the cost of one tidset representation on a dataset
+type TidsetBenchmark struct {
+	Representation string
+	// IndexBytes is the size of the transaction lists of the frequent items
+	IndexBytes uint64
+	// Intersections is the number of pairwise intersections timed
+	Intersections  int
+	IntersectNanos float64
+	MineSeconds    float64
+	Itemsets       int
+}
+
+// benchmarkTidsets times the pairwise intersections of the frequent items'
+// tidsets, repeat times over, and a full Eclat run in representation T
+func benchmarkTidsets[T tidset[T]](name string, dataset Dataset, minSupport float64, repeat int,
+	convert func([]int32) T, size func(T) uint64, mine MiningAlgorithm) TidsetBenchmark {
+	b := TidsetBenchmark{Representation: name}
+	tidlists := buildTidlists(dataset)
+	items := make([]string, 0, len(tidlists))
+	for item, tids := range tidlists {
+		if float64(len(tids))/float64(len(dataset)) >= minSupport {
+			items = append(items, item)
+		}
+	}
+	sort.Strings(items)
+	sets := make([]T, len(items))
+	for i, item := range items {
+		sets[i] = convert(tidlists[item])
+		b.IndexBytes += size(sets[i])
+	}
+
+	start := time.Now()
+	// The sum of cardinalities keeps the intersections from being optimised away
+	total := 0
+	for r := 0; r < repeat; r++ {
+		for i := range sets {
+			for j := i + 1; j < len(sets); j++ {
+				total += sets[i].and(sets[j]).cardinality()
+				b.Intersections++
+			}
+		}
+	}
+	if b.Intersections > 0 && total >= 0 {
+		b.IntersectNanos = float64(time.Since(start).Nanoseconds()) / float64(b.Intersections)
+	}
+
+	start = time.Now()
+	b.Itemsets = len(mine(dataset, minSupport))
+	b.MineSeconds = time.Since(start).Seconds()
+	return b
+}
+
+// writeBenchmarkCSV writes one line per representation
+func writeBenchmarkCSV(path string, benchmarks []TidsetBenchmark) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Representation,IndexBytes,Intersections,IntersectNanos,MineSeconds,Itemsets")
+	for _, b := range benchmarks {
+		fmt.Fprintf(f, "%s,%d,%d,%f,%f,%d\n", b.Representation, b.IndexBytes, b.Intersections, b.IntersectNanos, b.MineSeconds, b.Itemsets)
+	}
+	return f.Close()
+}
+
+// runBench implements the bench subcommand, which compares the roaring
+// bitmap tidsets of the vertical index with plain sorted-slice intersection
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support of the items intersected and of the Eclat runs")
+	repeat := fs.Int("repeat", 3, "rounds of pairwise intersections timed")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_bench.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bench [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if *repeat < 1 {
+		return fmt.Errorf("repeat must be at least 1")
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	if len(dataset) == 0 {
+		return fmt.Errorf("%s holds no transactions", filename)
+	}
+	benchmarks := []TidsetBenchmark{
+		benchmarkTidsets("sorted-slice", dataset, *minSupport, *repeat,
+			func(tids []int32) sliceTids { return tids },
+			func(t sliceTids) uint64 { return uint64(4 * len(t)) }, MineEclat),
+		benchmarkTidsets("roaring", dataset, *minSupport, *repeat, newRoaringTids,
+			func(t roaringTids) uint64 { return t.GetSizeInBytes() }, MineEclatRoaring),
+	}
+
+	fmt.Printf("%-13s %12s %14s %14s %10s %9s\n", "tidsets", "index", "intersections", "ns/intersect", "mine (s)", "itemsets")
+	for _, b := range benchmarks {
+		fmt.Printf("%-13s %12s %14d %14.0f %10.3f %9d\n", b.Representation, formatBytes(int64(b.IndexBytes)), b.Intersections,
+			b.IntersectNanos, b.MineSeconds, b.Itemsets)
+	}
+	if benchmarks[0].Itemsets != benchmarks[1].Itemsets {
+		return fmt.Errorf("the representations disagree: %d and %d itemsets", benchmarks[0].Itemsets, benchmarks[1].Itemsets)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_bench.csv")
+	if err := writeBenchmarkCSV(path, benchmarks); err != nil {
+		return err
+	}
+	fmt.Printf("Benchmark written to %s\n", path)
+	return nil
+}
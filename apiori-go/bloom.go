@@ -0,0 +1,76 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFalsePositiveRate is the share of infrequent subsets the prescreen
+// lets through to the exact lookup
+const bloomFalsePositiveRate = 0.01
+
+// bloomFilter is a set of strings answering membership with no false
+// negatives and false positives at about the rate it was sized for
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64
+	hashes int
+}
+
+// newBloomFilter sizes a filter for n strings at falsePositiveRate
+func newBloomFilter(n int, falsePositiveRate float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(max(n, 1)) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	hashes := max(1, int(math.Round(float64(m)/float64(max(n, 1))*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, hashes: hashes}
+}
+
+// locations derives the bit positions of s from two halves of one 64-bit
+// hash (Kirsch and Mitzenmacher)
+func (b *bloomFilter) locations(s string, fn func(uint64)) {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	sum := h.Sum64()
+	h1, h2 := sum&0xffffffff, sum>>32|1
+	for i := 0; i < b.hashes; i++ {
+		fn((h1 + uint64(i)*h2) % b.m)
+	}
+}
+
+func (b *bloomFilter) add(s string) {
+	b.locations(s, func(bit uint64) { b.bits[bit/64] |= 1 << (bit % 64) })
+}
+
+// mayContain reports false only for strings never added
+func (b *bloomFilter) mayContain(s string) bool {
+	found := true
+	b.locations(s, func(bit uint64) { found = found && b.bits[bit/64]&(1<<(bit%64)) != 0 })
+	return found
+}
+
+// SetBloomPrescreen makes subset pruning of levels generated from at least
+// minSets frequent itemsets check a Bloom filter of them first, rejecting
+// most candidates with an infrequent subset without the exact lookup
+// through the level (0 disables)
+func (am *AprioriMiner) SetBloomPrescreen(minSets int) {
+	am.bloomMinSets = minSets
+}
+
+// BloomRejections returns the number of subsets the prescreen found
+// infrequent without the exact lookup
+func (am *AprioriMiner) BloomRejections() int {
+	return am.bloomRejections
+}
+
+// frequentSetFilter returns a Bloom filter of the keys of frequentSets when
+// the prescreen applies to a level of that many, or nil
+func (am *AprioriMiner) frequentSetFilter(frequentSets []ItemSet) *bloomFilter {
+	if am.bloomMinSets <= 0 || len(frequentSets) < am.bloomMinSets {
+		return nil
+	}
+	filter := newBloomFilter(len(frequentSets), bloomFalsePositiveRate)
+	for _, set := range frequentSets {
+		filter.add(itemsetKey(set))
+	}
+	return filter
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Interval is a bootstrap confidence interval
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// bootstrapCounter resamples the transactions of a dataset and records, for
+// every replicate, how many sampled transactions contain each pattern
+type bootstrapCounter struct {
+	n        int
+	patterns map[string]int
+	tidlists [][]int32
+}
+
+func newBootstrapCounter(dataset Dataset) *bootstrapCounter {
+	return &bootstrapCounter{n: len(dataset), patterns: make(map[string]int)}
+}
+
+// add registers a pattern and returns its index
+func (b *bootstrapCounter) add(items []string) int {
+	key := strings.Join(items, "\x00")
+	if idx, ok := b.patterns[key]; ok {
+		return idx
+	}
+	b.patterns[key] = len(b.patterns)
+	b.tidlists = append(b.tidlists, nil)
+	return len(b.patterns) - 1
+}
+
+// index builds the transaction IDs containing each registered pattern by
+// intersecting per-item bitsets
+func (b *bootstrapCounter) index(dataset Dataset) {
+	words := (b.n + 63) / 64
+	itemBits := make(map[string][]uint64)
+	for tid, transaction := range dataset {
+		for _, item := range transaction {
+			set := itemBits[item]
+			if set == nil {
+				set = make([]uint64, words)
+				itemBits[item] = set
+			}
+			set[tid/64] |= 1 << (tid % 64)
+		}
+	}
+	acc := make([]uint64, words)
+	for key, idx := range b.patterns {
+		for w := range acc {
+			acc[w] = math.MaxUint64
+		}
+		for _, item := range strings.Split(key, "\x00") {
+			set := itemBits[item]
+			for w := range acc {
+				if set == nil {
+					acc[w] = 0
+				} else {
+					acc[w] &= set[w]
+				}
+			}
+		}
+		var tids []int32
+		for w, word := range acc {
+			for word != 0 {
+				tid := w*64 + bits.TrailingZeros64(word)
+				if tid < b.n {
+					tids = append(tids, int32(tid))
+				}
+				word &= word - 1
+			}
+		}
+		b.tidlists[idx] = tids
+	}
+}
+
+// run draws replicates resamples of the transactions with replacement and
+// returns counts[pattern][replicate]
+func (b *bootstrapCounter) run(replicates int, seed int64) [][]int32 {
+	rng := rand.New(rand.NewSource(seed))
+	counts := make([][]int32, len(b.tidlists))
+	for i := range counts {
+		counts[i] = make([]int32, replicates)
+	}
+	weights := make([]int32, b.n)
+	for r := 0; r < replicates; r++ {
+		// A resample is summarised by how often each transaction was drawn
+		for i := range weights {
+			weights[i] = 0
+		}
+		for i := 0; i < b.n; i++ {
+			weights[rng.Intn(b.n)]++
+		}
+		for p, tids := range b.tidlists {
+			var sum int32
+			for _, tid := range tids {
+				sum += weights[tid]
+			}
+			counts[p][r] = sum
+		}
+	}
+	return counts
+}
+
+// percentileInterval returns the central level interval of values
+func percentileInterval(values []float64, level float64) *Interval {
+	sort.Float64s(values)
+	at := func(q float64) float64 {
+		pos := q * float64(len(values)-1)
+		lo := int(math.Floor(pos))
+		hi := int(math.Ceil(pos))
+		return values[lo] + (values[hi]-values[lo])*(pos-float64(lo))
+	}
+	alpha := (1 - level) / 2
+	return &Interval{Low: at(alpha), High: at(1 - alpha)}
+}
+
+// BootstrapIntervals attaches percentile bootstrap intervals at the given
+// level to the support of every itemset and to the support and confidence of
+// every rule, from replicates resamples of the dataset's transactions.
+// Patterns driven by a handful of transactions get wide intervals.
+func BootstrapIntervals(dataset Dataset, results []ItemsetResult, rules []Rule, replicates int, level float64, seed int64) {
+	if len(dataset) == 0 || replicates < 1 {
+		return
+	}
+	counter := newBootstrapCounter(dataset)
+	itemsetIdx := make([]int, len(results))
+	for i, r := range results {
+		itemsetIdx[i] = counter.add(r.Items)
+	}
+	type ruleIdx struct{ antecedent, both int }
+	ruleIdxs := make([]ruleIdx, len(rules))
+	for i, r := range rules {
+		both := append(append([]string{}, r.Antecedent...), r.Consequent...)
+		sort.Strings(both)
+		ruleIdxs[i] = ruleIdx{antecedent: counter.add(r.Antecedent), both: counter.add(both)}
+	}
+	counter.index(dataset)
+	counts := counter.run(replicates, seed)
+
+	n := float64(len(dataset))
+	values := make([]float64, replicates)
+	supportInterval := func(p int) *Interval {
+		for r, c := range counts[p] {
+			values[r] = float64(c) / n
+		}
+		return percentileInterval(values, level)
+	}
+	for i := range results {
+		results[i].SupportCI = supportInterval(itemsetIdx[i])
+	}
+	for i := range rules {
+		idx := ruleIdxs[i]
+		rules[i].SupportCI = supportInterval(idx.both)
+		for r := range values {
+			if a := counts[idx.antecedent][r]; a > 0 {
+				values[r] = float64(counts[idx.both][r]) / float64(a)
+			} else {
+				values[r] = 0
+			}
+		}
+		rules[i].ConfidenceCI = percentileInterval(values, level)
+	}
+}
+
+// writeBootstrapCSV writes the support intervals of itemsets to path
+func writeBootstrapCSV(path string, results []ItemsetResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Size,Items,Support,SupportLow,SupportHigh")
+	for _, r := range results {
+		if r.SupportCI == nil {
+			continue
+		}
+		fmt.Fprintf(f, "%d,\"%s\",%f,%f,%f\n", r.Size, strings.Join(r.Items, ","), r.Support, r.SupportCI.Low, r.SupportCI.High)
+	}
+	return f.Close()
+}
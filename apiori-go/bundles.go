@@ -0,0 +1,182 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bundle is a frequent itemset proposed as a product bundle
+type Bundle struct {
+	ItemsetResult
+	// Margin is the summed margin of the items, or the number of items when
+	// no prices were given
+	Margin float64 `json:"margin"`
+	// Score is Support × Margin, the expected margin per transaction
+	Score float64 `json:"score"`
+}
+
+// LoadItemMargins reads a price list with one "item price [cost]" line per
+// item and returns the margin of each item, its price less its cost. Blank
+// lines and lines starting with # are ignored.
+func LoadItemMargins(filename string) (map[string]float64, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	margins := make(map[string]float64)
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 3 || len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"item price [cost]\"", filename, i+1)
+		}
+		margin, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid price %q", filename, i+1, fields[1])
+		}
+		if len(fields) == 3 {
+			cost, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid cost %q", filename, i+1, fields[2])
+			}
+			margin -= cost
+		}
+		margins[fields[0]] = margin
+	}
+	return margins, nil
+}
+
+// suggestBundles scores the itemsets of minSize to maxSize items with an
+// all-confidence of at least minAllConfidence and greedily picks the best
+// scoring ones that share no item with a bundle already picked. Items
+// missing from a non-nil margins map make an itemset ineligible.
+func suggestBundles(results []ItemsetResult, margins map[string]float64, minSize, maxSize int, minAllConfidence float64) []Bundle {
+	var candidates []Bundle
+	for _, r := range results {
+		if r.Size < minSize || (maxSize > 0 && r.Size > maxSize) || r.AllConfidence < minAllConfidence {
+			continue
+		}
+		margin, priced := 0.0, true
+		for _, item := range r.Items {
+			if margins == nil {
+				margin++
+				continue
+			}
+			m, ok := margins[item]
+			if !ok {
+				priced = false
+				break
+			}
+			margin += m
+		}
+		if !priced || margin <= 0 {
+			continue
+		}
+		candidates = append(candidates, Bundle{ItemsetResult: r, Margin: margin, Score: r.Support * margin})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return strings.Join(candidates[i].Items, ",") < strings.Join(candidates[j].Items, ",")
+	})
+
+	used := make(map[string]bool)
+	var bundles []Bundle
+	for _, b := range candidates {
+		overlaps := false
+		for _, item := range b.Items {
+			if used[item] {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		for _, item := range b.Items {
+			used[item] = true
+		}
+		bundles = append(bundles, b)
+	}
+	return bundles
+}
+
+// writeBundlesCSV writes the bundles in rank order
+func writeBundlesCSV(w io.Writer, bundles []Bundle) error {
+	fmt.Fprintln(w, "Rank,Items,Size,Support,Margin,Score,AllConfidence")
+	for i, b := range bundles {
+		_, err := fmt.Fprintf(w, "%d,\"%s\",%d,%f,%f,%f,%f\n", i+1, strings.Join(b.Items, ","), b.Size, b.Support, b.Margin, b.Score, b.AllConfidence)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBundles implements the bundles subcommand, which proposes
+// non-overlapping product bundles from a saved itemset result
+func runBundles(args []string) error {
+	fs := flag.NewFlagSet("bundles", flag.ExitOnError)
+	prices := fs.String("prices", "", "file of \"item price [cost]\" lines; without it every item counts as a margin of 1")
+	minSize := fs.Int("min-size", 2, "fewest items in a bundle")
+	maxSize := fs.Int("max-size", 0, "most items in a bundle (0 for no limit)")
+	minAllConfidence := fs.Float64("min-all-confidence", 0, "minimum all-confidence, so that every item of a bundle sells with the others")
+	limit := fs.Int("n", 20, "bundles proposed (0 for all)")
+	output := fs.String("output", "", "bundles file (default: <dataset>_bundles.csv next to the itemsets)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bundles [flags] <dataset>_summary.csv")
+	}
+	if *minSize < 1 || (*maxSize > 0 && *maxSize < *minSize) {
+		return fmt.Errorf("min-size must be at least 1 and at most max-size")
+	}
+	var margins map[string]float64
+	if *prices != "" {
+		var err error
+		if margins, err = LoadItemMargins(*prices); err != nil {
+			return err
+		}
+	}
+
+	path := fs.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	results, err := ReadResultsCSV(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	bundles := suggestBundles(results, margins, *minSize, *maxSize, *minAllConfidence)
+	if *limit > 0 && len(bundles) > *limit {
+		bundles = bundles[:*limit]
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(path, "_summary.csv") + "_bundles.csv"
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := writeBundlesCSV(out, bundles); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Proposed %d bundles from %d itemsets; written to %s\n", len(bundles), len(results), *output)
+	return nil
+}
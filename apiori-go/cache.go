@@ -0,0 +1,87 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// cachedResult is the value stored in the result cache
+type cachedResult struct {
+	Results []ItemsetResult `json:"results"`
+	Metrics TimingMetrics   `json:"metrics"`
+}
+
+// SetCache enables caching of mined results in Redis for ttl
+func (s *Server) SetCache(cache *RedisCache, ttl time.Duration) {
+	s.cache = cache
+	s.cacheTTL = ttl
+}
+
+// resultCacheKey identifies results by dataset contents and mining parameters
+func resultCacheKey(info DatasetInfo, minSupport float64) string {
+	return fmt.Sprintf("apriori:results:%s:%g", info.SHA256, minSupport)
+}
+
+// lookupCachedResult returns cached results for the dataset and parameters.
+// Cache failures are logged and treated as misses.
+func (s *Server) lookupCachedResult(info DatasetInfo, minSupport float64) (cachedResult, bool) {
+	if s.cache == nil || info.SHA256 == "" {
+		return cachedResult{}, false
+	}
+	data, ok, err := s.cache.Get(resultCacheKey(info, minSupport))
+	if err != nil {
+		log.Printf("result cache lookup failed: %v", err)
+		return cachedResult{}, false
+	}
+	if !ok {
+		return cachedResult{}, false
+	}
+	var cached cachedResult
+	if err := json.Unmarshal(data, &cached); err != nil {
+		log.Printf("ignoring corrupt cache entry: %v", err)
+		return cachedResult{}, false
+	}
+	return cached, true
+}
+
+// storeCachedResult saves results for later identical requests
+func (s *Server) storeCachedResult(info DatasetInfo, minSupport float64, result cachedResult) {
+	if s.cache == nil || info.SHA256 == "" {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to encode cache entry: %v", err)
+		return
+	}
+	if err := s.cache.Set(resultCacheKey(info, minSupport), data, s.cacheTTL); err != nil {
+		log.Printf("failed to store cache entry: %v", err)
+	}
+}
+
+// completeFromCacheLocked records job as finished with cached results. The
+// caller must hold s.mu.
+func (s *Server) completeFromCacheLocked(job *Job, cached cachedResult) (Job, error) {
+	if err := s.saveJobResults(job.ID, cached.Results); err != nil {
+		return Job{}, fmt.Errorf("failed to save results: %v", err)
+	}
+	now := time.Now().UTC()
+	job.Status = JobDone
+	job.Cached = true
+	job.FinishedAt = &now
+	job.Metrics = cached.Metrics
+	job.Itemsets = len(cached.Results)
+	job.results = cached.Results
+	s.jobs[job.ID] = job
+	if err := s.saveStateLocked(); err != nil {
+		delete(s.jobs, job.ID)
+		return Job{}, err
+	}
+	s.publishEvent(job, JobEvent{Type: EventFinished, Status: job.Status})
+	s.notifyWebhook(*job)
+	return *job, nil
+}
@@ -0,0 +1,104 @@
+//go:build capi && !js
+
+// The capi build exports the miner through a C ABI for callers in C, C++ or
+// Python. Build the library and its header with
+//
+//	go build -tags capi -buildmode=c-shared -o libapriori.so .
+//
+// Both functions take a JSON request and return a JSON response allocated by
+// the library, which the caller must release with apriori_free:
+//
+//	char *apriori_mine(const char *request);
+//	char *apriori_rules(const char *request);
+//	void apriori_free(char *response);
+//
+// A request has the form
+//
+//	{"transactions": [["bread", "milk"], ...], "minSupport": 0.4, "minConfidence": 0.6}
+//
+// where the supports default to the CLI values. apriori_mine responds with
+// {"itemsets": [...]} and apriori_rules with {"rules": [...]}, using the JSON
+// layout of the server API; failures respond with {"error": "..."}.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// capiRequest is the JSON request accepted by the exported functions
+type capiRequest struct {
+	Transactions  []Transaction `json:"transactions"`
+	MinSupport    *float64      `json:"minSupport"`
+	MinConfidence *float64      `json:"minConfidence"`
+}
+
+// capiMine decodes a request and mines its transactions
+func capiMine(request *C.char) (*AprioriMiner, float64, error) {
+	if request == nil {
+		return nil, 0, fmt.Errorf("request is NULL")
+	}
+	var req capiRequest
+	if err := json.Unmarshal([]byte(C.GoString(request)), &req); err != nil {
+		return nil, 0, fmt.Errorf("invalid request: %v", err)
+	}
+	minSupport, minConfidence := 0.4, 0.6
+	if req.MinSupport != nil {
+		minSupport = *req.MinSupport
+	}
+	if req.MinConfidence != nil {
+		minConfidence = *req.MinConfidence
+	}
+	if !(minSupport > 0 && minSupport <= 1) {
+		return nil, 0, fmt.Errorf("minSupport must be in (0,1]")
+	}
+	if !(minConfidence >= 0 && minConfidence <= 1) {
+		return nil, 0, fmt.Errorf("minConfidence must be in [0,1]")
+	}
+	miner := NewAprioriMiner(Dataset(req.Transactions), minSupport)
+	miner.Mine()
+	return miner, minConfidence, nil
+}
+
+// capiRespond encodes {field: value}, or {"error": ...} when err is set, as
+// a C string owned by the caller
+func capiRespond(field string, value interface{}, err error) *C.char {
+	resp := map[string]interface{}{field: value}
+	if err != nil {
+		resp = map[string]interface{}{"error": err.Error()}
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	return C.CString(string(data))
+}
+
+//export apriori_mine
+func apriori_mine(request *C.char) *C.char {
+	miner, _, err := capiMine(request)
+	if err != nil {
+		return capiRespond("", nil, err)
+	}
+	return capiRespond("itemsets", miner.Results(), nil)
+}
+
+//export apriori_rules
+func apriori_rules(request *C.char) *C.char {
+	miner, minConfidence, err := capiMine(request)
+	if err != nil {
+		return capiRespond("", nil, err)
+	}
+	return capiRespond("rules", miner.GenerateRules(minConfidence), nil)
+}
+
+//export apriori_free
+func apriori_free(response *C.char) {
+	C.free(unsafe.Pointer(response))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Class label positions within a transaction line
+const (
+	ClassFirst = "first"
+	ClassLast  = "last"
+)
+
+// classItemPrefix marks the class labels added to transactions while mining
+// class association rules. Items split on whitespace never hold a space, so
+// a class item cannot collide with a feature item.
+const classItemPrefix = "class "
+
+// LabeledDataset is a dataset whose transactions each carry a class label
+// apart from their items
+type LabeledDataset struct {
+	Transactions Dataset
+	Classes      []string
+}
+
+// SplitClassLabels separates the class label from every transaction: the
+// item starting with prefix, the prefix dropped, or with no prefix the item
+// at position (first or last)
+func SplitClassLabels(dataset Dataset, position, prefix string) (LabeledDataset, error) {
+	if prefix == "" && position != ClassFirst && position != ClassLast {
+		return LabeledDataset{}, fmt.Errorf("class position must be %s or %s", ClassFirst, ClassLast)
+	}
+	labeled := LabeledDataset{
+		Transactions: make(Dataset, len(dataset)),
+		Classes:      make([]string, len(dataset)),
+	}
+	for i, t := range dataset {
+		if len(t) == 0 {
+			return LabeledDataset{}, fmt.Errorf("transaction %d has no class label", i+1)
+		}
+		at := -1
+		switch {
+		case prefix != "":
+			for j, item := range t {
+				if !strings.HasPrefix(item, prefix) {
+					continue
+				}
+				if at >= 0 {
+					return LabeledDataset{}, fmt.Errorf("transaction %d has more than one class label", i+1)
+				}
+				at = j
+			}
+			if at < 0 {
+				return LabeledDataset{}, fmt.Errorf("transaction %d has no item starting with %q", i+1, prefix)
+			}
+			labeled.Classes[i] = strings.TrimPrefix(t[at], prefix)
+		case position == ClassFirst:
+			at = 0
+			labeled.Classes[i] = t[at]
+		default:
+			at = len(t) - 1
+			labeled.Classes[i] = t[at]
+		}
+		items := make(Transaction, 0, len(t)-1)
+		items = append(items, t[:at]...)
+		labeled.Transactions[i] = append(items, t[at+1:]...)
+	}
+	return labeled, nil
+}
+
+// ClassCounts returns the number of transactions of each class
+func (ld LabeledDataset) ClassCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, c := range ld.Classes {
+		counts[c]++
+	}
+	return counts
+}
+
+// ClassRule is a class association rule Antecedent -> Class
+type ClassRule struct {
+	Antecedent []string `json:"antecedent"`
+	Class      string   `json:"class"`
+	// Count is the number of transactions of the class holding the
+	// antecedent; AntecedentCount counts them over all classes
+	Count           int `json:"count"`
+	AntecedentCount int `json:"antecedentCount"`
+	// Support is Count over all transactions and ClassSupport over the
+	// transactions of the class
+	Support      float64 `json:"support"`
+	ClassSupport float64 `json:"classSupport"`
+	Confidence   float64 `json:"confidence"`
+	Lift         float64 `json:"lift"`
+}
+
+// String formats the rule as "a,b -> class"
+func (r ClassRule) String() string {
+	return strings.Join(r.Antecedent, ",") + " -> " + r.Class
+}
+
+// MineClassRules mines the class association rules meeting minSupport and
+// minConfidence. The class of each transaction is mined as one more item,
+// so the frequent itemsets holding a class are the rules' itemsets and the
+// rest give their antecedent counts. Rules are ranked by sortClassRules.
+func MineClassRules(ld LabeledDataset, minSupport, minConfidence float64) []ClassRule {
+	tagged := make(Dataset, len(ld.Transactions))
+	for i, t := range ld.Transactions {
+		tagged[i] = append(append(make(Transaction, 0, len(t)+1), t...), classItemPrefix+ld.Classes[i])
+	}
+	miner := NewAprioriMiner(tagged, minSupport)
+	miner.Mine()
+
+	classCounts := ld.ClassCounts()
+	total := float64(len(tagged))
+	var rules []ClassRule
+	for k, itemsets := range miner.frequentSets {
+		if k < 2 {
+			continue
+		}
+		for _, itemset := range itemsets {
+			var antecedent []string
+			class, classes := "", 0
+			for _, item := range sortedItems(itemset) {
+				if strings.HasPrefix(item, classItemPrefix) {
+					class = strings.TrimPrefix(item, classItemPrefix)
+					classes++
+				} else {
+					antecedent = append(antecedent, item)
+				}
+			}
+			if classes != 1 {
+				continue
+			}
+			count := miner.countSupport(itemset)
+			antecedentCount := miner.countSupport(toItemSet(antecedent))
+			confidence := float64(count) / float64(antecedentCount)
+			if confidence < minConfidence {
+				continue
+			}
+			rules = append(rules, ClassRule{
+				Antecedent:      antecedent,
+				Class:           class,
+				Count:           count,
+				AntecedentCount: antecedentCount,
+				Support:         float64(count) / total,
+				ClassSupport:    float64(count) / float64(classCounts[class]),
+				Confidence:      confidence,
+				Lift:            confidence / (float64(classCounts[class]) / total),
+			})
+		}
+	}
+	sortClassRules(rules)
+	return rules
+}
+
+// sortClassRules ranks rules by confidence, then support, then shorter
+// antecedent, breaking the remaining ties by the rule text
+func sortClassRules(rules []ClassRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		if a.Support != b.Support {
+			return a.Support > b.Support
+		}
+		if len(a.Antecedent) != len(b.Antecedent) {
+			return len(a.Antecedent) < len(b.Antecedent)
+		}
+		return a.String() < b.String()
+	})
+}
+
+// WriteClassRulesCSV writes class association rules with their measures, one
+// per line
+func WriteClassRulesCSV(w io.Writer, rules []ClassRule) error {
+	if _, err := io.WriteString(w, "Antecedent,Class,Count,AntecedentCount,Support,ClassSupport,Confidence,Lift\n"); err != nil {
+		return err
+	}
+	for _, r := range rules {
+		_, err := fmt.Fprintf(w, "\"%s\",\"%s\",%d,%d,%f,%f,%f,%f\n", strings.Join(r.Antecedent, ","), r.Class,
+			r.Count, r.AntecedentCount, r.Support, r.ClassSupport, r.Confidence, r.Lift)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
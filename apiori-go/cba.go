@@ -0,0 +1,99 @@
+package main
+
+// CBAClassifier is an associative classifier built as CBA does (Liu, Hsu and
+// Ma, 1998): an ordered list of class association rules, the first one whose
+// antecedent a transaction holds giving its class, and a default class for
+// transactions no rule covers
+type CBAClassifier struct {
+	Rules        []ClassRule `json:"rules"`
+	DefaultClass string      `json:"defaultClass"`
+	// TrainingErrors counts the training transactions the classifier
+	// misclassifies
+	TrainingErrors       int `json:"trainingErrors"`
+	TrainingTransactions int `json:"trainingTransactions"`
+	// ClassPosition and ClassPrefix locate the class label in labeled
+	// transactions, as given to SplitClassLabels
+	ClassPosition string  `json:"classPosition"`
+	ClassPrefix   string  `json:"classPrefix,omitempty"`
+	MinSupport    float64 `json:"minSupport"`
+	MinConfidence float64 `json:"minConfidence"`
+}
+
+// majorityClass returns the most frequent class of the transactions not yet
+// covered, the lexically first on ties, with the number of them it misses
+func majorityClass(classes []string, covered []bool) (string, int) {
+	counts := make(map[string]int)
+	remaining := 0
+	for i, c := range classes {
+		if !covered[i] {
+			counts[c]++
+			remaining++
+		}
+	}
+	best := ""
+	for c, n := range counts {
+		if best == "" || n > counts[best] || n == counts[best] && c < best {
+			best = c
+		}
+	}
+	return best, remaining - counts[best]
+}
+
+// BuildCBA selects a classifier from rules ranked by sortClassRules with
+// database coverage pruning. Going down the ranking, a rule is kept when it
+// correctly classifies a transaction no kept rule covers, and the
+// transactions it covers are removed. Each kept rule fixes a default class,
+// the majority of the transactions left, and the classifier is cut after
+// the kept rule with the fewest total training errors.
+func BuildCBA(ld LabeledDataset, rules []ClassRule) *CBAClassifier {
+	n := len(ld.Transactions)
+	covered := make([]bool, n)
+	defaultClass, defaultErrors := majorityClass(ld.Classes, covered)
+	c := &CBAClassifier{DefaultClass: defaultClass, TrainingErrors: defaultErrors, TrainingTransactions: n}
+
+	var kept []ClassRule
+	ruleErrors := 0
+	for _, rule := range rules {
+		antecedent := toItemSet(rule.Antecedent)
+		var matches []int
+		correct := false
+		for i, t := range ld.Transactions {
+			if !covered[i] && isSubset(antecedent, t) {
+				matches = append(matches, i)
+				correct = correct || ld.Classes[i] == rule.Class
+			}
+		}
+		if !correct {
+			continue
+		}
+		for _, i := range matches {
+			covered[i] = true
+			if ld.Classes[i] != rule.Class {
+				ruleErrors++
+			}
+		}
+		kept = append(kept, rule)
+		class, errors := majorityClass(ld.Classes, covered)
+		if class == "" {
+			// Every transaction is covered; keep the previous default
+			class = defaultClass
+		}
+		defaultClass = class
+		if total := ruleErrors + errors; total < c.TrainingErrors {
+			c.Rules = append([]ClassRule(nil), kept...)
+			c.DefaultClass, c.TrainingErrors = class, total
+		}
+	}
+	return c
+}
+
+// Classify returns the class of a transaction and the index of the rule
+// giving it, or -1 when the default class applies
+func (c *CBAClassifier) Classify(t Transaction) (string, int) {
+	for i, rule := range c.Rules {
+		if isSubset(toItemSet(rule.Antecedent), t) {
+			return rule.Class, i
+		}
+	}
+	return c.DefaultClass, -1
+}
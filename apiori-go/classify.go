@@ -0,0 +1,163 @@
+//go:build !js
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// RuleCoverage is how a rule fares as a classifier on evaluated transactions
+type RuleCoverage struct {
+	Rule
+	// Matches counts the transactions holding the antecedent; Coverage is
+	// their share of all transactions
+	Matches  int     `json:"matches"`
+	Coverage float64 `json:"coverage"`
+	// Hits counts the matches whose target holds the whole consequent;
+	// Precision is their share of the matches
+	Hits      int     `json:"hits"`
+	Precision float64 `json:"precision"`
+}
+
+// ClassifierMetrics scores the predicted items against the target items of
+// every transaction, counted over all predictions (micro-averaged)
+type ClassifierMetrics struct {
+	Transactions   int     `json:"transactions"`
+	Predicted      int     `json:"predicted"`
+	Targets        int     `json:"targets"`
+	TruePositives  int     `json:"truePositives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+	CoveredShare   float64 `json:"coveredShare"`
+	PredictedShare float64 `json:"predictedShare"`
+}
+
+// hideItems splits every transaction into the items rules see and hide
+// target items drawn with seed, leaving at least one item visible
+func hideItems(dataset Dataset, hide int, seed int64) (visible, targets Dataset) {
+	rng := rand.New(rand.NewSource(seed))
+	visible, targets = make(Dataset, len(dataset)), make(Dataset, len(dataset))
+	for i, t := range dataset {
+		items := distinctItems(Dataset{t})
+		rng.Shuffle(len(items), func(a, b int) { items[a], items[b] = items[b], items[a] })
+		k := min(hide, max(len(items)-1, 0))
+		targets[i], visible[i] = items[:k], items[k:]
+	}
+	return visible, targets
+}
+
+// loadLabels reads the target items of each transaction, a line each in the
+// layout of a dataset
+func loadLabels(filename string, transactions int) (Dataset, error) {
+	labels, err := LoadDataset(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) != transactions {
+		return nil, fmt.Errorf("%s has %d lines for %d transactions", filename, len(labels), transactions)
+	}
+	return labels, nil
+}
+
+// withoutTargets drops from every transaction the items of its target, which
+// the rules must predict rather than see
+func withoutTargets(dataset, targets Dataset) Dataset {
+	visible := make(Dataset, len(dataset))
+	for i, t := range dataset {
+		target := toItemSet(targets[i])
+		for _, item := range t {
+			if !target[item] {
+				visible[i] = append(visible[i], item)
+			}
+		}
+	}
+	return visible
+}
+
+// evaluateClassifier scores the predictions of applied transactions against
+// their targets, and each rule by its matches and hits
+func evaluateClassifier(rules []Rule, applied []AppliedTransaction, targets Dataset) (ClassifierMetrics, []RuleCoverage) {
+	m := ClassifierMetrics{Transactions: len(applied)}
+	coverage := make([]RuleCoverage, len(rules))
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		coverage[i].Rule = rule
+		index[rule.String()] = i
+	}
+	covered, predicting := 0, 0
+	for i, a := range applied {
+		target := toItemSet(targets[i])
+		m.Targets += len(target)
+		m.Predicted += len(a.Predicted)
+		for _, item := range a.Predicted {
+			if target[item] {
+				m.TruePositives++
+			}
+		}
+		if len(a.Matched) > 0 {
+			covered++
+		}
+		if len(a.Predicted) > 0 {
+			predicting++
+		}
+		for _, rule := range a.Matched {
+			c := &coverage[index[rule.String()]]
+			c.Matches++
+			if isSubset(toItemSet(rule.Consequent), Transaction(targets[i])) {
+				c.Hits++
+			}
+		}
+	}
+	if m.Predicted > 0 {
+		m.Precision = float64(m.TruePositives) / float64(m.Predicted)
+	}
+	if m.Targets > 0 {
+		m.Recall = float64(m.TruePositives) / float64(m.Targets)
+	}
+	if m.Precision+m.Recall > 0 {
+		m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+	}
+	if n := len(applied); n > 0 {
+		m.CoveredShare = float64(covered) / float64(n)
+		m.PredictedShare = float64(predicting) / float64(n)
+		for i := range coverage {
+			coverage[i].Coverage = float64(coverage[i].Matches) / float64(n)
+			if coverage[i].Matches > 0 {
+				coverage[i].Precision = float64(coverage[i].Hits) / float64(coverage[i].Matches)
+			}
+		}
+	}
+	return m, coverage
+}
+
+// writeClassifierCSV writes the overall metrics as a single row
+func writeClassifierCSV(path string, m ClassifierMetrics) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Transactions,Predicted,Targets,TruePositives,Precision,Recall,F1,CoveredShare,PredictedShare")
+	fmt.Fprintf(f, "%d,%d,%d,%d,%f,%f,%f,%f,%f\n", m.Transactions, m.Predicted, m.Targets, m.TruePositives,
+		m.Precision, m.Recall, m.F1, m.CoveredShare, m.PredictedShare)
+	return f.Close()
+}
+
+// writeRuleCoverageCSV writes a line per rule with its classifier measures
+func writeRuleCoverageCSV(path string, coverage []RuleCoverage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Antecedent,Consequent,Confidence,Lift,Matches,Coverage,Hits,Precision")
+	for _, c := range coverage {
+		fmt.Fprintf(f, "\"%s\",\"%s\",%f,%f,%d,%f,%d,%f\n", strings.Join(c.Antecedent, ","), strings.Join(c.Consequent, ","),
+			c.Confidence, c.Lift, c.Matches, c.Coverage, c.Hits, c.Precision)
+	}
+	return f.Close()
+}
@@ -0,0 +1,213 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// loadLabeledDataset reads a dataset and splits off the class label of each
+// transaction as SplitClassLabels does
+func loadLabeledDataset(filename, position, prefix string) (LabeledDataset, error) {
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return LabeledDataset{}, err
+	}
+	return SplitClassLabels(dataset, position, prefix)
+}
+
+// runCARs implements the cars subcommand, which mines the class association
+// rules of a labeled dataset: the rules whose consequent is the class label
+func runCARs(args []string) error {
+	fs := flag.NewFlagSet("cars", flag.ExitOnError)
+	minSupport := fs.Float64("minsupport", 0.1, "minimum support of a rule's antecedent and class together")
+	minConfidence := fs.Float64("minconfidence", 0.5, "minimum confidence")
+	classPosition := fs.String("class-position", ClassLast, "item of each line holding the class label: first or last")
+	classPrefix := fs.String("class-prefix", "", "take the class label from the item starting with this prefix instead")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_cars.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: cars [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	filename := fs.Arg(0)
+	labeled, err := loadLabeledDataset(filename, *classPosition, *classPrefix)
+	if err != nil {
+		return err
+	}
+	rules := MineClassRules(labeled, *minSupport, *minConfidence)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_cars.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if err := WriteClassRulesCSV(w, rules); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	counts := labeled.ClassCounts()
+	perClass := make(map[string]int, len(counts))
+	for _, r := range rules {
+		perClass[r.Class]++
+	}
+	classes := make([]string, 0, len(counts))
+	for c := range counts {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	fmt.Printf("Mined %d class association rules from %d transactions of %d classes\n", len(rules), len(labeled.Transactions), len(classes))
+	for _, c := range classes {
+		fmt.Printf("  %s: %d transactions, %d rules\n", c, counts[c], perClass[c])
+	}
+	fmt.Printf("Rules written to %s\n", path)
+	return nil
+}
+
+// runTrain implements the train subcommand, which builds a CBA classifier
+// from the class association rules of a labeled dataset
+func runTrain(args []string) error {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	minSupport := fs.Float64("minsupport", 0.1, "minimum support of a rule's antecedent and class together")
+	minConfidence := fs.Float64("minconfidence", 0.5, "minimum confidence")
+	classPosition := fs.String("class-position", ClassLast, "item of each line holding the class label: first or last")
+	classPrefix := fs.String("class-prefix", "", "take the class label from the item starting with this prefix instead")
+	output := fs.String("o", "", "classifier file (default: results/<dataset>_cba.json)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: train [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	filename := fs.Arg(0)
+	labeled, err := loadLabeledDataset(filename, *classPosition, *classPrefix)
+	if err != nil {
+		return err
+	}
+	if len(labeled.Transactions) == 0 {
+		return fmt.Errorf("%s has no transactions", filename)
+	}
+	rules := MineClassRules(labeled, *minSupport, *minConfidence)
+	classifier := BuildCBA(labeled, rules)
+	classifier.ClassPosition, classifier.ClassPrefix = *classPosition, *classPrefix
+	classifier.MinSupport, classifier.MinConfidence = *minSupport, *minConfidence
+
+	path := *output
+	if path == "" {
+		if err := os.MkdirAll("results", 0755); err != nil {
+			return err
+		}
+		path = filepath.Join("results", getOutputBasename(filename)+"_cba.json")
+	}
+	data, err := json.MarshalIndent(classifier, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	accuracy := 1 - float64(classifier.TrainingErrors)/float64(classifier.TrainingTransactions)
+	fmt.Printf("Mined %d class association rules; kept %d after database coverage pruning, default class %s\n",
+		len(rules), len(classifier.Rules), classifier.DefaultClass)
+	fmt.Printf("Training accuracy %.4f (%d errors in %d transactions)\n", accuracy, classifier.TrainingErrors, classifier.TrainingTransactions)
+	fmt.Printf("Classifier written to %s\n", path)
+	return nil
+}
+
+// runPredict implements the predict subcommand, which classifies
+// transactions with a classifier built by train, scoring it when the
+// transactions carry their class
+func runPredict(args []string) error {
+	fs := flag.NewFlagSet("predict", flag.ExitOnError)
+	labeled := fs.Bool("labeled", false, "transactions carry their class as in training; report the accuracy")
+	outputDir := fs.String("output-dir", "results", "directory receiving <transactions>_predictions.csv")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: predict [flags] <classifier.json> <transactions>")
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var classifier CBAClassifier
+	if err := json.Unmarshal(data, &classifier); err != nil {
+		return fmt.Errorf("failed to read %s: %v", fs.Arg(0), err)
+	}
+
+	filename := fs.Arg(1)
+	var ld LabeledDataset
+	if *labeled {
+		ld, err = loadLabeledDataset(filename, classifier.ClassPosition, classifier.ClassPrefix)
+	} else {
+		ld.Transactions, err = LoadDataset(filename)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_predictions.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "Transaction,Predicted,Actual,Rule")
+	correct, byDefault := 0, 0
+	for i, t := range ld.Transactions {
+		class, rule := classifier.Classify(t)
+		ruleText := "default"
+		if rule >= 0 {
+			ruleText = classifier.Rules[rule].String()
+		} else {
+			byDefault++
+		}
+		actual := ""
+		if *labeled {
+			actual = ld.Classes[i]
+			if actual == class {
+				correct++
+			}
+		}
+		fmt.Fprintf(w, "%d,\"%s\",\"%s\",\"%s\"\n", i+1, class, actual, ruleText)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Classified %d transactions with %d rules; %d fell to the default class %s\n",
+		len(ld.Transactions), len(classifier.Rules), byDefault, classifier.DefaultClass)
+	if *labeled && len(ld.Transactions) > 0 {
+		fmt.Printf("Accuracy %.4f (%d of %d)\n", float64(correct)/float64(len(ld.Transactions)), correct, len(ld.Transactions))
+	}
+	fmt.Printf("Predictions written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ClickstreamFields names the fields of clickstream events; a dotted name
+// such as "context.user_id" reaches into nested objects
+type ClickstreamFields struct {
+	User  string
+	Event string
+	Item  string
+	Time  string
+	// EventTypes, when set, keeps only the events of these types
+	EventTypes map[string]bool
+	// TagEvent prefixes every item with its event type, as in "view:sku1",
+	// so viewing and buying an item are different items
+	TagEvent bool
+}
+
+// jsonString renders a scalar JSON value as an item or user ID
+func jsonString(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, x != ""
+	case json.Number:
+		return x.String(), true
+	case bool:
+		return strconv.FormatBool(x), true
+	}
+	return "", false
+}
+
+// LoadClickstreamEvents reads JSON Lines clickstream events into events of a
+// user acting on an item. Timestamps are strings or numbers in any format of
+// timestamped datasets. Events missing a field are counted in skipped.
+func LoadClickstreamEvents(filename string, fields ClickstreamFields) (events []Event, skipped int, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.UseNumber()
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, skipped, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+
+		var eventType string
+		if fields.EventTypes != nil || fields.TagEvent {
+			v, _ := jsonField(doc, fields.Event)
+			eventType, _ = jsonString(v)
+			if fields.EventTypes != nil && !fields.EventTypes[eventType] {
+				continue
+			}
+		}
+		userValue, _ := jsonField(doc, fields.User)
+		itemValue, _ := jsonField(doc, fields.Item)
+		timeValue, _ := jsonField(doc, fields.Time)
+		user, okUser := jsonString(userValue)
+		item, okItem := jsonString(itemValue)
+		ts, okTime := jsonString(timeValue)
+		if !okUser || !okItem || !okTime || (fields.TagEvent && eventType == "") {
+			skipped++
+			continue
+		}
+		t, err := parseTimestamp(ts)
+		if err != nil {
+			return nil, skipped, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+		item = strings.Join(strings.Fields(item), "_")
+		if fields.TagEvent {
+			item = eventType + ":" + item
+		}
+		events = append(events, Event{User: user, Time: t, Item: item})
+	}
+	return events, skipped, scanner.Err()
+}
@@ -0,0 +1,375 @@
+// Package client is a typed Go client for the REST API of the apriori server
+// started with `serve`. The API is described by the server's /openapi.json.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Job statuses reported by the server
+const (
+	JobQueued      = "queued"
+	JobRunning     = "running"
+	JobDone        = "done"
+	JobFailed      = "failed"
+	JobInterrupted = "interrupted"
+)
+
+// Dataset describes a dataset registered with the server
+type Dataset struct {
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	Bytes        int64     `json:"bytes"`
+	Transactions int       `json:"transactions"`
+	SHA256       string    `json:"sha256"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+// JobRequest is the body of a job submission
+type JobRequest struct {
+	Dataset    string  `json:"dataset"`
+	MinSupport float64 `json:"minSupport"`
+	WebhookURL string  `json:"webhookUrl,omitempty"`
+}
+
+// TimingMetrics holds the durations of a job in seconds
+type TimingMetrics struct {
+	DataLoadTime float64 `json:"dataLoadTime"`
+	// LoadShards is the number of byte ranges loaded concurrently, 0 or 1
+	// for a sequential load
+	LoadShards     int     `json:"loadShards,omitempty"`
+	ProcessingTime float64 `json:"processingTime"`
+	TotalTime      float64 `json:"totalTime"`
+}
+
+// Job is a mining job as reported by the server
+type Job struct {
+	ID          string        `json:"id"`
+	Namespace   string        `json:"namespace"`
+	Dataset     string        `json:"dataset"`
+	MinSupport  float64       `json:"minSupport"`
+	Status      string        `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	SubmittedAt time.Time     `json:"submittedAt"`
+	StartedAt   *time.Time    `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time    `json:"finishedAt,omitempty"`
+	Metrics     TimingMetrics `json:"metrics"`
+	Itemsets    int           `json:"itemsets"`
+	Cached      bool          `json:"cached,omitempty"`
+	WebhookURL  string        `json:"webhookUrl,omitempty"`
+	SubmittedBy string        `json:"submittedBy,omitempty"`
+}
+
+// Finished reports whether the job has reached a final status
+func (j Job) Finished() bool {
+	return j.Status == JobDone || j.Status == JobFailed || j.Status == JobInterrupted
+}
+
+// Itemset is a frequent itemset found by a job
+type Itemset struct {
+	Size    int      `json:"size"`
+	Items   []string `json:"items"`
+	Count   int      `json:"count"`
+	Support float64  `json:"support"`
+	// Null-invariant measures of how strongly the items occur together
+	AllConfidence float64 `json:"allConfidence"`
+	Kulczynski    float64 `json:"kulczynski"`
+	Cosine        float64 `json:"cosine"`
+}
+
+// LevelProgress reports the outcome of one level of a running job
+type LevelProgress struct {
+	Level      int `json:"level"`
+	Candidates int `json:"candidates"`
+	Frequent   int `json:"frequent"`
+	// MinSupport is the threshold the level was counted against
+	MinSupport float64 `json:"minSupport"`
+}
+
+// JobEvent is a progress notification streamed while a job runs
+type JobEvent struct {
+	Type   string         `json:"type"`
+	JobID  string         `json:"jobId"`
+	Time   time.Time      `json:"time"`
+	Level  *LevelProgress `json:"level,omitempty"`
+	Status string         `json:"status,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// Quota limits the resources of a namespace; zero means unlimited
+type Quota struct {
+	MaxDatasets     int   `json:"maxDatasets,omitempty"`
+	MaxStorageBytes int64 `json:"maxStorageBytes,omitempty"`
+	MaxActiveJobs   int   `json:"maxActiveJobs,omitempty"`
+}
+
+// Namespace reports a namespace with its quota and current usage
+type Namespace struct {
+	Name         string    `json:"name"`
+	Quota        Quota     `json:"quota"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Datasets     int       `json:"datasets"`
+	StorageBytes int64     `json:"storageBytes"`
+	ActiveJobs   int       `json:"activeJobs"`
+	Jobs         int       `json:"jobs"`
+}
+
+// Error is returned for responses with a non-success status
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("apriori server: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client talks to one apriori server. Namespace selects the namespace that
+// dataset and job calls address; empty means the default namespace.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// New returns a client for the server at baseURL authenticating with apiKey,
+// which may be empty when the server does not require keys
+func New(baseURL, apiKey string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), APIKey: apiKey, HTTPClient: http.DefaultClient}
+}
+
+// WithNamespace returns a copy of c addressing namespace ns
+func (c *Client) WithNamespace(ns string) *Client {
+	cp := *c
+	cp.Namespace = ns
+	return &cp
+}
+
+// scoped prefixes path with the client's namespace
+func (c *Client) scoped(path string) string {
+	if c.Namespace == "" {
+		return path
+	}
+	return "/namespaces/" + url.PathEscape(c.Namespace) + path
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	return req, nil
+}
+
+// send performs the request, decoding a JSON error body on failure
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var body struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &body) != nil || body.Error == "" {
+			body.Error = strings.TrimSpace(string(data))
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+	return resp, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	contentType := ""
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	req, err := c.newRequest(ctx, method, path, body, contentType)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ListNamespaces returns all namespaces with their usage
+func (c *Client) ListNamespaces(ctx context.Context) ([]Namespace, error) {
+	var out []Namespace
+	err := c.do(ctx, http.MethodGet, "/namespaces", nil, &out)
+	return out, err
+}
+
+// GetNamespace returns the quota and usage of namespace ns
+func (c *Client) GetNamespace(ctx context.Context, ns string) (Namespace, error) {
+	var out Namespace
+	err := c.do(ctx, http.MethodGet, "/namespaces/"+url.PathEscape(ns), nil, &out)
+	return out, err
+}
+
+// PutNamespace creates namespace ns or updates its quota. A nil quota applies
+// the server's default to new namespaces and leaves existing ones unchanged.
+func (c *Client) PutNamespace(ctx context.Context, ns string, quota *Quota) (Namespace, error) {
+	var in interface{}
+	if quota != nil {
+		in = struct {
+			Quota *Quota `json:"quota"`
+		}{quota}
+	}
+	var out Namespace
+	err := c.do(ctx, http.MethodPut, "/namespaces/"+url.PathEscape(ns), in, &out)
+	return out, err
+}
+
+// ListDatasets returns the datasets of the client's namespace
+func (c *Client) ListDatasets(ctx context.Context) ([]Dataset, error) {
+	var out []Dataset
+	err := c.do(ctx, http.MethodGet, c.scoped("/datasets"), nil, &out)
+	return out, err
+}
+
+// GetDataset returns the dataset called name
+func (c *Client) GetDataset(ctx context.Context, name string) (Dataset, error) {
+	var out Dataset
+	err := c.do(ctx, http.MethodGet, c.scoped("/datasets/"+url.PathEscape(name)), nil, &out)
+	return out, err
+}
+
+// UploadDataset stores the transactions read from r as dataset name
+func (c *Client) UploadDataset(ctx context.Context, name string, r io.Reader) (Dataset, error) {
+	var out Dataset
+	req, err := c.newRequest(ctx, http.MethodPut, c.scoped("/datasets/"+url.PathEscape(name)), r, "text/plain")
+	if err != nil {
+		return out, err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&out)
+	return out, err
+}
+
+// RegisterDataset registers a file already present on the server as dataset
+// name. path is relative to the namespace's import root on the server.
+func (c *Client) RegisterDataset(ctx context.Context, name, path string) (Dataset, error) {
+	in := struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+	}{name, path}
+	var out Dataset
+	err := c.do(ctx, http.MethodPost, c.scoped("/datasets"), in, &out)
+	return out, err
+}
+
+// ListJobs returns the jobs of the client's namespace
+func (c *Client) ListJobs(ctx context.Context) ([]Job, error) {
+	var out []Job
+	err := c.do(ctx, http.MethodGet, c.scoped("/jobs"), nil, &out)
+	return out, err
+}
+
+// SubmitJob queues a mining job
+func (c *Client) SubmitJob(ctx context.Context, req JobRequest) (Job, error) {
+	var out Job
+	err := c.do(ctx, http.MethodPost, c.scoped("/jobs"), req, &out)
+	return out, err
+}
+
+// GetJob returns the job with the given ID
+func (c *Client) GetJob(ctx context.Context, id string) (Job, error) {
+	var out Job
+	err := c.do(ctx, http.MethodGet, c.scoped("/jobs/"+url.PathEscape(id)), nil, &out)
+	return out, err
+}
+
+// JobResults returns the frequent itemsets of a finished job
+func (c *Client) JobResults(ctx context.Context, id string) ([]Itemset, error) {
+	var out []Itemset
+	err := c.do(ctx, http.MethodGet, c.scoped("/jobs/"+url.PathEscape(id)+"/results"), nil, &out)
+	return out, err
+}
+
+// WaitJob polls the job every interval until it finishes or ctx is done
+func (c *Client) WaitJob(ctx context.Context, id string, interval time.Duration) (Job, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil || job.Finished() {
+			return job, err
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamJobEvents calls fn for every progress event of the job until the job
+// finishes, fn returns an error, or ctx is done
+func (c *Client) StreamJobEvents(ctx context.Context, id string, fn func(JobEvent) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, c.scoped("/jobs/"+url.PathEscape(id)+"/events"), nil, "")
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event JobEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("invalid event: %v", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
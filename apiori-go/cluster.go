@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ItemsetCluster is a group of similar frequent itemsets summarised by one
+// representative
+type ItemsetCluster struct {
+	Representative ItemsetResult   `json:"representative"`
+	Members        []ItemsetResult `json:"members"`
+}
+
+// jaccardDistance returns 1 - |a∩b| / |a∪b| of two sorted item lists
+func jaccardDistance(a, b []string) float64 {
+	common, i, j := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return 1 - float64(common)/float64(union)
+}
+
+// ClusterItemsets groups itemsets whose Jaccard distance to a cluster's
+// representative is at most maxDistance. Larger and then more frequent
+// itemsets are considered first and become representatives when no existing
+// one is close enough, so each cluster is led by its most specific pattern.
+func ClusterItemsets(results []ItemsetResult, maxDistance float64) []ItemsetCluster {
+	order := make([]ItemsetResult, len(results))
+	copy(order, results)
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Size != order[j].Size {
+			return order[i].Size > order[j].Size
+		}
+		return order[i].Support > order[j].Support
+	})
+
+	var clusters []ItemsetCluster
+	// Representatives by item: within a distance below 1 they share an item
+	byItem := make(map[string][]int)
+	for _, r := range order {
+		best, bestDistance := -1, 0.0
+		seen := make(map[int]bool)
+		for _, item := range r.Items {
+			for _, c := range byItem[item] {
+				if seen[c] {
+					continue
+				}
+				seen[c] = true
+				d := jaccardDistance(r.Items, clusters[c].Representative.Items)
+				if d > maxDistance {
+					continue
+				}
+				if best < 0 || d < bestDistance || (d == bestDistance && c < best) {
+					best, bestDistance = c, d
+				}
+			}
+		}
+		if best >= 0 {
+			clusters[best].Members = append(clusters[best].Members, r)
+			continue
+		}
+		clusters = append(clusters, ItemsetCluster{Representative: r, Members: []ItemsetResult{r}})
+		for _, item := range r.Items {
+			byItem[item] = append(byItem[item], len(clusters)-1)
+		}
+	}
+	return clusters
+}
+
+// writeClustersCSV writes one line per cluster with its representative and
+// members to path
+func writeClustersCSV(path string, clusters []ItemsetCluster) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Cluster,Representative,Support,Members,MemberItemsets")
+	for i, c := range clusters {
+		members := make([]string, len(c.Members))
+		for j, m := range c.Members {
+			members[j] = "{" + strings.Join(m.Items, ",") + "}"
+		}
+		fmt.Fprintf(f, "%d,\"%s\",%f,%d,\"%s\"\n", i+1, strings.Join(c.Representative.Items, ","),
+			c.Representative.Support, len(c.Members), strings.Join(members, " "))
+	}
+	return f.Close()
+}
@@ -0,0 +1,68 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// runCompare implements the compare subcommand, which runs two algorithms on
+// the same dataset and threshold and checks that they find the same frequent
+// itemsets with the same supports
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	first := fs.String("a", "apriori", "first algorithm")
+	second := fs.String("b", "eclat", "second algorithm")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support")
+	tolerance := fs.Float64("tolerance", 1e-9, "largest support difference accepted")
+	workersA := fs.Int("workers-a", 1, "counting workers of -a when it is apriori")
+	workersB := fs.Int("workers-b", 1, "counting workers of -b when it is apriori; compare with -a apriori -b apriori -tolerance 0 to check determinism")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: compare [-a algorithm] [-b algorithm] [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	algorithmA, err := lookupAlgorithm(*first)
+	if err != nil {
+		return err
+	}
+	algorithmB, err := lookupAlgorithm(*second)
+	if err != nil {
+		return err
+	}
+	if *workersA < 1 || *workersB < 1 {
+		return fmt.Errorf("workers-a and workers-b must be at least 1")
+	}
+	if *first == "apriori" {
+		algorithmA = aprioriWorkers(*workersA)
+	}
+	if *second == "apriori" {
+		algorithmB = aprioriWorkers(*workersB)
+	}
+
+	dataset, err := LoadDataset(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	resultsA := algorithmA(dataset, *minSupport)
+	fmt.Printf("%s: %d itemsets in %.3f seconds\n", *first, len(resultsA), time.Since(start).Seconds())
+	start = time.Now()
+	resultsB := algorithmB(dataset, *minSupport)
+	fmt.Printf("%s: %d itemsets in %.3f seconds\n", *second, len(resultsB), time.Since(start).Seconds())
+
+	// Differences read as what the second algorithm gets wrong relative to the first
+	diffs := compareResults(resultsA, resultsB, *tolerance)
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%s and %s disagree on %d itemsets", *first, *second, len(diffs))
+	}
+	fmt.Printf("%s and %s agree\n", *first, *second)
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"sort"
+)
+
+// SetMustContain restricts the itemsets mined to those holding at least one
+// of items. The constraint is pushed into candidate generation: only
+// itemsets with a required item are generated and counted, so frequent
+// itemsets without one are never explored beyond single items.
+func (am *AprioriMiner) SetMustContain(items []string) {
+	if len(items) == 0 {
+		am.mustContain = nil
+		return
+	}
+	am.mustContain = toItemSet(items)
+}
+
+// withRequiredItem returns the sets holding a required item
+func (am *AprioriMiner) withRequiredItem(sets []ItemSet) []ItemSet {
+	var kept []ItemSet
+	for _, set := range sets {
+		for item := range set {
+			if am.mustContain[item] {
+				kept = append(kept, set)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// requiredFirst orders the items of set with the required items before the
+// others, each group in lexicographic order
+func (am *AprioriMiner) requiredFirst(set ItemSet) []string {
+	items := sortedItems(set)
+	sort.SliceStable(items, func(i, j int) bool {
+		return am.mustContain[items[i]] && !am.mustContain[items[j]]
+	})
+	return items
+}
+
+// generateConstrainedCandidates generates the candidates of size+1 holding a
+// required item. With required items ordered first, such an itemset loses
+// neither its first item nor its constraint when its last or second-to-last
+// item is dropped, so the usual prefix join of the frequent constrained sets
+// finds it. Pairs instead join a required item with any frequent item.
+// Subsets without a required item were never counted and are not checked.
+func (am *AprioriMiner) generateConstrainedCandidates(ctx context.Context, frequentSets []ItemSet, size int) ([]ItemSet, error) {
+	ordered := make([][]string, len(frequentSets))
+	for i, set := range frequentSets {
+		ordered[i] = am.requiredFirst(set)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		for k := range a {
+			if a[k] != b[k] {
+				if am.mustContain[a[k]] != am.mustContain[b[k]] {
+					return am.mustContain[a[k]]
+				}
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	frequent := make(map[string]bool, len(frequentSets))
+	for _, set := range frequentSets {
+		frequent[itemsetKey(set)] = true
+	}
+
+	candidates := make([]ItemSet, 0)
+	for i := range ordered {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		items1 := ordered[i]
+		if size == 1 && !am.mustContain[items1[0]] {
+			continue
+		}
+		for j := i + 1; j < len(ordered); j++ {
+			items2 := ordered[j]
+			if size > 1 && !equalPrefix(items1, items2, size-1) {
+				break
+			}
+			newSet := toItemSet(append(append([]string{}, items1...), items2[size-1]))
+			if len(newSet) != size+1 || (am.multiset && hasRepeatedItem(newSet)) {
+				continue
+			}
+			if am.candidateHook != nil {
+				am.candidateHook(CandidateStep{Level: size + 1, Items: sortedItems(newSet), Outcome: OutcomeCreated})
+			}
+			if subset := am.infrequentRequiredSubset(newSet, frequent); subset != nil {
+				if am.candidateHook != nil {
+					am.candidateHook(CandidateStep{Level: size + 1, Items: sortedItems(newSet),
+						Outcome: OutcomePruned, PrunedBy: sortedItems(subset)})
+				}
+				continue
+			}
+			candidates = append(candidates, newSet)
+		}
+	}
+	return candidates, nil
+}
+
+// equalPrefix reports whether a and b share their first n items
+func equalPrefix(a, b []string, n int) bool {
+	for k := 0; k < n; k++ {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// infrequentRequiredSubset returns the first subset of candidate one item
+// smaller that holds a required item but is not frequent, or nil
+func (am *AprioriMiner) infrequentRequiredSubset(candidate ItemSet, frequent map[string]bool) ItemSet {
+	if len(candidate) <= 2 {
+		return nil
+	}
+	for drop := range candidate {
+		subset := make(ItemSet, len(candidate)-1)
+		required := false
+		for item := range candidate {
+			if item != drop {
+				subset[item] = true
+				required = required || am.mustContain[item]
+			}
+		}
+		if required && !frequent[itemsetKey(subset)] {
+			return subset
+		}
+	}
+	return nil
+}
@@ -0,0 +1,478 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the dataset handed to an external implementation
+const (
+	// AdapterInputText is the dataset as loaded, one transaction of item
+	// names per line
+	AdapterInputText = "text"
+	// AdapterInputSPMF codes the items as positive integers, sorted within
+	// each transaction, as SPMF expects
+	AdapterInputSPMF = "spmf"
+)
+
+// Formats of the itemsets an external implementation writes
+const (
+	// AdapterOutputSPMF is one itemset per line followed by "#SUP: count"
+	AdapterOutputSPMF = "spmf"
+	// AdapterOutputSummary is the layout of the summary file
+	AdapterOutputSummary = "summary"
+	// AdapterOutputLines counts the non-empty lines as itemsets without
+	// checking them
+	AdapterOutputLines = "lines"
+)
+
+// ExternalAdapter describes how to run one reference implementation. The
+// command is an argument list whose placeholders are replaced before it
+// runs: {input} and {output} are the dataset and result paths, {minsupport}
+// the support as a fraction, {minsupport_percent} as a percentage, {mincount}
+// the matching transaction count, {transactions} the dataset size and
+// {config_dir} the directory of the adapter file. Without {output} the
+// command's standard output is read as the result.
+type ExternalAdapter struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+	Input   string   `json:"input"`
+	Output  string   `json:"output"`
+	// Timeout is the time in seconds a run may take; 0 uses -timeout
+	Timeout float64 `json:"timeout"`
+}
+
+// AdapterConfig is the file format read by crossbench -adapters
+type AdapterConfig struct {
+	Adapters []ExternalAdapter `json:"adapters"`
+}
+
+// crossbenchRun is the outcome of one implementation on the dataset
+type crossbenchRun struct {
+	Implementation string
+	Kind           string
+	Seconds        []float64
+	Itemsets       int
+	// Differences counts the itemsets disagreeing with the reference, or is
+	// -1 when the output was not checked
+	Differences int
+	Err         error
+}
+
+// status summarises a run for the comparison file
+func (r crossbenchRun) status() string {
+	switch {
+	case r.Err != nil:
+		return "failed"
+	case r.Differences < 0:
+		return "unchecked"
+	case r.Differences > 0:
+		return "disagrees"
+	}
+	return "agrees"
+}
+
+// loadAdapterConfig reads and validates an adapter file
+func loadAdapterConfig(path string) ([]ExternalAdapter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config AdapterConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse adapter config: %v", err)
+	}
+	if len(config.Adapters) == 0 {
+		return nil, fmt.Errorf("adapter config has no adapters")
+	}
+	seen := make(map[string]bool)
+	for i := range config.Adapters {
+		a := &config.Adapters[i]
+		if !datasetNamePattern.MatchString(a.Name) || seen[a.Name] {
+			return nil, fmt.Errorf("adapter names must be unique and use letters, digits, '.', '_' or '-': %q", a.Name)
+		}
+		seen[a.Name] = true
+		if _, ok := miningAlgorithms[a.Name]; ok {
+			return nil, fmt.Errorf("adapter %s shares its name with a built-in algorithm", a.Name)
+		}
+		if len(a.Command) == 0 {
+			return nil, fmt.Errorf("adapter %s has no command", a.Name)
+		}
+		if a.Input == "" {
+			a.Input = AdapterInputText
+		}
+		if a.Input != AdapterInputText && a.Input != AdapterInputSPMF {
+			return nil, fmt.Errorf("adapter %s: input must be %s or %s", a.Name, AdapterInputText, AdapterInputSPMF)
+		}
+		if a.Output == "" {
+			a.Output = AdapterOutputSPMF
+		}
+		if a.Output != AdapterOutputSPMF && a.Output != AdapterOutputSummary && a.Output != AdapterOutputLines {
+			return nil, fmt.Errorf("adapter %s: output must be %s, %s or %s", a.Name, AdapterOutputSPMF, AdapterOutputSummary, AdapterOutputLines)
+		}
+		if a.Timeout < 0 {
+			return nil, fmt.Errorf("adapter %s: timeout must not be negative", a.Name)
+		}
+	}
+	return config.Adapters, nil
+}
+
+// writeAdapterInputs writes the dataset in both input formats to dir and
+// returns their paths with the item names behind the SPMF codes
+func writeAdapterInputs(dir string, dataset Dataset) (textPath, spmfPath string, names []string, err error) {
+	codes := make(map[string]int)
+	for _, item := range distinctItems(dataset) {
+		names = append(names, item)
+		codes[item] = len(names)
+	}
+	textPath = filepath.Join(dir, "dataset.txt")
+	spmfPath = filepath.Join(dir, "dataset_spmf.txt")
+	var text, spmf bytes.Buffer
+	for _, transaction := range dataset {
+		items := sortedItems(toItemSet(transaction))
+		text.WriteString(strings.Join(items, " ") + "\n")
+		coded := make([]int, len(items))
+		for i, item := range items {
+			coded[i] = codes[item]
+		}
+		sort.Ints(coded)
+		for i, code := range coded {
+			if i > 0 {
+				spmf.WriteByte(' ')
+			}
+			spmf.WriteString(strconv.Itoa(code))
+		}
+		spmf.WriteByte('\n')
+	}
+	if err := os.WriteFile(textPath, text.Bytes(), 0644); err != nil {
+		return "", "", nil, err
+	}
+	if err := os.WriteFile(spmfPath, spmf.Bytes(), 0644); err != nil {
+		return "", "", nil, err
+	}
+	return textPath, spmfPath, names, nil
+}
+
+// readSPMFItemsets parses itemsets written as "items #SUP: count", mapping
+// integer items back to their names when names is set
+func readSPMFItemsets(r io.Reader, names []string, transactions int) ([]ItemsetResult, error) {
+	var results []ItemsetResult
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		itemsPart, supPart, ok := strings.Cut(text, "#SUP:")
+		if !ok {
+			return nil, fmt.Errorf("line %d: no #SUP: count", line)
+		}
+		// Other measures, such as #CONF:, may follow the count
+		fields := strings.Fields(supPart)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("line %d: no #SUP: count", line)
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid count %q", line, fields[0])
+		}
+		items := strings.Fields(itemsPart)
+		if names != nil {
+			for i, item := range items {
+				code, err := strconv.Atoi(item)
+				if err != nil || code < 1 || code > len(names) {
+					return nil, fmt.Errorf("line %d: unknown item %q", line, item)
+				}
+				items[i] = names[code-1]
+			}
+		}
+		sort.Strings(items)
+		results = append(results, ItemsetResult{Size: len(items), Items: items, Count: count,
+			Support: float64(count) / float64(transactions)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// countLines counts the non-empty lines of r
+func countLines(r io.Reader) (int, error) {
+	n := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			n++
+		}
+	}
+	return n, scanner.Err()
+}
+
+// runAdapter runs an external implementation once on the input of its
+// format and returns the itemsets it found, nil for unchecked output, with
+// how many there were
+func runAdapter(ctx context.Context, a ExternalAdapter, inputs, placeholders map[string]string, names []string,
+	transactions int, workDir string) ([]ItemsetResult, int, time.Duration, error) {
+	outputPath := filepath.Join(workDir, a.Name+"_output.txt")
+	os.Remove(outputPath)
+	inputPath := inputs[a.Input]
+	toStdout := true
+	args := make([]string, len(a.Command))
+	for i, arg := range a.Command {
+		if strings.Contains(arg, "{output}") {
+			toStdout = false
+		}
+		arg = strings.ReplaceAll(arg, "{input}", inputPath)
+		arg = strings.ReplaceAll(arg, "{output}", outputPath)
+		for name, value := range placeholders {
+			arg = strings.ReplaceAll(arg, "{"+name+"}", value)
+		}
+		args[i] = arg
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, 0, elapsed, fmt.Errorf("timed out after %s", elapsed.Round(time.Millisecond))
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+			msg = msg[i+1:]
+		}
+		if msg != "" {
+			return nil, 0, elapsed, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, 0, elapsed, err
+	}
+
+	var output io.Reader = &stdout
+	if !toStdout {
+		f, err := os.Open(outputPath)
+		if err != nil {
+			return nil, 0, elapsed, fmt.Errorf("no result file: %v", err)
+		}
+		defer f.Close()
+		output = f
+	}
+	var results []ItemsetResult
+	switch a.Output {
+	case AdapterOutputLines:
+		n, err := countLines(output)
+		return nil, n, elapsed, err
+	case AdapterOutputSummary:
+		results, err = ReadResultsCSV(output)
+	default:
+		codes := names
+		if a.Input == AdapterInputText {
+			codes = nil
+		}
+		results, err = readSPMFItemsets(output, codes, transactions)
+	}
+	if err != nil {
+		return nil, 0, elapsed, fmt.Errorf("unreadable result: %v", err)
+	}
+	return results, len(results), elapsed, nil
+}
+
+// writeCrossbenchCSV writes one line per implementation
+func writeCrossbenchCSV(path string, minSupport float64, runs []crossbenchRun) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Implementation,Kind,MinSupport,Runs,MeanSeconds,MinSeconds,Itemsets,Differences,Status,Error")
+	for _, r := range runs {
+		mean, fastest := 0.0, 0.0
+		if len(r.Seconds) > 0 {
+			fastest = r.Seconds[0]
+			for _, s := range r.Seconds {
+				mean += s
+				fastest = min(fastest, s)
+			}
+			mean /= float64(len(r.Seconds))
+		}
+		differences, message := "", ""
+		if r.Differences >= 0 && r.Err == nil {
+			differences = strconv.Itoa(r.Differences)
+		}
+		if r.Err != nil {
+			message = strings.ReplaceAll(r.Err.Error(), "\"", "'")
+		}
+		fmt.Fprintf(f, "%s,%s,%f,%d,%f,%f,%d,%s,%s,\"%s\"\n", r.Implementation, r.Kind, minSupport, len(r.Seconds),
+			mean, fastest, r.Itemsets, differences, r.status(), message)
+	}
+	return f.Close()
+}
+
+// runCrossbench implements the crossbench subcommand, which times the
+// built-in algorithms and external reference implementations, such as SPMF
+// or mlxtend, on the same dataset and threshold and checks their itemsets
+// against the first built-in algorithm
+func runCrossbench(args []string) error {
+	fs := flag.NewFlagSet("crossbench", flag.ExitOnError)
+	adapters := fs.String("adapters", "", "JSON file of external implementations to run (see adapters/adapters.json)")
+	algorithms := fs.String("algorithms", "apriori,eclat", "comma-separated built-in algorithms; the first is the reference the others are checked against")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support")
+	repeat := fs.Int("repeat", 1, "runs timed per implementation")
+	timeout := fs.Duration("timeout", 10*time.Minute, "time allowed per external run")
+	tolerance := fs.Float64("tolerance", 1e-6, "largest support difference accepted")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_crossbench.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: crossbench [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if *repeat < 1 {
+		return fmt.Errorf("repeat must be at least 1")
+	}
+	names := parseParamList(*algorithms)
+	if len(names) == 0 {
+		return fmt.Errorf("algorithms must name at least one built-in algorithm")
+	}
+	builtin := make([]MiningAlgorithm, len(names))
+	for i, name := range names {
+		algorithm, err := lookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		builtin[i] = algorithm
+	}
+	var external []ExternalAdapter
+	configDir := ""
+	if *adapters != "" {
+		var err error
+		if external, err = loadAdapterConfig(*adapters); err != nil {
+			return err
+		}
+		if configDir, err = filepath.Abs(filepath.Dir(*adapters)); err != nil {
+			return err
+		}
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	if len(dataset) == 0 {
+		return fmt.Errorf("%s holds no transactions", filename)
+	}
+
+	var runs []crossbenchRun
+	var reference []ItemsetResult
+	for i, algorithm := range builtin {
+		run := crossbenchRun{Implementation: names[i], Kind: "built-in"}
+		var results []ItemsetResult
+		for r := 0; r < *repeat; r++ {
+			start := time.Now()
+			results = algorithm(dataset, *minSupport)
+			run.Seconds = append(run.Seconds, time.Since(start).Seconds())
+		}
+		if i == 0 {
+			reference = results
+		}
+		run.Itemsets = len(results)
+		run.Differences = len(compareResults(reference, results, *tolerance))
+		runs = append(runs, run)
+	}
+
+	if len(external) > 0 {
+		workDir, err := os.MkdirTemp("", "crossbench-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(workDir)
+		textPath, spmfPath, itemNames, err := writeAdapterInputs(workDir, dataset)
+		if err != nil {
+			return err
+		}
+		placeholders := map[string]string{
+			"minsupport":         strconv.FormatFloat(*minSupport, 'f', -1, 64),
+			"minsupport_percent": strconv.FormatFloat(*minSupport*100, 'f', -1, 64),
+			"mincount":           strconv.Itoa(int(math.Ceil(*minSupport*float64(len(dataset)) - 1e-9))),
+			"transactions":       strconv.Itoa(len(dataset)),
+			"config_dir":         configDir,
+		}
+		inputs := map[string]string{AdapterInputText: textPath, AdapterInputSPMF: spmfPath}
+		for _, a := range external {
+			run := crossbenchRun{Implementation: a.Name, Kind: "external", Differences: -1}
+			limit := *timeout
+			if a.Timeout > 0 {
+				limit = time.Duration(a.Timeout * float64(time.Second))
+			}
+			var results []ItemsetResult
+			for r := 0; r < *repeat && run.Err == nil; r++ {
+				ctx, cancel := context.WithTimeout(context.Background(), limit)
+				var elapsed time.Duration
+				results, run.Itemsets, elapsed, run.Err = runAdapter(ctx, a, inputs, placeholders, itemNames, len(dataset), workDir)
+				cancel()
+				run.Seconds = append(run.Seconds, elapsed.Seconds())
+			}
+			if run.Err == nil && a.Output != AdapterOutputLines {
+				run.Differences = len(compareResults(reference, results, *tolerance))
+			}
+			runs = append(runs, run)
+		}
+	}
+
+	fmt.Printf("%-16s %-9s %10s %9s %6s  %s\n", "implementation", "kind", "mean (s)", "itemsets", "diffs", "status")
+	failed := 0
+	for _, r := range runs {
+		mean := 0.0
+		for _, s := range r.Seconds {
+			mean += s / float64(len(r.Seconds))
+		}
+		differences := "-"
+		if r.Differences >= 0 && r.Err == nil {
+			differences = strconv.Itoa(r.Differences)
+		}
+		status := r.status()
+		if r.Err != nil {
+			status += ": " + r.Err.Error()
+		}
+		if r.Err != nil || r.Differences > 0 {
+			failed++
+		}
+		fmt.Printf("%-16s %-9s %10.3f %9d %6s  %s\n", r.Implementation, r.Kind, mean, r.Itemsets, differences, status)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_crossbench.csv")
+	if err := writeCrossbenchCSV(path, *minSupport, runs); err != nil {
+		return err
+	}
+	fmt.Printf("Comparison written to %s\n", path)
+	if failed > 0 {
+		return fmt.Errorf("%d implementations failed or disagree with %s", failed, names[0])
+	}
+	return nil
+}
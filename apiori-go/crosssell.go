@@ -0,0 +1,187 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CrossSell lists the items most associated with one item
+type CrossSell struct {
+	Item    string  `json:"item"`
+	Support float64 `json:"support"`
+	// Associated are the rules Item -> other item, best first
+	Associated []Rule `json:"associated"`
+}
+
+// minePairs mines the frequent items and pairs of dataset only, which is all
+// single-item rules need
+func minePairs(dataset Dataset, minSupport float64) *AprioriMiner {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	miner := NewAprioriMiner(dataset, minSupport)
+	miner.SetProgressFunc(func(p LevelProgress) {
+		if p.Level >= 2 {
+			cancel()
+		}
+	})
+	miner.MineContext(ctx)
+	return miner
+}
+
+// crossSellReport ranks, for each item, the items it predicts by measure and
+// keeps the top n. With items given only those are reported, in that order;
+// otherwise every item with an association is, by descending support.
+func crossSellReport(miner *AprioriMiner, items []string, minConfidence float64, measure string, n int) ([]CrossSell, error) {
+	byItem := make(map[string][]Rule)
+	var rules []Rule
+	for _, pair := range miner.frequentSets[2] {
+		rules = append(rules, miner.rulesFromItemset(sortedItems(pair), minConfidence)...)
+	}
+	AdjustPValues(rules, AdjustBenjaminiHochberg)
+	for _, rule := range rules {
+		byItem[rule.Antecedent[0]] = append(byItem[rule.Antecedent[0]], rule)
+	}
+	if len(items) == 0 {
+		for item := range byItem {
+			items = append(items, item)
+		}
+		sort.Slice(items, func(i, j int) bool {
+			si, sj := miner.calculateSupport(ItemSet{items[i]: true}), miner.calculateSupport(ItemSet{items[j]: true})
+			if si != sj {
+				return si > sj
+			}
+			return items[i] < items[j]
+		})
+	}
+
+	report := make([]CrossSell, 0, len(items))
+	for _, item := range items {
+		associated := byItem[item]
+		if err := SortRulesBy(associated, measure); err != nil {
+			return nil, err
+		}
+		if len(associated) > n {
+			associated = associated[:n]
+		}
+		report = append(report, CrossSell{
+			Item:       item,
+			Support:    float64(miner.countSupport(ItemSet{item: true})) / float64(miner.transactionLen),
+			Associated: associated,
+		})
+	}
+	return report, nil
+}
+
+// writeCrossSellCSV writes one line per item and associated item
+func writeCrossSellCSV(w io.Writer, report []CrossSell) error {
+	fmt.Fprintln(w, "Item,ItemSupport,Rank,AssociatedItem,Confidence,Lift,Support")
+	for _, c := range report {
+		for i, r := range c.Associated {
+			_, err := fmt.Fprintf(w, "\"%s\",%f,%d,\"%s\",%f,%f,%f\n", c.Item, c.Support, i+1, r.Consequent[0], r.Confidence, r.Lift, r.Support)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeCrossSellText writes the report in words, one block per item
+func writeCrossSellText(w io.Writer, report []CrossSell) error {
+	for _, c := range report {
+		fmt.Fprintf(w, "%s (in %.1f%% of baskets)\n", c.Item, c.Support*100)
+		if len(c.Associated) == 0 {
+			fmt.Fprintln(w, "  no associated items above the thresholds")
+		}
+		for i, r := range c.Associated {
+			fmt.Fprintf(w, "  %d. %s: in %.1f%% of baskets with %s, %.2fx its usual rate\n",
+				i+1, r.Consequent[0], r.Confidence*100, c.Item, r.Lift)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCrossSell implements the cross-sell subcommand, which reports the top
+// associated items of each item for merchandising
+func runCrossSell(args []string) error {
+	fs := flag.NewFlagSet("cross-sell", flag.ExitOnError)
+	items := fs.String("items", "", "comma-separated items to report (default: every item)")
+	n := fs.Int("n", 5, "associated items listed per item")
+	minSupport := fs.Float64("minsupport", 0.01, "minimum support of an item pair")
+	minConfidence := fs.Float64("minconfidence", 0, "minimum confidence of an associated item")
+	rankBy := fs.String("rank-by", RankConfidence, "order of the associated items: confidence, lift, leverage or normalized-leverage")
+	format := fs.String("format", "csv", "report format: csv or text")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_cross_sell.csv or .txt")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: cross-sell [flags] <dataset>")
+	}
+	if *n < 1 {
+		return fmt.Errorf("n must be at least 1")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if err := SortRulesBy(nil, *rankBy); err != nil {
+		return err
+	}
+	write, ext := writeCrossSellCSV, ".csv"
+	switch *format {
+	case "csv":
+	case "text":
+		write, ext = writeCrossSellText, ".txt"
+	default:
+		return fmt.Errorf("format must be csv or text")
+	}
+	var requested []string
+	if *items != "" {
+		for _, item := range strings.Split(*items, ",") {
+			requested = append(requested, strings.TrimSpace(item))
+		}
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	miner := minePairs(dataset, *minSupport)
+	report, err := crossSellReport(miner, requested, *minConfidence, *rankBy, *n)
+	if err != nil {
+		return err
+	}
+	for _, c := range report {
+		if c.Support == 0 {
+			fmt.Printf("Warning: %s does not occur in %s\n", c.Item, filename)
+		}
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_cross_sell"+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f, report); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Cross-sell report for %d items written to %s\n", len(report), path)
+	return nil
+}
@@ -0,0 +1,323 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Discretization methods
+const (
+	DiscretizeWidth     = "width"
+	DiscretizeFrequency = "frequency"
+	DiscretizeEntropy   = "entropy"
+)
+
+// missingValues are the cells read as no value, compared case-insensitively
+var missingValues = map[string]bool{"": true, "na": true, "n/a": true, "nan": true, "null": true, "?": true}
+
+// isMissing reports whether a table cell holds no value
+func isMissing(cell string) bool {
+	return missingValues[strings.ToLower(cell)]
+}
+
+// Binning is the intervals a numeric column is cut into: value v falls in
+// the first bin whose upper cut exceeds it
+type Binning struct {
+	Column string
+	Min    float64
+	Max    float64
+	Cuts   []float64
+	Counts []int
+}
+
+// bin returns the index of the interval holding v
+func (b *Binning) bin(v float64) int {
+	return sort.Search(len(b.Cuts), func(i int) bool { return v < b.Cuts[i] })
+}
+
+// label names interval i as [low,high), closing the last one at the maximum
+func (b *Binning) label(i int) string {
+	low, high := b.Min, b.Max
+	if i > 0 {
+		low = b.Cuts[i-1]
+	}
+	if i < len(b.Cuts) {
+		high = b.Cuts[i]
+		return "[" + formatCut(low) + "," + formatCut(high) + ")"
+	}
+	return "[" + formatCut(low) + "," + formatCut(high) + "]"
+}
+
+// formatCut prints a cut point to six significant digits
+func formatCut(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// numericColumn parses the present cells of a column, with the rows they
+// come from, or fails on the first cell that is not a number
+func numericColumn(cells []string) (values []float64, rows []int, err error) {
+	for r, cell := range cells {
+		if isMissing(cell) {
+			continue
+		}
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %q is not a number", r+2, cell)
+		}
+		values = append(values, v)
+		rows = append(rows, r)
+	}
+	return values, rows, nil
+}
+
+// equalWidthCuts splits [min,max] into bins intervals of the same width
+func equalWidthCuts(values []float64, bins int) []float64 {
+	low, high := minMax(values)
+	var cuts []float64
+	if high == low {
+		return cuts
+	}
+	width := (high - low) / float64(bins)
+	for i := 1; i < bins; i++ {
+		cuts = append(cuts, low+float64(i)*width)
+	}
+	return cuts
+}
+
+// equalFrequencyCuts splits the sorted values into bins intervals of about
+// the same count; repeated values never straddle a cut, so heavy ties yield
+// fewer bins
+func equalFrequencyCuts(values []float64, bins int) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var cuts []float64
+	for i := 1; i < bins; i++ {
+		cut := sorted[i*len(sorted)/bins]
+		if cut > sorted[0] && (len(cuts) == 0 || cut > cuts[len(cuts)-1]) {
+			cuts = append(cuts, cut)
+		}
+	}
+	return cuts
+}
+
+// entropyCuts splits the values where the class entropy drops most,
+// recursing into both halves while the split passes the minimum description
+// length criterion of Fayyad and Irani (1993)
+func entropyCuts(values []float64, classes []string) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+	sortedValues := make([]float64, len(values))
+	sortedClasses := make([]string, len(values))
+	for i, j := range order {
+		sortedValues[i], sortedClasses[i] = values[j], classes[j]
+	}
+	var cuts []float64
+	var split func(lo, hi int)
+	split = func(lo, hi int) {
+		n := hi - lo
+		whole, k := classEntropy(sortedClasses[lo:hi])
+		best, bestEntropy := -1, math.Inf(1)
+		for i := lo + 1; i < hi; i++ {
+			if sortedValues[i] == sortedValues[i-1] {
+				continue
+			}
+			left, _ := classEntropy(sortedClasses[lo:i])
+			right, _ := classEntropy(sortedClasses[i:hi])
+			e := (float64(i-lo)*left + float64(hi-i)*right) / float64(n)
+			if e < bestEntropy {
+				best, bestEntropy = i, e
+			}
+		}
+		if best < 0 {
+			return
+		}
+		left, k1 := classEntropy(sortedClasses[lo:best])
+		right, k2 := classEntropy(sortedClasses[best:hi])
+		delta := math.Log2(math.Pow(3, float64(k))-2) - (float64(k)*whole - float64(k1)*left - float64(k2)*right)
+		if whole-bestEntropy <= (math.Log2(float64(n-1))+delta)/float64(n) {
+			return
+		}
+		split(lo, best)
+		cuts = append(cuts, sortedValues[best])
+		split(best, hi)
+	}
+	split(0, len(sortedValues))
+	return cuts
+}
+
+// classEntropy returns the entropy in bits of the classes and how many
+// distinct ones there are
+func classEntropy(classes []string) (float64, int) {
+	counts := make(map[string]int)
+	for _, c := range classes {
+		counts[c]++
+	}
+	e := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(len(classes))
+		e -= p * math.Log2(p)
+	}
+	return e, len(counts)
+}
+
+func minMax(values []float64) (float64, float64) {
+	low, high := values[0], values[0]
+	for _, v := range values[1:] {
+		low, high = min(low, v), max(high, v)
+	}
+	return low, high
+}
+
+// writeBinsCSV writes the intervals of every discretized column
+func writeBinsCSV(path string, binnings []*Binning) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Column,Bin,Interval,Count")
+	for _, b := range binnings {
+		for i, count := range b.Counts {
+			fmt.Fprintf(f, "\"%s\",%d,\"%s\",%d\n", b.Column, i+1, b.label(i), count)
+		}
+	}
+	return f.Close()
+}
+
+// runDiscretize implements the discretize subcommand, which replaces the
+// numbers of numeric table columns by the intervals holding them, ready for
+// conversion into column=interval items
+func runDiscretize(args []string) error {
+	fs := flag.NewFlagSet("discretize", flag.ExitOnError)
+	method := fs.String("method", DiscretizeFrequency, "binning: width (equal-width), frequency (equal-frequency) or entropy (class entropy, needs -class)")
+	bins := fs.Int("bins", 5, "intervals per column for the width and frequency methods")
+	columns := fs.String("columns", "", "comma-separated columns to discretize (default: every column holding only numbers)")
+	class := fs.String("class", "", "class column the entropy method splits on")
+	delimiter := fs.String("delimiter", ",", "column delimiter of the table")
+	encoding := fs.String("encoding", "", "character encoding of the table, such as latin1 or gbk (default UTF-8); the output is UTF-8")
+	output := fs.String("o", "", "discretized table (default: results/<table>_discretized.csv, with the intervals in _bins.csv)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: discretize [flags] <table.csv>")
+	}
+	switch *method {
+	case DiscretizeWidth, DiscretizeFrequency:
+		if *bins < 2 {
+			return fmt.Errorf("bins must be at least 2")
+		}
+	case DiscretizeEntropy:
+		if *class == "" {
+			return fmt.Errorf("the entropy method needs -class")
+		}
+	default:
+		return fmt.Errorf("method must be %s, %s or %s", DiscretizeWidth, DiscretizeFrequency, DiscretizeEntropy)
+	}
+	delim, err := parseDelimiter(*delimiter)
+	if err != nil {
+		return err
+	}
+
+	filename := fs.Arg(0)
+	table, err := ReadTable(filename, delim, *encoding)
+	if err != nil {
+		return err
+	}
+	var classCells []string
+	if *class != "" {
+		at, err := table.columnIndex([]string{*class})
+		if err != nil {
+			return err
+		}
+		classCells = table.column(at[0])
+	}
+	var selected []int
+	if *columns != "" {
+		if selected, err = table.columnIndex(parseParamList(*columns)); err != nil {
+			return err
+		}
+	} else {
+		for i, name := range table.Header {
+			if name == *class {
+				continue
+			}
+			values, _, err := numericColumn(table.column(i))
+			if err == nil && len(values) > 0 {
+				selected = append(selected, i)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("%s has no numeric columns", filename)
+	}
+
+	var binnings []*Binning
+	for _, i := range selected {
+		values, rows, err := numericColumn(table.column(i))
+		if err != nil {
+			return fmt.Errorf("column %q: %v", table.Header[i], err)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("column %q has no values", table.Header[i])
+		}
+		b := &Binning{Column: table.Header[i]}
+		b.Min, b.Max = minMax(values)
+		switch *method {
+		case DiscretizeWidth:
+			b.Cuts = equalWidthCuts(values, *bins)
+		case DiscretizeFrequency:
+			b.Cuts = equalFrequencyCuts(values, *bins)
+		default:
+			// Rows missing their class take no part in choosing the cuts
+			var known []float64
+			var classes []string
+			for j, r := range rows {
+				if !isMissing(classCells[r]) {
+					known = append(known, values[j])
+					classes = append(classes, classCells[r])
+				}
+			}
+			if len(known) > 0 {
+				b.Cuts = entropyCuts(known, classes)
+			}
+		}
+		b.Counts = make([]int, len(b.Cuts)+1)
+		for j, r := range rows {
+			bin := b.bin(values[j])
+			b.Counts[bin]++
+			table.Rows[r][i] = b.label(bin)
+		}
+		binnings = append(binnings, b)
+	}
+
+	path := *output
+	if path == "" {
+		if err := os.MkdirAll("results", 0755); err != nil {
+			return err
+		}
+		path = filepath.Join("results", getOutputBasename(filename)+"_discretized.csv")
+	}
+	if err := WriteTable(path, table, delim); err != nil {
+		return err
+	}
+	binsPath := strings.TrimSuffix(path, filepath.Ext(path)) + "_bins.csv"
+	if err := writeBinsCSV(binsPath, binnings); err != nil {
+		return err
+	}
+	fmt.Printf("Discretized %d columns of %d rows by %s\n", len(binnings), len(table.Rows), *method)
+	for _, b := range binnings {
+		fmt.Printf("  %s: %d intervals over [%s,%s]\n", b.Column, len(b.Counts), formatCut(b.Min), formatCut(b.Max))
+	}
+	fmt.Printf("Table written to %s, intervals to %s\n", path, binsPath)
+	return nil
+}
@@ -0,0 +1,386 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"algo-project/aprioripb"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// workerService implements the MiningWorker gRPC service, holding the
+// partitions loaded by coordinators in memory until they are released
+type workerService struct {
+	aprioripb.UnimplementedMiningWorkerServer
+
+	mu         sync.Mutex
+	partitions map[string]Dataset
+}
+
+func newWorkerService() *workerService {
+	return &workerService{partitions: make(map[string]Dataset)}
+}
+
+// partition returns the loaded partition with the given ID
+func (w *workerService) partition(id string) (Dataset, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	part, ok := w.partitions[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "partition %q is not loaded", id)
+	}
+	return part, nil
+}
+
+// LoadPartition stores the transactions under the partition ID, replacing any
+// partition previously loaded with that ID
+func (w *workerService) LoadPartition(ctx context.Context, req *aprioripb.LoadPartitionRequest) (*aprioripb.LoadPartitionResponse, error) {
+	if req.GetPartitionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "partition_id is required")
+	}
+	part := transactionsFromProto(req.GetTransactions())
+	w.mu.Lock()
+	w.partitions[req.GetPartitionId()] = part
+	w.mu.Unlock()
+	return &aprioripb.LoadPartitionResponse{Transactions: int64(len(part))}, nil
+}
+
+// MinePartition mines a loaded partition with the requested relative support
+func (w *workerService) MinePartition(ctx context.Context, req *aprioripb.MinePartitionRequest) (*aprioripb.MinePartitionResponse, error) {
+	if !(req.GetMinSupport() > 0 && req.GetMinSupport() <= 1) {
+		return nil, status.Error(codes.InvalidArgument, "min_support must be in (0,1]")
+	}
+	part, err := w.partition(req.GetPartitionId())
+	if err != nil {
+		return nil, err
+	}
+	miner := NewAprioriMiner(part, req.GetMinSupport())
+	if err := miner.MineContext(ctx); err != nil {
+		return nil, err
+	}
+	resp := &aprioripb.MinePartitionResponse{}
+	for _, r := range miner.Results() {
+		resp.Itemsets = append(resp.Itemsets, itemsetToProto(r))
+	}
+	return resp, nil
+}
+
+// CountCandidates counts the transactions of a loaded partition containing
+// each candidate
+func (w *workerService) CountCandidates(ctx context.Context, req *aprioripb.CountCandidatesRequest) (*aprioripb.CountCandidatesResponse, error) {
+	part, err := w.partition(req.GetPartitionId())
+	if err != nil {
+		return nil, err
+	}
+	miner := NewAprioriMiner(part, 1)
+	resp := &aprioripb.CountCandidatesResponse{Counts: make([]int64, len(req.GetCandidates()))}
+	for i, candidate := range req.GetCandidates() {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		resp.Counts[i] = int64(miner.countSupport(toItemSet(candidate.GetItems())))
+	}
+	return resp, nil
+}
+
+// ReleasePartition forgets a loaded partition; unknown IDs are ignored
+func (w *workerService) ReleasePartition(ctx context.Context, req *aprioripb.ReleasePartitionRequest) (*aprioripb.ReleasePartitionResponse, error) {
+	w.mu.Lock()
+	delete(w.partitions, req.GetPartitionId())
+	w.mu.Unlock()
+	return &aprioripb.ReleasePartitionResponse{}, nil
+}
+
+func transactionsToProto(dataset Dataset) []*aprioripb.Transaction {
+	out := make([]*aprioripb.Transaction, len(dataset))
+	for i, transaction := range dataset {
+		out[i] = &aprioripb.Transaction{Items: transaction}
+	}
+	return out
+}
+
+func transactionsFromProto(transactions []*aprioripb.Transaction) Dataset {
+	out := make(Dataset, len(transactions))
+	for i, transaction := range transactions {
+		out[i] = transaction.GetItems()
+	}
+	return out
+}
+
+func itemsetToProto(r ItemsetResult) *aprioripb.Itemset {
+	return &aprioripb.Itemset{
+		Size:          int32(r.Size),
+		Items:         r.Items,
+		Count:         int64(r.Count),
+		Support:       r.Support,
+		AllConfidence: r.AllConfidence,
+		Kulczynski:    r.Kulczynski,
+		Cosine:        r.Cosine,
+	}
+}
+
+// partitionDataset splits dataset into n contiguous partitions of nearly equal size
+func partitionDataset(dataset Dataset, n int) []Dataset {
+	if n > len(dataset) {
+		n = len(dataset)
+	}
+	parts := make([]Dataset, 0, n)
+	for i := 0; i < n; i++ {
+		parts = append(parts, dataset[i*len(dataset)/n:(i+1)*len(dataset)/n])
+	}
+	return parts
+}
+
+// Coordinator mines a dataset across MiningWorker processes using the SON
+// algorithm: any itemset frequent in the whole dataset is frequent in at least
+// one partition, so the union of the locally frequent itemsets is a complete
+// candidate set whose global counts are the sums of the per-partition counts.
+// Each partition is sent to its worker once; the verification pass then sends
+// the candidates in shards and adds up the exact counts the workers return.
+type Coordinator struct {
+	workers    []aprioripb.MiningWorkerClient
+	partitions int
+	shardSize  int
+}
+
+// NewCoordinator returns a coordinator dispatching partitions round-robin to
+// workers. Partitions defaults to one per worker and shardSize, the number of
+// candidates per count request, to 10000 when not positive.
+func NewCoordinator(workers []aprioripb.MiningWorkerClient, partitions, shardSize int) *Coordinator {
+	if partitions <= 0 {
+		partitions = len(workers)
+	}
+	if shardSize <= 0 {
+		shardSize = 10000
+	}
+	return &Coordinator{workers: workers, partitions: partitions, shardSize: shardSize}
+}
+
+// forEachPartition runs fn for every partition concurrently, returning the
+// first error
+func (c *Coordinator) forEachPartition(ctx context.Context, n int, fn func(ctx context.Context, i int, worker aprioripb.MiningWorkerClient) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(ctx, i, c.workers[i%len(c.workers)]); err != nil {
+				errs[i] = fmt.Errorf("partition %d: %v", i, err)
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mine returns the itemsets of dataset meeting minSupport
+func (c *Coordinator) Mine(ctx context.Context, dataset Dataset, minSupport float64) (*AprioriMiner, error) {
+	miner := NewAprioriMiner(dataset, minSupport)
+	if len(dataset) == 0 {
+		return miner, nil
+	}
+	parts := partitionDataset(dataset, c.partitions)
+	runID := newJobID()
+	ids := make([]string, len(parts))
+	for i := range parts {
+		ids[i] = fmt.Sprintf("%s-%d", runID, i)
+	}
+	defer func() {
+		// Free worker memory even when mining failed part way
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		c.forEachPartition(releaseCtx, len(parts), func(ctx context.Context, i int, worker aprioripb.MiningWorkerClient) error {
+			_, err := worker.ReleasePartition(ctx, &aprioripb.ReleasePartitionRequest{PartitionId: ids[i]})
+			return err
+		})
+	}()
+
+	// Phase 1: load and mine each partition locally, collecting the union
+	local := make([][]*aprioripb.Itemset, len(parts))
+	err := traced(ctx, "son_local_mining", func(ctx context.Context) error {
+		return c.forEachPartition(ctx, len(parts), func(ctx context.Context, i int, worker aprioripb.MiningWorkerClient) error {
+			_, err := worker.LoadPartition(ctx, &aprioripb.LoadPartitionRequest{PartitionId: ids[i], Transactions: transactionsToProto(parts[i])})
+			if err != nil {
+				return err
+			}
+			resp, err := worker.MinePartition(ctx, &aprioripb.MinePartitionRequest{PartitionId: ids[i], MinSupport: minSupport})
+			if err != nil {
+				return err
+			}
+			local[i] = resp.GetItemsets()
+			return nil
+		})
+	}, attribute.Int("apriori.partitions", len(parts)))
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var candidates []*aprioripb.Itemset
+	for _, itemsets := range local {
+		for _, itemset := range itemsets {
+			key := strings.Join(itemset.GetItems(), "\x00")
+			if !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, &aprioripb.Itemset{Size: itemset.GetSize(), Items: itemset.GetItems()})
+			}
+		}
+	}
+	log.Printf("Phase 1: %d partitions produced %d candidate itemsets", len(parts), len(candidates))
+
+	// Phase 2: count the candidates shard by shard over every partition.
+	// Each partition writes only its own row, so no locking is needed.
+	counts := make([][]int64, len(parts))
+	err = traced(ctx, "son_verification", func(ctx context.Context) error {
+		return c.forEachPartition(ctx, len(parts), func(ctx context.Context, i int, worker aprioripb.MiningWorkerClient) error {
+			counts[i] = make([]int64, 0, len(candidates))
+			for start := 0; start < len(candidates); start += c.shardSize {
+				shard := candidates[start:min(start+c.shardSize, len(candidates))]
+				resp, err := worker.CountCandidates(ctx, &aprioripb.CountCandidatesRequest{PartitionId: ids[i], Candidates: shard})
+				if err != nil {
+					return err
+				}
+				if len(resp.GetCounts()) != len(shard) {
+					return fmt.Errorf("worker returned %d counts for %d candidates", len(resp.GetCounts()), len(shard))
+				}
+				counts[i] = append(counts[i], resp.GetCounts()...)
+			}
+			return nil
+		})
+	}, attribute.Int("apriori.candidates", len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	for j, candidate := range candidates {
+		total := 0
+		for i := range parts {
+			total += int(counts[i][j])
+		}
+		if float64(total)/float64(len(dataset)) >= minSupport {
+			set := toItemSet(candidate.GetItems())
+			miner.frequentSets[len(set)] = append(miner.frequentSets[len(set)], set)
+			miner.supportCounts[itemsetKey(set)] = total
+		}
+	}
+	return miner, nil
+}
+
+// runWorker serves the MiningWorker gRPC service for a coordinator
+func runWorker(args []string) error {
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	addr := fs.String("addr", ":9090", "address for the MiningWorker gRPC service")
+	maxMessage := fs.Int("max-message-mb", 512, "largest partition accepted in MiB")
+	fs.Parse(args)
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(*maxMessage<<20),
+		grpc.MaxSendMsgSize(*maxMessage<<20))
+	aprioripb.RegisterMiningWorkerServer(grpcServer, newWorkerService())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Serving MiningWorker on %s", *addr)
+		errCh <- grpcServer.Serve(lis)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	grpcServer.GracefulStop()
+	return nil
+}
+
+// runCoordinate mines a dataset file across the given workers and writes the
+// same result files as a local run
+func runCoordinate(args []string) error {
+	fs := flag.NewFlagSet("coordinate", flag.ExitOnError)
+	workerAddrs := fs.String("workers", "", "comma-separated addresses of worker processes")
+	partitions := fs.Int("partitions", 0, "number of dataset partitions (default one per worker)")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support in (0,1]")
+	outputDir := fs.String("output-dir", "results", "directory receiving the result files")
+	shardSize := fs.Int("shard-size", 10000, "candidates sent per count request in the verification pass")
+	maxMessage := fs.Int("max-message-mb", 512, "largest message exchanged with a worker in MiB")
+	fs.Parse(args)
+
+	if *workerAddrs == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: coordinate -workers host:port[,host:port...] [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	filename := fs.Arg(0)
+
+	var workers []aprioripb.MiningWorkerClient
+	for _, addr := range strings.Split(*workerAddrs, ",") {
+		conn, err := grpc.NewClient(strings.TrimSpace(addr),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallRecvMsgSize(*maxMessage<<20),
+				grpc.MaxCallSendMsgSize(*maxMessage<<20)))
+		if err != nil {
+			return fmt.Errorf("failed to connect to worker %s: %v", addr, err)
+		}
+		defer conn.Close()
+		workers = append(workers, aprioripb.NewMiningWorkerClient(conn))
+	}
+
+	startTime := time.Now()
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	dataLoadTime := time.Since(startTime)
+	fmt.Printf("Running distributed Apriori on dataset from %s across %d workers\n", filename, len(workers))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	processStart := time.Now()
+	miner, err := NewCoordinator(workers, *partitions, *shardSize).Mine(ctx, dataset, *minSupport)
+	if err != nil {
+		return err
+	}
+	processingTime := time.Since(processStart)
+	printResults(miner)
+
+	metrics := TimingMetrics{
+		DataLoadTime:   dataLoadTime.Seconds(),
+		ProcessingTime: processingTime.Seconds(),
+		TotalTime:      time.Since(startTime).Seconds(),
+	}
+	if err := miner.OutputResultsTo(*outputDir, getOutputBasename(filename), metrics); err != nil {
+		return err
+	}
+	fmt.Printf("\nResults have been written to CSV files in the '%s' directory.\n", *outputDir)
+	return nil
+}
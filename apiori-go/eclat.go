@@ -0,0 +1,146 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
+
+// MineEclat mines the frequent itemsets of dataset with Eclat: a depth-first
+// search over the items in lexicographic order that counts an itemset by
+// intersecting the transaction lists of its prefix and its last item. It
+// serves as an independent check of the Apriori miner.
+func MineEclat(dataset Dataset, minSupport float64) []ItemsetResult {
+	return mineEclat(dataset, minSupport, func(tids []int32) sliceTids { return tids })
+}
+
+// MineEclatRoaring is MineEclat with the transaction lists held as roaring
+// bitmaps, whose intersections stay fast and whose size follows the density
+// of an item rather than its count on datasets of millions of transactions
+func MineEclatRoaring(dataset Dataset, minSupport float64) []ItemsetResult {
+	return mineEclat(dataset, minSupport, newRoaringTids)
+}
+
+// tidset is a set of transaction IDs supporting the intersections of Eclat
+type tidset[T any] interface {
+	and(other T) T
+	cardinality() int
+}
+
+// sliceTids is a tidset as a sorted list of transaction IDs
+type sliceTids []int32
+
+func (a sliceTids) and(b sliceTids) sliceTids { return intersectTids(a, b) }
+func (a sliceTids) cardinality() int          { return len(a) }
+
+// roaringTids is a tidset as a roaring bitmap
+type roaringTids struct{ *roaring.Bitmap }
+
+func newRoaringTids(tids []int32) roaringTids {
+	b := roaring.New()
+	for _, tid := range tids {
+		b.Add(uint32(tid))
+	}
+	b.RunOptimize()
+	return roaringTids{b}
+}
+
+func (a roaringTids) and(b roaringTids) roaringTids {
+	return roaringTids{roaring.And(a.Bitmap, b.Bitmap)}
+}
+func (a roaringTids) cardinality() int { return int(a.GetCardinality()) }
+
+// mineEclat runs Eclat over transaction lists converted into tidsets of T
+func mineEclat[T tidset[T]](dataset Dataset, minSupport float64, convert func([]int32) T) []ItemsetResult {
+	n := len(dataset)
+	if n == 0 {
+		return []ItemsetResult{}
+	}
+	tidlists := buildTidlists(dataset)
+
+	type node struct {
+		item string
+		tids T
+	}
+	var roots []node
+	itemSupports := make(map[string]float64)
+	for item, tids := range tidlists {
+		if float64(len(tids))/float64(n) >= minSupport {
+			roots = append(roots, node{item, convert(tids)})
+			itemSupports[item] = float64(len(tids)) / float64(n)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].item < roots[j].item })
+
+	results := make([]ItemsetResult, 0)
+	var search func(prefix []string, nodes []node)
+	search = func(prefix []string, nodes []node) {
+		for i, nd := range nodes {
+			items := append(append([]string{}, prefix...), nd.item)
+			count := nd.tids.cardinality()
+			support := float64(count) / float64(n)
+			parts := make([]float64, len(items))
+			for j, item := range items {
+				parts[j] = itemSupports[item]
+			}
+			allConfidence, kulczynski, cosine := nullInvariantMeasures(support, parts)
+			results = append(results, ItemsetResult{
+				Size:          len(items),
+				Items:         items,
+				Count:         count,
+				Support:       support,
+				AllConfidence: allConfidence,
+				Kulczynski:    kulczynski,
+				Cosine:        cosine,
+			})
+			var children []node
+			for _, other := range nodes[i+1:] {
+				tids := nd.tids.and(other.tids)
+				if float64(tids.cardinality())/float64(n) >= minSupport {
+					children = append(children, node{other.item, tids})
+				}
+			}
+			if len(children) > 0 {
+				search(items, children)
+			}
+		}
+	}
+	search(nil, roots)
+	sortResults(results)
+	return results
+}
+
+// buildTidlists returns the vertical index of dataset: the sorted IDs of the
+// transactions containing each item
+func buildTidlists(dataset Dataset) map[string][]int32 {
+	tidlists := make(map[string][]int32)
+	for tid, transaction := range dataset {
+		for _, item := range transaction {
+			list := tidlists[item]
+			// Repeated items in a transaction are counted once
+			if len(list) == 0 || list[len(list)-1] != int32(tid) {
+				tidlists[item] = append(list, int32(tid))
+			}
+		}
+	}
+	return tidlists
+}
+
+// intersectTids returns the transaction IDs in both sorted lists
+func intersectTids(a, b []int32) []int32 {
+	out := make([]int32, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,205 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmergingPattern is an itemset whose support changed between two periods
+type EmergingPattern struct {
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Size          int       `json:"size"`
+	Items         []string  `json:"items"`
+	SupportBefore float64   `json:"supportBefore"`
+	SupportAfter  float64   `json:"supportAfter"`
+	// GrowthRate is SupportAfter / SupportBefore, +Inf for new itemsets
+	GrowthRate float64 `json:"growthRate"`
+}
+
+// timePeriod holds the transactions dated in [Start, End)
+type timePeriod struct {
+	Start, End time.Time
+	Dataset    Dataset
+}
+
+// splitPeriods cuts the transactions into consecutive periods of the given
+// length starting at the earliest transaction
+func splitPeriods(dataset Dataset, times []time.Time, length time.Duration) []timePeriod {
+	if len(times) == 0 {
+		return nil
+	}
+	first := times[0]
+	for _, t := range times {
+		if t.Before(first) {
+			first = t
+		}
+	}
+	var periods []timePeriod
+	for i, t := range times {
+		idx := int(t.Sub(first) / length)
+		for len(periods) <= idx {
+			start := first.Add(time.Duration(len(periods)) * length)
+			periods = append(periods, timePeriod{Start: start, End: start.Add(length)})
+		}
+		periods[idx].Dataset = append(periods[idx].Dataset, dataset[i])
+	}
+	return periods
+}
+
+// splitAt cuts the transactions into those before split and the rest
+func splitAt(dataset Dataset, times []time.Time, split time.Time) []timePeriod {
+	before := timePeriod{Start: split, End: split}
+	after := timePeriod{Start: split, End: split}
+	for i, t := range times {
+		if t.Before(split) {
+			before.Dataset = append(before.Dataset, dataset[i])
+			if t.Before(before.Start) {
+				before.Start = t
+			}
+		} else {
+			after.Dataset = append(after.Dataset, dataset[i])
+			if !t.Before(after.End) {
+				after.End = t.Add(time.Nanosecond)
+			}
+		}
+	}
+	return []timePeriod{before, after}
+}
+
+// findEmergingPatterns compares the itemsets frequent at minSupport in either
+// period and returns those whose support grew or shrank by at least a factor
+// of minGrowth, the largest changes first
+func findEmergingPatterns(before, after timePeriod, minSupport, minGrowth float64) []EmergingPattern {
+	beforeMiner := NewAprioriMiner(before.Dataset, minSupport)
+	beforeMiner.Mine()
+	afterMiner := NewAprioriMiner(after.Dataset, minSupport)
+	afterMiner.Mine()
+
+	seen := make(map[string]bool)
+	var patterns []EmergingPattern
+	for _, miner := range []*AprioriMiner{beforeMiner, afterMiner} {
+		for _, r := range miner.Results() {
+			key := strings.Join(r.Items, ",")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			// Counts outside the frequent sets fall back to scanning the period
+			set := toItemSet(r.Items)
+			p := EmergingPattern{
+				From:          before.Start,
+				To:            after.Start,
+				Size:          r.Size,
+				Items:         r.Items,
+				SupportBefore: beforeMiner.calculateSupport(set),
+				SupportAfter:  afterMiner.calculateSupport(set),
+			}
+			if p.SupportBefore > 0 {
+				p.GrowthRate = p.SupportAfter / p.SupportBefore
+			} else {
+				p.GrowthRate = math.Inf(1)
+			}
+			if p.GrowthRate >= minGrowth || p.GrowthRate <= 1/minGrowth {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		ci, cj := math.Abs(math.Log(patterns[i].GrowthRate)), math.Abs(math.Log(patterns[j].GrowthRate))
+		if ci != cj {
+			return ci > cj
+		}
+		return math.Max(patterns[i].SupportBefore, patterns[i].SupportAfter) >
+			math.Max(patterns[j].SupportBefore, patterns[j].SupportAfter)
+	})
+	return patterns
+}
+
+// writeEmergingCSV writes the patterns to path
+func writeEmergingCSV(path string, patterns []EmergingPattern) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "From,To,Size,Items,SupportBefore,SupportAfter,GrowthRate,Trend")
+	for _, p := range patterns {
+		trend := "growing"
+		if p.GrowthRate < 1 {
+			trend = "shrinking"
+		}
+		fmt.Fprintf(f, "%s,%s,%d,\"%s\",%f,%f,%f,%s\n",
+			p.From.Format(time.RFC3339), p.To.Format(time.RFC3339), p.Size, strings.Join(p.Items, ","),
+			p.SupportBefore, p.SupportAfter, p.GrowthRate, trend)
+	}
+	return f.Close()
+}
+
+// runEmerging implements the emerging subcommand, which reports itemsets
+// whose support changes across the periods of a timestamped dataset
+func runEmerging(args []string) error {
+	fs := flag.NewFlagSet("emerging", flag.ExitOnError)
+	split := fs.String("split", "", "compare transactions before and after this time")
+	period := fs.Duration("period", 0, "compare consecutive periods of this length instead of a split")
+	minSupport := fs.Float64("minsupport", 0.05, "minimum support of an itemset in either period")
+	minGrowth := fs.Float64("min-growth", 2, "report itemsets whose support grows or shrinks by at least this factor")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_emerging.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: emerging (-split time | -period duration) [flags] <timestamped dataset>")
+	}
+	if (*split == "") == (*period <= 0) {
+		return fmt.Errorf("exactly one of -split and -period is required")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if !(*minGrowth > 1) {
+		return fmt.Errorf("min-growth must be greater than 1")
+	}
+
+	filename := fs.Arg(0)
+	dataset, times, err := LoadTimestampedDataset(filename)
+	if err != nil {
+		return err
+	}
+	var periods []timePeriod
+	if *split != "" {
+		at, err := parseTimestamp(*split)
+		if err != nil {
+			return err
+		}
+		periods = splitAt(dataset, times, at)
+	} else {
+		periods = splitPeriods(dataset, times, *period)
+	}
+
+	var patterns []EmergingPattern
+	for i := 1; i < len(periods); i++ {
+		before, after := periods[i-1], periods[i]
+		if len(before.Dataset) == 0 || len(after.Dataset) == 0 {
+			continue
+		}
+		patterns = append(patterns, findEmergingPatterns(before, after, *minSupport, *minGrowth)...)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_emerging.csv")
+	if err := writeEmergingCSV(path, patterns); err != nil {
+		return err
+	}
+	fmt.Printf("Found %d emerging or shrinking itemsets across %d periods\n", len(patterns), len(periods))
+	fmt.Printf("Emerging patterns written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,128 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Job event types streamed to clients
+const (
+	EventLevel    = "level"
+	EventFinished = "finished"
+)
+
+// JobEvent is a progress notification emitted while a job runs
+type JobEvent struct {
+	Type   string         `json:"type"`
+	JobID  string         `json:"jobId"`
+	Time   time.Time      `json:"time"`
+	Level  *LevelProgress `json:"level,omitempty"`
+	Status string         `json:"status,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// publishEvent records ev on the job and forwards it to all subscribers.
+// The caller must hold s.mu.
+func (s *Server) publishEvent(job *Job, ev JobEvent) {
+	ev.JobID = job.ID
+	ev.Time = time.Now().UTC()
+	job.events = append(job.events, ev)
+	for _, ch := range job.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			// Slow subscribers miss intermediate levels rather than stalling the miner
+		}
+	}
+	if ev.Type == EventFinished {
+		for _, ch := range job.subscribers {
+			close(ch)
+		}
+		job.subscribers = nil
+	}
+}
+
+// subscribeEvents returns the events emitted so far for a job together with a
+// channel delivering future ones; the channel is nil once the job has finished
+func (s *Server) subscribeEvents(ns, id string) ([]JobEvent, chan JobEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || job.Namespace != ns {
+		return nil, nil, errJobNotFound
+	}
+	history := append([]JobEvent(nil), job.events...)
+	if job.Status == JobDone || job.Status == JobFailed || job.Status == JobInterrupted {
+		return history, nil, nil
+	}
+	ch := make(chan JobEvent, 64)
+	job.subscribers = append(job.subscribers, ch)
+	return history, ch, nil
+}
+
+// unsubscribeEvents detaches ch from the job when a client goes away early
+func (s *Server) unsubscribeEvents(id string, ch chan JobEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	for i, sub := range job.subscribers {
+		if sub == ch {
+			job.subscribers = append(job.subscribers[:i], job.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// handleJobEvents streams job progress as server-sent events
+func (s *Server) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	history, ch, err := s.subscribeEvents(namespaceFromRequest(r), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if ch != nil {
+		defer s.unsubscribeEvents(id, ch)
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	for _, ev := range history {
+		writeSSE(w, ev)
+	}
+	flusher.Flush()
+	if ch == nil {
+		return
+	}
+
+	for {
+		select {
+		case ev, open := <-ch:
+			if !open {
+				return
+			}
+			writeSSE(w, ev)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, ev JobEvent) {
+	data, _ := json.Marshal(ev)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+}
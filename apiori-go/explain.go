@@ -0,0 +1,185 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// parseItemList splits comma-separated items, sorted as itemsets store them
+func parseItemList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	sort.Strings(items)
+	return items
+}
+
+// readManifestFor returns the run manifest written next to a summary file
+func readManifestFor(summaryPath string) (runManifest, error) {
+	base := strings.TrimSuffix(filepath.Base(summaryPath), "_summary.csv")
+	var m runManifest
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(summaryPath), base+manifestSuffix))
+	if err != nil {
+		return m, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
+
+// runExplain implements the explain subcommand, which drills into one
+// itemset or rule of a saved result: its counts and measures, the
+// transactions containing it, and its frequent subsets and supersets
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	transactions := fs.Int("transactions", 0, "transactions of the mined dataset (default: from the run manifest)")
+	datasetPath := fs.String("dataset", "", "dataset to list the contributing transactions from (default: the one in the run manifest)")
+	sample := fs.Int("sample", 5, "contributing transactions printed in full")
+	maxIDs := fs.Int("max-ids", 50, "contributing transaction IDs listed (0 for all)")
+	maxRelatives := fs.Int("max-relatives", 10, "supersets listed, most frequent first (0 for all)")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: explain [flags] <dataset>_summary.csv \"a,b\" | \"a,b -> c\"")
+	}
+	if *sample < 0 || *maxIDs < 0 || *maxRelatives < 0 {
+		return fmt.Errorf("sample, max-ids and max-relatives must be non-negative")
+	}
+
+	path := fs.Arg(0)
+	miner, results, err := loadSavedItemsets(path, *transactions)
+	if err != nil {
+		return err
+	}
+	var antecedent, consequent, items []string
+	if left, right, isRule := strings.Cut(fs.Arg(1), "->"); isRule {
+		antecedent, consequent = parseItemList(left), parseItemList(right)
+		if len(antecedent) == 0 || len(consequent) == 0 {
+			return fmt.Errorf("a rule needs items on both sides of ->")
+		}
+		items = parseItemList(strings.Join(append(append([]string{}, antecedent...), consequent...), ","))
+		if len(items) != len(antecedent)+len(consequent) {
+			return fmt.Errorf("the sides of a rule may not share items")
+		}
+	} else {
+		items = parseItemList(fs.Arg(1))
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no items given")
+	}
+
+	byKey := make(map[string]ItemsetResult, len(results))
+	for _, r := range results {
+		byKey[strings.Join(r.Items, "\x00")] = r
+	}
+	key := strings.Join(items, "\x00")
+	r, frequent := byKey[key]
+	n := miner.transactionLen
+	fmt.Printf("Itemset {%s}\n", strings.Join(items, ","))
+	if frequent {
+		fmt.Printf("  count %d of %d transactions, support %.4f\n", r.Count, n, r.Support)
+		fmt.Printf("  all-confidence %.4f, kulczynski %.4f, cosine %.4f\n", r.AllConfidence, r.Kulczynski, r.Cosine)
+	} else {
+		fmt.Println("  not frequent in the saved result")
+	}
+
+	if consequent != nil {
+		if !frequent {
+			return fmt.Errorf("the rule's itemset is not in the saved result, so its measures are unknown")
+		}
+		for _, rule := range miner.rulesFromItemset(items, 0) {
+			if strings.Join(rule.Antecedent, ",") != strings.Join(antecedent, ",") {
+				continue
+			}
+			fmt.Printf("\nRule %s\n", rule)
+			fmt.Printf("  support %.4f, confidence %.4f, lift %.4f\n", rule.Support, rule.Confidence, rule.Lift)
+			fmt.Printf("  leverage %.4f, normalized leverage %.4f\n", rule.Leverage, rule.NormalizedLeverage)
+			fmt.Printf("  all-confidence %.4f, kulczynski %.4f, cosine %.4f\n", rule.AllConfidence, rule.Kulczynski, rule.Cosine)
+			fmt.Printf("  chi-square %.4f (p %.4g), Fisher exact p %.4g unadjusted\n", rule.ChiSquare, rule.PValue, rule.FisherPValue)
+		}
+	}
+
+	// Contributing transactions need the dataset, which the summary lacks
+	filename := *datasetPath
+	m, manifestErr := readManifestFor(path)
+	if filename == "" && manifestErr == nil {
+		filename = m.Dataset
+	}
+	if filename == "" {
+		fmt.Println("\nContributing transactions: pass -dataset to list them")
+	} else {
+		var dataset Dataset
+		opts, err := loadOptionsFromParameters(m.Parameters)
+		if err == nil {
+			if opts.Policy == "" {
+				dataset, err = LoadDataset(filename)
+			} else {
+				dataset, _, err = LoadDatasetWithOptions(filename, opts)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		set := toItemSet(items)
+		var ids []int
+		for i, t := range dataset {
+			if isSubset(set, t) {
+				ids = append(ids, i+1)
+			}
+		}
+		fmt.Printf("\nContributing transactions of %s: %d\n", filename, len(ids))
+		if frequent && len(ids) != r.Count {
+			fmt.Printf("  the saved count is %d; the dataset changed since the run\n", r.Count)
+		}
+		shown := ids
+		if *maxIDs > 0 && len(shown) > *maxIDs {
+			shown = shown[:*maxIDs]
+		}
+		idStrings := make([]string, len(shown))
+		for i, id := range shown {
+			idStrings[i] = fmt.Sprint(id)
+		}
+		if len(shown) > 0 {
+			more := ""
+			if len(shown) < len(ids) {
+				more = fmt.Sprintf(" and %d more", len(ids)-len(shown))
+			}
+			fmt.Printf("  lines %s%s\n", strings.Join(idStrings, " "), more)
+		}
+		for _, id := range ids[:min(*sample, len(ids))] {
+			fmt.Printf("  %d: %s\n", id, strings.Join(dataset[id-1], " "))
+		}
+	}
+
+	// Every frequent subset is in the result; supersets only where frequent
+	var subsets, supersets []ItemsetResult
+	set := toItemSet(items)
+	for _, other := range results {
+		switch {
+		case other.Size < len(items) && isSubset(toItemSet(other.Items), Transaction(items)):
+			subsets = append(subsets, other)
+		case other.Size > len(items) && isSubset(set, Transaction(other.Items)):
+			supersets = append(supersets, other)
+		}
+	}
+	fmt.Printf("\nFrequent subsets: %d\n", len(subsets))
+	for _, s := range subsets {
+		fmt.Printf("  {%s} support %.4f\n", strings.Join(s.Items, ","), s.Support)
+	}
+	sort.SliceStable(supersets, func(i, j int) bool { return supersets[i].Support > supersets[j].Support })
+	fmt.Printf("\nFrequent supersets: %d\n", len(supersets))
+	if *maxRelatives > 0 && len(supersets) > *maxRelatives {
+		supersets = supersets[:*maxRelatives]
+	}
+	for _, s := range supersets {
+		fmt.Printf("  {%s} support %.4f\n", strings.Join(s.Items, ","), s.Support)
+	}
+	return nil
+}
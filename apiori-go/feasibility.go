@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// candidateBytes approximates the heap taken by one candidate itemset, a
+// small map of shared item strings
+const candidateBytes = 300
+
+// Feasibility estimates the size of the first levels of a run from the item
+// supports alone
+type Feasibility struct {
+	FrequentItems int `json:"frequentItems"`
+	// Level2Candidates is exact: every pair of frequent items is a candidate
+	Level2Candidates int64 `json:"level2Candidates"`
+	// Level2Frequent counts the pairs frequent if items occurred
+	// independently; correlated items make it an underestimate
+	Level2Frequent int64 `json:"level2Frequent"`
+	// Level3Candidates joins the estimated frequent pairs sharing an item
+	// before subset pruning
+	Level3Candidates int64 `json:"level3Candidates"`
+	// Bytes is the memory of the larger of the two candidate levels
+	Bytes int64 `json:"bytes"`
+}
+
+// Candidates is the larger estimated candidate level
+func (f Feasibility) Candidates() int64 {
+	return max(f.Level2Candidates, f.Level3Candidates)
+}
+
+func (f Feasibility) String() string {
+	return fmt.Sprintf("%d frequent items, %d level-2 candidates, ~%d level-3 candidates, ~%.1f MB",
+		f.FrequentItems, f.Level2Candidates, f.Level3Candidates, float64(f.Bytes)/(1<<20))
+}
+
+// itemSupportCurve returns the supports of the items of a dataset in
+// descending order; the number of frequent 1-itemsets at a threshold t is the
+// number of entries at least t
+func itemSupportCurve(dataset Dataset) []float64 {
+	counts := make(map[string]int)
+	for _, transaction := range dataset {
+		seen := make(map[string]bool, len(transaction))
+		for _, item := range transaction {
+			if !seen[item] {
+				seen[item] = true
+				counts[item]++
+			}
+		}
+	}
+	curve := make([]float64, 0, len(counts))
+	for _, c := range counts {
+		curve = append(curve, float64(c)/float64(len(dataset)))
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(curve)))
+	return curve
+}
+
+// EstimateFeasibility estimates the candidate levels 2 and 3 of mining
+// dataset at minSupport, taking time quadratic in the frequent items
+func EstimateFeasibility(dataset Dataset, minSupport float64) Feasibility {
+	var supports []float64
+	for _, p := range itemSupportCurve(dataset) {
+		if p >= minSupport {
+			supports = append(supports, p)
+		}
+	}
+	f := Feasibility{FrequentItems: len(supports)}
+	n := int64(len(supports))
+	f.Level2Candidates = n * (n - 1) / 2
+
+	// With items in descending support, the partners of item i forming an
+	// expected frequent pair are a prefix of the items after it; the join
+	// then pairs up the partners of each first item
+	for i := range supports {
+		partners := int64(0)
+		for j := i + 1; j < len(supports) && supports[i]*supports[j] >= minSupport; j++ {
+			partners++
+		}
+		f.Level2Frequent += partners
+		f.Level3Candidates += partners * (partners - 1) / 2
+	}
+	f.Bytes = f.Candidates() * candidateBytes
+	return f
+}
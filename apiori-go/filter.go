@@ -0,0 +1,555 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed filter expression over itemsets and rules, such as
+//
+//	support > 0.1 && lift > 1.5 && contains('beer')
+//
+// Expressions combine comparisons (<, <=, >, >=, ==, !=) of numbers with
+// &&, || and !, and may call contains(item), antecedent(item) and
+// consequent(item). Itemsets provide size, count, support, allConfidence,
+// kulczynski and cosine; rules also provide confidence, lift, chiSquare,
+// pValue, fisherPValue, adjustedPValue, leverage and normalizedLeverage, and
+// their size and count cover the items of both sides.
+type Filter struct {
+	expr filterNode
+	// ruleOnly is set when the expression uses values only rules have
+	ruleOnly bool
+}
+
+// filterValue is the value of an expression: a number, a boolean or a string
+type filterValue interface{}
+
+// filterEnv resolves the identifiers and functions of an expression
+type filterEnv interface {
+	value(name string) (float64, bool)
+	call(name, item string) (bool, bool)
+}
+
+type filterNode interface {
+	eval(env filterEnv) (filterValue, error)
+}
+
+// filterFields lists the identifiers known for itemsets and for rules
+var (
+	itemsetFilterFields = []string{"size", "count", "support", "allConfidence", "kulczynski", "cosine"}
+	ruleFilterFields    = []string{"size", "count", "support", "confidence", "lift", "chiSquare", "pValue",
+		"fisherPValue", "adjustedPValue", "allConfidence", "kulczynski", "cosine", "leverage", "normalizedLeverage"}
+)
+
+// ParseFilter parses a filter expression
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{src: expr}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("filter: unexpected %q at offset %d", p.tok.text, p.tok.pos)
+	}
+	f := &Filter{expr: node}
+	isItemsetField := make(map[string]bool)
+	for _, name := range itemsetFilterFields {
+		isItemsetField[name] = true
+	}
+	known := make(map[string]bool)
+	for _, name := range ruleFilterFields {
+		known[name] = true
+	}
+	for _, name := range itemsetFilterFields {
+		known[name] = true
+	}
+	for _, name := range p.idents {
+		if !known[name] {
+			return nil, fmt.Errorf("filter: unknown value %q", name)
+		}
+		if !isItemsetField[name] {
+			f.ruleOnly = true
+		}
+	}
+	for _, name := range p.calls {
+		switch name {
+		case "contains":
+		case "antecedent", "consequent":
+			f.ruleOnly = true
+		default:
+			return nil, fmt.Errorf("filter: unknown function %q", name)
+		}
+	}
+	// Evaluating once for an empty result reports type errors such as
+	// comparing an item name with a number before any result is filtered
+	if f.AppliesToItemsets() {
+		if _, err := f.MatchItemset(ItemsetResult{}); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := f.MatchRule(Rule{}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// AppliesToItemsets reports whether the expression can be evaluated for
+// itemsets, that is whether it only uses values itemsets have
+func (f *Filter) AppliesToItemsets() bool {
+	return !f.ruleOnly
+}
+
+// MatchItemset evaluates the expression for an itemset
+func (f *Filter) MatchItemset(r ItemsetResult) (bool, error) {
+	return f.match(itemsetEnv(r))
+}
+
+// MatchRule evaluates the expression for a rule
+func (f *Filter) MatchRule(r Rule) (bool, error) {
+	return f.match(ruleEnv(r))
+}
+
+func (f *Filter) match(env filterEnv) (bool, error) {
+	v, err := f.expr.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter: expression is not a condition")
+	}
+	return b, nil
+}
+
+// FilterItemsets keeps the itemsets matching f
+func FilterItemsets(f *Filter, results []ItemsetResult) ([]ItemsetResult, error) {
+	out := make([]ItemsetResult, 0, len(results))
+	for _, r := range results {
+		ok, err := f.MatchItemset(r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// FilterRules keeps the rules matching f
+func FilterRules(f *Filter, rules []Rule) ([]Rule, error) {
+	out := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		ok, err := f.MatchRule(r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type itemsetEnv ItemsetResult
+
+func (r itemsetEnv) value(name string) (float64, bool) {
+	switch name {
+	case "size":
+		return float64(r.Size), true
+	case "count":
+		return float64(r.Count), true
+	case "support":
+		return r.Support, true
+	case "allConfidence":
+		return r.AllConfidence, true
+	case "kulczynski":
+		return r.Kulczynski, true
+	case "cosine":
+		return r.Cosine, true
+	}
+	return 0, false
+}
+
+func (r itemsetEnv) call(name, item string) (bool, bool) {
+	if name != "contains" {
+		return false, false
+	}
+	return hasItem(r.Items, item), true
+}
+
+type ruleEnv Rule
+
+func (r ruleEnv) value(name string) (float64, bool) {
+	switch name {
+	case "size":
+		return float64(len(r.Antecedent) + len(r.Consequent)), true
+	case "count":
+		return float64(r.Count), true
+	case "support":
+		return r.Support, true
+	case "confidence":
+		return r.Confidence, true
+	case "lift":
+		return r.Lift, true
+	case "chiSquare":
+		return r.ChiSquare, true
+	case "pValue":
+		return r.PValue, true
+	case "fisherPValue":
+		return r.FisherPValue, true
+	case "adjustedPValue":
+		return r.AdjustedPValue, true
+	case "allConfidence":
+		return r.AllConfidence, true
+	case "kulczynski":
+		return r.Kulczynski, true
+	case "cosine":
+		return r.Cosine, true
+	case "leverage":
+		return r.Leverage, true
+	case "normalizedLeverage":
+		return r.NormalizedLeverage, true
+	}
+	return 0, false
+}
+
+func (r ruleEnv) call(name, item string) (bool, bool) {
+	switch name {
+	case "contains":
+		return hasItem(r.Antecedent, item) || hasItem(r.Consequent, item), true
+	case "antecedent":
+		return hasItem(r.Antecedent, item), true
+	case "consequent":
+		return hasItem(r.Consequent, item), true
+	}
+	return false, false
+}
+
+// hasItem reports whether item is one of items
+func hasItem(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Expression nodes
+
+type numberNode float64
+
+func (n numberNode) eval(filterEnv) (filterValue, error) { return float64(n), nil }
+
+type stringNode string
+
+func (s stringNode) eval(filterEnv) (filterValue, error) { return string(s), nil }
+
+type identNode string
+
+func (id identNode) eval(env filterEnv) (filterValue, error) {
+	v, ok := env.value(string(id))
+	if !ok {
+		return nil, fmt.Errorf("filter: %s is not defined here", string(id))
+	}
+	return v, nil
+}
+
+type callNode struct {
+	name string
+	arg  filterNode
+}
+
+func (c callNode) eval(env filterEnv) (filterValue, error) {
+	arg, err := c.arg.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	item, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("filter: %s expects an item name", c.name)
+	}
+	v, ok := env.call(c.name, item)
+	if !ok {
+		return nil, fmt.Errorf("filter: %s is not defined here", c.name)
+	}
+	return v, nil
+}
+
+type notNode struct{ operand filterNode }
+
+func (n notNode) eval(env filterEnv) (filterValue, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("filter: ! expects a condition")
+	}
+	return !b, nil
+}
+
+type logicNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n logicNode) eval(env filterEnv) (filterValue, error) {
+	operand := func(node filterNode) (bool, error) {
+		v, err := node.eval(env)
+		if err != nil {
+			return false, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return false, fmt.Errorf("filter: %s expects conditions", n.op)
+		}
+		return b, nil
+	}
+	left, err := operand(n.left)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit as in Go
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return operand(n.right)
+}
+
+type compareNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n compareNode) eval(env filterEnv) (filterValue, error) {
+	lv, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	l, lok := lv.(float64)
+	r, rok := rv.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("filter: %s compares numbers", n.op)
+	}
+	switch n.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "==":
+		return l == r, nil
+	default:
+		return l != r, nil
+	}
+}
+
+// Parser
+
+const (
+	tokEOF = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type filterToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+type filterParser struct {
+	src    string
+	pos    int
+	tok    filterToken
+	idents []string
+	calls  []string
+}
+
+// next reads the following token into p.tok
+func (p *filterParser) next() error {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.src) {
+		p.tok = filterToken{kind: tokEOF, pos: start}
+		return nil
+	}
+	c := p.src[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.src) && strings.ContainsRune("0123456789.eE", rune(p.src[p.pos])) {
+			// An exponent may carry a sign
+			if (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') && p.pos+1 < len(p.src) && strings.ContainsRune("+-", rune(p.src[p.pos+1])) {
+				p.pos++
+			}
+			p.pos++
+		}
+		p.tok = filterToken{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case c == '\'' || c == '"':
+		end := strings.IndexByte(p.src[p.pos+1:], c)
+		if end < 0 {
+			return fmt.Errorf("filter: unterminated string at offset %d", start)
+		}
+		p.pos += end + 2
+		p.tok = filterToken{kind: tokString, text: p.src[start+1 : p.pos-1], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		p.tok = filterToken{kind: tokIdent, text: p.src[start:p.pos], pos: start}
+	default:
+		for _, op := range []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "!", "(", ")"} {
+			if strings.HasPrefix(p.src[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = filterToken{kind: tokOp, text: op, pos: start}
+				return nil
+			}
+		}
+		return fmt.Errorf("filter: unexpected %q at offset %d", string(c), start)
+	}
+	return nil
+}
+
+func (p *filterParser) isOp(ops ...string) bool {
+	if p.tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.isOp("||") {
+		if err = p.next(); err != nil {
+			break
+		}
+		var right filterNode
+		if right, err = p.parseAnd(); err == nil {
+			left = logicNode{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.isOp("&&") {
+		if err = p.next(); err != nil {
+			break
+		}
+		var right filterNode
+		if right, err = p.parseNot(); err == nil {
+			left = logicNode{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.isOp("!") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("filter: invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		return numberNode(v), p.next()
+	case tokString:
+		return stringNode(tok.text), p.next()
+	case tokIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if !p.isOp("(") {
+			p.idents = append(p.idents, tok.text)
+			return identNode(tok.text), nil
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(")") {
+			return nil, fmt.Errorf("filter: expected ) at offset %d", p.tok.pos)
+		}
+		p.calls = append(p.calls, tok.text)
+		return callNode{name: tok.text, arg: arg}, p.next()
+	case tokOp:
+		if tok.text == "(" {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOp(")") {
+				return nil, fmt.Errorf("filter: expected ) at offset %d", p.tok.pos)
+			}
+			return node, p.next()
+		}
+	}
+	if tok.kind == tokEOF {
+		return nil, fmt.Errorf("filter: unexpected end of expression")
+	}
+	return nil, fmt.Errorf("filter: unexpected %q at offset %d", tok.text, tok.pos)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Every documented field must parse and read its own value
+func TestFilterFields(t *testing.T) {
+	itemset := ItemsetResult{Size: 2, Items: []string{"beer", "diaper"}, Count: 7, Support: 0.35,
+		AllConfidence: 0.5, Kulczynski: 0.625, Cosine: 0.75}
+	itemsetValues := map[string]float64{"size": 2, "count": 7, "support": 0.35,
+		"allConfidence": 0.5, "kulczynski": 0.625, "cosine": 0.75}
+	rule := Rule{Antecedent: []string{"beer"}, Consequent: []string{"diaper", "milk"}, Count: 9, Support: 0.45,
+		Confidence: 0.8, Lift: 1.5, ChiSquare: 4.25, PValue: 0.04, FisherPValue: 0.03, AdjustedPValue: 0.06,
+		AllConfidence: 0.55, Kulczynski: 0.65, Cosine: 0.7, Leverage: 0.125, NormalizedLeverage: 0.375}
+	ruleValues := map[string]float64{"size": 3, "count": 9, "support": 0.45, "confidence": 0.8, "lift": 1.5,
+		"chiSquare": 4.25, "pValue": 0.04, "fisherPValue": 0.03, "adjustedPValue": 0.06, "allConfidence": 0.55,
+		"kulczynski": 0.65, "cosine": 0.7, "leverage": 0.125, "normalizedLeverage": 0.375}
+
+	check := func(kind, expr string, match func(*Filter) (bool, error), want bool) {
+		t.Helper()
+		f, err := ParseFilter(expr)
+		if err != nil {
+			t.Errorf("%s: ParseFilter(%q): %v", kind, expr, err)
+			return
+		}
+		if got, err := match(f); err != nil || got != want {
+			t.Errorf("%s: %q matched %v (%v), want %v", kind, expr, got, err, want)
+		}
+	}
+	for _, name := range itemsetFilterFields {
+		value, ok := itemsetValues[name]
+		if !ok {
+			t.Errorf("itemset field %s has no test value", name)
+			continue
+		}
+		check("itemset", fmt.Sprintf("%s == %g", name, value), func(f *Filter) (bool, error) {
+			if !f.AppliesToItemsets() {
+				t.Errorf("%s does not apply to itemsets", name)
+			}
+			return f.MatchItemset(itemset)
+		}, true)
+		check("itemset", fmt.Sprintf("%s != %g", name, value), func(f *Filter) (bool, error) { return f.MatchItemset(itemset) }, false)
+	}
+	for _, name := range ruleFilterFields {
+		value, ok := ruleValues[name]
+		if !ok {
+			t.Errorf("rule field %s has no test value", name)
+			continue
+		}
+		check("rule", fmt.Sprintf("%s == %g", name, value), func(f *Filter) (bool, error) { return f.MatchRule(rule) }, true)
+		check("rule", fmt.Sprintf("%s != %g", name, value), func(f *Filter) (bool, error) { return f.MatchRule(rule) }, false)
+	}
+
+	check("itemset", "contains('beer') && !contains('milk')", func(f *Filter) (bool, error) { return f.MatchItemset(itemset) }, true)
+	check("rule", "antecedent('beer') && consequent('milk') && contains('diaper')", func(f *Filter) (bool, error) { return f.MatchRule(rule) }, true)
+}
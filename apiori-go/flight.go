@@ -0,0 +1,321 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Flight tables available for every finished job
+const (
+	flightTableItemsets = "itemsets"
+	flightTableRules    = "rules"
+)
+
+// flightBatchRows bounds the rows of each record batch sent by DoGet
+const flightBatchRows = 65536
+
+// defaultFlightConfidence is used for rule tables requested without a
+// minimum confidence, matching the CLI default
+const defaultFlightConfidence = 0.6
+
+var itemsetSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "size", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "items", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "count", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "support", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "all_confidence", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "kulczynski", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "cosine", Type: arrow.PrimitiveTypes.Float64},
+}, nil)
+
+var ruleSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "antecedent", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "consequent", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "support", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "confidence", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "lift", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "chi_square", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "p_value", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "fisher_p_value", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "adjusted_p_value", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "all_confidence", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "kulczynski", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "cosine", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "leverage", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "normalized_leverage", Type: arrow.PrimitiveTypes.Float64},
+}, nil)
+
+// flightTicket identifies a result table. It is the ticket payload and may
+// also be sent as a CMD descriptor.
+type flightTicket struct {
+	Namespace     string  `json:"namespace"`
+	Job           string  `json:"job"`
+	Table         string  `json:"table"`
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+	// MaxPValue and MaxAdjustedPValue drop rules above these chi-square and
+	// adjusted Fisher p-values when set
+	MaxPValue         float64 `json:"maxPValue,omitempty"`
+	MaxAdjustedPValue float64 `json:"maxAdjustedPValue,omitempty"`
+	// MinImprovement drops rules that barely improve on their sub-rules
+	MinImprovement float64 `json:"minImprovement,omitempty"`
+}
+
+// flightService serves the itemsets and rules of finished jobs over Arrow
+// Flight on the gRPC listener, so the API key interceptors apply to it too
+type flightService struct {
+	flight.BaseFlightServer
+	server *Server
+	mem    memory.Allocator
+}
+
+func newFlightService(server *Server) *flightService {
+	return &flightService{server: server, mem: memory.NewGoAllocator()}
+}
+
+// parseDescriptor accepts PATH descriptors of the form [job, table] or
+// [namespace, job, table], and CMD descriptors holding a JSON flightTicket
+func parseDescriptor(desc *flight.FlightDescriptor) (flightTicket, error) {
+	var t flightTicket
+	switch desc.GetType() {
+	case flight.DescriptorPATH:
+		path := desc.GetPath()
+		switch len(path) {
+		case 2:
+			t = flightTicket{Job: path[0], Table: path[1]}
+		case 3:
+			t = flightTicket{Namespace: path[0], Job: path[1], Table: path[2]}
+		default:
+			return t, status.Error(codes.InvalidArgument, "descriptor path must be [namespace,] job, table")
+		}
+	case flight.DescriptorCMD:
+		if err := json.Unmarshal(desc.GetCmd(), &t); err != nil {
+			return t, status.Errorf(codes.InvalidArgument, "invalid descriptor command: %v", err)
+		}
+	default:
+		return t, status.Error(codes.InvalidArgument, "unknown descriptor type")
+	}
+	return t, validateTicket(&t)
+}
+
+// validateTicket normalizes the namespace and checks the table name
+func validateTicket(t *flightTicket) error {
+	t.Namespace = grpcNamespace(t.Namespace)
+	if t.Table != flightTableItemsets && t.Table != flightTableRules {
+		return status.Errorf(codes.InvalidArgument, "table must be %s or %s", flightTableItemsets, flightTableRules)
+	}
+	if !(t.MinConfidence >= 0 && t.MinConfidence <= 1) {
+		return status.Error(codes.InvalidArgument, "minConfidence must be in [0,1]")
+	}
+	return nil
+}
+
+// checkFlightNamespace enforces the namespace restrictions of the caller's API key,
+// which the interceptors cannot see inside descriptors and tickets
+func checkFlightNamespace(ctx context.Context, ns string) error {
+	if key := apiKeyFromContext(ctx); key != nil && !key.allowsNamespace(ns) {
+		return status.Errorf(codes.PermissionDenied, "API key %q may not access namespace %s", key.Name, ns)
+	}
+	return nil
+}
+
+func (f *flightService) flightInfo(t flightTicket, job Job) (*flight.FlightInfo, error) {
+	ticket, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	schema, records := itemsetSchema, int64(job.Itemsets)
+	if t.Table == flightTableRules {
+		// Rules are derived on DoGet, so their number is not known yet
+		schema, records = ruleSchema, -1
+	}
+	return &flight.FlightInfo{
+		Schema: flight.SerializeSchema(schema, f.mem),
+		FlightDescriptor: &flight.FlightDescriptor{
+			Type: flight.DescriptorPATH,
+			Path: []string{t.Namespace, t.Job, t.Table},
+		},
+		Endpoint:     []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		TotalRecords: records,
+		TotalBytes:   -1,
+	}, nil
+}
+
+// ListFlights lists the result tables of every finished job the caller may read
+func (f *flightService) ListFlights(criteria *flight.Criteria, stream flight.FlightService_ListFlightsServer) error {
+	for _, usage := range f.server.listNamespaces(apiKeyFromContext(stream.Context())) {
+		ns := usage.Name
+		jobs, err := f.server.listJobs(ns)
+		if err != nil {
+			continue
+		}
+		for _, job := range jobs {
+			if job.Status != JobDone && job.Status != JobInterrupted {
+				continue
+			}
+			for _, table := range []string{flightTableItemsets, flightTableRules} {
+				info, err := f.flightInfo(flightTicket{Namespace: ns, Job: job.ID, Table: table}, job)
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(info); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupJob returns the finished job a ticket refers to
+func (f *flightService) lookupJob(ctx context.Context, t flightTicket) (Job, error) {
+	if err := checkFlightNamespace(ctx, t.Namespace); err != nil {
+		return Job{}, err
+	}
+	job, ok := f.server.getJob(t.Namespace, t.Job)
+	if !ok {
+		return Job{}, grpcError(errJobNotFound)
+	}
+	if job.Status != JobDone && job.Status != JobInterrupted {
+		return Job{}, grpcError(fmt.Errorf("job is %s", job.Status))
+	}
+	return job, nil
+}
+
+// GetFlightInfo describes the result table named by the descriptor
+func (f *flightService) GetFlightInfo(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	t, err := parseDescriptor(desc)
+	if err != nil {
+		return nil, err
+	}
+	job, err := f.lookupJob(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return f.flightInfo(t, job)
+}
+
+// GetSchema returns the schema of the result table named by the descriptor
+func (f *flightService) GetSchema(ctx context.Context, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	t, err := parseDescriptor(desc)
+	if err != nil {
+		return nil, err
+	}
+	schema := itemsetSchema
+	if t.Table == flightTableRules {
+		schema = ruleSchema
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(schema, f.mem)}, nil
+}
+
+// DoGet streams a result table as Arrow record batches
+func (f *flightService) DoGet(tkt *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+	var t flightTicket
+	if err := json.Unmarshal(tkt.GetTicket(), &t); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid ticket: %v", err)
+	}
+	if err := validateTicket(&t); err != nil {
+		return err
+	}
+	job, err := f.lookupJob(stream.Context(), t)
+	if err != nil {
+		return err
+	}
+	results, err := f.server.jobResults(t.Namespace, t.Job)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	if t.Table == flightTableItemsets {
+		w := flight.NewRecordWriter(stream, ipc.WithSchema(itemsetSchema), ipc.WithAllocator(f.mem))
+		defer w.Close()
+		b := array.NewRecordBuilder(f.mem, itemsetSchema)
+		defer b.Release()
+		for start := 0; start < len(results) || start == 0; start += flightBatchRows {
+			for _, r := range results[start:min(start+flightBatchRows, len(results))] {
+				b.Field(0).(*array.Int32Builder).Append(int32(r.Size))
+				appendStrings(b.Field(1).(*array.ListBuilder), r.Items)
+				b.Field(2).(*array.Int64Builder).Append(int64(r.Count))
+				b.Field(3).(*array.Float64Builder).Append(r.Support)
+				b.Field(4).(*array.Float64Builder).Append(r.AllConfidence)
+				b.Field(5).(*array.Float64Builder).Append(r.Kulczynski)
+				b.Field(6).(*array.Float64Builder).Append(r.Cosine)
+			}
+			if err := writeRecord(w, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	minConfidence := t.MinConfidence
+	if minConfidence == 0 {
+		minConfidence = defaultFlightConfidence
+	}
+	miner := minerFromResults(results, job.MinSupport)
+	rules := miner.GenerateRules(minConfidence)
+	if t.MinImprovement > 0 {
+		rules = miner.FilterByImprovement(rules, t.MinImprovement)
+	}
+	if t.MaxPValue > 0 || t.MaxAdjustedPValue > 0 {
+		maxPValue, maxAdjusted := 1.0, 1.0
+		if t.MaxPValue > 0 {
+			maxPValue = t.MaxPValue
+		}
+		if t.MaxAdjustedPValue > 0 {
+			maxAdjusted = t.MaxAdjustedPValue
+		}
+		rules = FilterSignificantRules(rules, maxPValue, maxAdjusted)
+	}
+	w := flight.NewRecordWriter(stream, ipc.WithSchema(ruleSchema), ipc.WithAllocator(f.mem))
+	defer w.Close()
+	b := array.NewRecordBuilder(f.mem, ruleSchema)
+	defer b.Release()
+	for start := 0; start < len(rules) || start == 0; start += flightBatchRows {
+		for _, r := range rules[start:min(start+flightBatchRows, len(rules))] {
+			appendStrings(b.Field(0).(*array.ListBuilder), r.Antecedent)
+			appendStrings(b.Field(1).(*array.ListBuilder), r.Consequent)
+			b.Field(2).(*array.Float64Builder).Append(r.Support)
+			b.Field(3).(*array.Float64Builder).Append(r.Confidence)
+			b.Field(4).(*array.Float64Builder).Append(r.Lift)
+			b.Field(5).(*array.Float64Builder).Append(r.ChiSquare)
+			b.Field(6).(*array.Float64Builder).Append(r.PValue)
+			b.Field(7).(*array.Float64Builder).Append(r.FisherPValue)
+			b.Field(8).(*array.Float64Builder).Append(r.AdjustedPValue)
+			b.Field(9).(*array.Float64Builder).Append(r.AllConfidence)
+			b.Field(10).(*array.Float64Builder).Append(r.Kulczynski)
+			b.Field(11).(*array.Float64Builder).Append(r.Cosine)
+			b.Field(12).(*array.Float64Builder).Append(r.Leverage)
+			b.Field(13).(*array.Float64Builder).Append(r.NormalizedLeverage)
+		}
+		if err := writeRecord(w, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendStrings(b *array.ListBuilder, values []string) {
+	b.Append(true)
+	vb := b.ValueBuilder().(*array.StringBuilder)
+	for _, v := range values {
+		vb.Append(v)
+	}
+}
+
+// writeRecord sends the rows accumulated in b as one record batch
+func writeRecord(w *flight.Writer, b *array.RecordBuilder) error {
+	rec := b.NewRecord()
+	defer rec.Release()
+	return w.Write(rec)
+}
@@ -0,0 +1,233 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// logFollower reads the lines appended to a file, reopening it when it is
+// replaced or truncated, like tail -F
+type logFollower struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial string
+	// fromEnd skips the content present when the file is first opened
+	fromEnd bool
+}
+
+// poll returns the complete lines appended since the last call. A missing
+// file yields no lines until it appears.
+func (f *logFollower) poll() ([]string, error) {
+	if f.file == nil {
+		file, err := os.Open(f.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.file, f.reader, f.offset, f.partial = file, bufio.NewReader(file), 0, ""
+		if f.fromEnd {
+			if f.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+				return nil, err
+			}
+			f.fromEnd = false
+		}
+	}
+
+	var lines []string
+	for {
+		chunk, err := f.reader.ReadString('\n')
+		f.offset += int64(len(chunk))
+		if err == io.EOF {
+			f.partial += chunk
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, strings.TrimRight(f.partial+chunk, "\r\n"))
+		f.partial = ""
+	}
+
+	// The old file is drained; switch to a replacement or restart a
+	// truncated one on the next poll
+	current, err := os.Stat(f.path)
+	open, openErr := f.file.Stat()
+	switch {
+	case err != nil || openErr != nil || !os.SameFile(current, open):
+		f.file.Close()
+		f.file = nil
+	case current.Size() < f.offset:
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return lines, err
+		}
+		f.reader.Reset(f.file)
+		f.offset, f.partial = 0, ""
+	}
+	return lines, nil
+}
+
+func (f *logFollower) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// transactionWindow keeps the most recent transactions, at most size of them
+// and none older than maxAge when it is positive
+type transactionWindow struct {
+	size         int
+	maxAge       time.Duration
+	transactions Dataset
+	arrived      []time.Time
+}
+
+func (w *transactionWindow) add(t Transaction, now time.Time) {
+	w.transactions = append(w.transactions, t)
+	w.arrived = append(w.arrived, now)
+	if over := len(w.transactions) - w.size; over > 0 {
+		w.transactions, w.arrived = w.transactions[over:], w.arrived[over:]
+	}
+}
+
+// snapshot expires old transactions and returns a copy of the window
+func (w *transactionWindow) snapshot(now time.Time) Dataset {
+	if w.maxAge > 0 {
+		i := 0
+		for i < len(w.arrived) && now.Sub(w.arrived[i]) > w.maxAge {
+			i++
+		}
+		w.transactions, w.arrived = w.transactions[i:], w.arrived[i:]
+	}
+	return append(Dataset{}, w.transactions...)
+}
+
+// followStatus describes the current snapshot for dashboards
+type followStatus struct {
+	Source       string    `json:"source"`
+	MinedAt      time.Time `json:"minedAt"`
+	Transactions int       `json:"transactions"`
+	Itemsets     int       `json:"itemsets"`
+	// LinesRead counts every transaction read since the follower started
+	LinesRead int64 `json:"linesRead"`
+}
+
+// writeFollowSnapshot mines the window and replaces the current summary and
+// status in dir; readers see either the old or the new files
+func writeFollowSnapshot(ctx context.Context, dir string, dataset Dataset, minSupport float64, status followStatus) error {
+	miner := NewAprioriMiner(dataset, minSupport)
+	if err := miner.MineContext(ctx); err != nil {
+		return err
+	}
+	results := miner.Results()
+	var summary bytes.Buffer
+	if err := WriteResultsCSV(&summary, results); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "current_summary.csv"), summary.Bytes()); err != nil {
+		return err
+	}
+	status.Itemsets = len(results)
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, "current.json"), append(data, '\n'))
+}
+
+// runFollow implements the follow subcommand, which tails an append-only
+// transaction log and keeps a snapshot of the frequent itemsets of its most
+// recent transactions up to date
+func runFollow(args []string) error {
+	fs := flag.NewFlagSet("follow", flag.ExitOnError)
+	window := fs.Int("window", 10000, "most recent transactions mined")
+	maxAge := fs.Duration("max-age", 0, "also drop transactions read longer ago than this (0 keeps them while in the window)")
+	interval := fs.Duration("interval", time.Minute, "how often the window is mined when new transactions arrived")
+	pollInterval := fs.Duration("poll", time.Second, "how often the log is checked for new lines")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support")
+	fromEnd := fs.Bool("from-end", false, "skip the transactions already in the log, like tail -n 0")
+	outputDir := fs.String("output-dir", filepath.Join("results", "follow"), "directory receiving current_summary.csv and current.json")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: follow [flags] <transaction log>")
+	}
+	if *window < 1 || *interval <= 0 || *pollInterval <= 0 || *maxAge < 0 {
+		return fmt.Errorf("window must be at least 1, interval and poll positive and max-age non-negative")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+
+	source := fs.Arg(0)
+	follower := &logFollower{path: source, fromEnd: *fromEnd}
+	defer follower.Close()
+	transactions := &transactionWindow{size: *window, maxAge: *maxAge}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	poll := time.NewTicker(*pollInterval)
+	defer poll.Stop()
+	log.Printf("Following %s into %s", source, *outputDir)
+
+	var read int64
+	fresh := false
+	lastMined := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopped following %s after %d transactions", source, read)
+			return nil
+		case <-poll.C:
+		}
+		now := time.Now()
+		lines, err := follower.poll()
+		if err != nil {
+			log.Printf("Reading %s: %v", source, err)
+		}
+		for _, line := range lines {
+			if items := strings.Fields(line); len(items) > 0 {
+				transactions.add(items, now)
+				read++
+				fresh = true
+			}
+		}
+		if !fresh || now.Sub(lastMined) < *interval {
+			continue
+		}
+		dataset := transactions.snapshot(now)
+		if len(dataset) == 0 {
+			continue
+		}
+		status := followStatus{Source: source, MinedAt: now.UTC(), Transactions: len(dataset), LinesRead: read}
+		if err := writeFollowSnapshot(ctx, *outputDir, dataset, *minSupport, status); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Mining the window failed: %v", err)
+			}
+			continue
+		}
+		log.Printf("Mined the last %d transactions into %s", len(dataset), filepath.Join(*outputDir, "current_summary.csv"))
+		fresh, lastMined = false, now
+	}
+}
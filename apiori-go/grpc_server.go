@@ -0,0 +1,260 @@
+//go:build !js
+
+package main
+
+//go:generate protoc -I proto --go_out=aprioripb --go_opt=paths=source_relative --go-grpc_out=aprioripb --go-grpc_opt=paths=source_relative apriori.proto
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"algo-project/aprioripb"
+
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcService implements the Apriori gRPC service on top of a Server
+type grpcService struct {
+	aprioripb.UnimplementedAprioriServer
+	server *Server
+}
+
+// newGRPCServer creates a gRPC server exposing the API of server and its
+// results over Arrow Flight, requiring API keys when auth is non-nil and rate
+// limiting callers when limiter is
+func newGRPCServer(server *Server, auth *apiKeyAuth, limiter *rateLimiter) *grpc.Server {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if auth != nil {
+		unary = append(unary, auth.UnaryInterceptor)
+		stream = append(stream, auth.StreamInterceptor)
+	}
+	if limiter != nil {
+		unary = append(unary, limiter.UnaryInterceptor)
+		stream = append(stream, limiter.StreamInterceptor)
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if server.limits.MaxDatasetBytes > 0 {
+		// Leave room for the other fields of an upload request
+		opts = append(opts, grpc.MaxRecvMsgSize(int(server.limits.MaxDatasetBytes)+64*1024))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	aprioripb.RegisterAprioriServer(grpcServer, &grpcService{server: server})
+	flight.RegisterFlightServiceServer(grpcServer, newFlightService(server))
+	return grpcServer
+}
+
+func (g *grpcService) UploadDataset(ctx context.Context, req *aprioripb.UploadDatasetRequest) (*aprioripb.Dataset, error) {
+	info, err := g.server.uploadDataset(grpcNamespace(req.GetNamespace()), req.GetName(), req.GetContent())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return datasetToProto(*info), nil
+}
+
+func (g *grpcService) RegisterDataset(ctx context.Context, req *aprioripb.RegisterDatasetRequest) (*aprioripb.Dataset, error) {
+	info, err := g.server.importDataset(grpcNamespace(req.GetNamespace()), req.GetName(), req.GetPath())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return datasetToProto(*info), nil
+}
+
+func (g *grpcService) ListDatasets(ctx context.Context, req *aprioripb.ListDatasetsRequest) (*aprioripb.ListDatasetsResponse, error) {
+	list, err := g.server.listDatasets(grpcNamespace(req.GetNamespace()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &aprioripb.ListDatasetsResponse{}
+	for _, info := range list {
+		resp.Datasets = append(resp.Datasets, datasetToProto(info))
+	}
+	return resp, nil
+}
+
+func (g *grpcService) SubmitJob(ctx context.Context, req *aprioripb.SubmitJobRequest) (*aprioripb.Job, error) {
+	jobReq := JobRequest{
+		Dataset:    req.GetDataset(),
+		MinSupport: req.GetMinSupport(),
+		WebhookURL: req.GetWebhookUrl(),
+	}
+	if key := apiKeyFromContext(ctx); key != nil {
+		jobReq.submitter = key.Name
+	}
+	job, err := g.server.submitJob(grpcNamespace(req.GetNamespace()), jobReq)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return jobToProto(job), nil
+}
+
+func (g *grpcService) GetJob(ctx context.Context, req *aprioripb.GetJobRequest) (*aprioripb.Job, error) {
+	job, ok := g.server.getJob(grpcNamespace(req.GetNamespace()), req.GetId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, errJobNotFound.Error())
+	}
+	return jobToProto(job), nil
+}
+
+func (g *grpcService) ListJobs(ctx context.Context, req *aprioripb.ListJobsRequest) (*aprioripb.ListJobsResponse, error) {
+	list, err := g.server.listJobs(grpcNamespace(req.GetNamespace()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &aprioripb.ListJobsResponse{}
+	for _, job := range list {
+		resp.Jobs = append(resp.Jobs, jobToProto(job))
+	}
+	return resp, nil
+}
+
+// StreamItemsets sends the itemsets of a finished job one message at a time
+func (g *grpcService) StreamItemsets(req *aprioripb.StreamItemsetsRequest, stream aprioripb.Apriori_StreamItemsetsServer) error {
+	results, err := g.server.jobResults(grpcNamespace(req.GetNamespace()), req.GetJobId())
+	if err != nil {
+		return grpcError(err)
+	}
+	for _, r := range results {
+		if err := stream.Send(itemsetToProto(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IngestTransactions writes the streamed transactions to a dataset and, when
+// the first message asks for it, submits a job once the client closes the stream
+func (g *grpcService) IngestTransactions(stream aprioripb.Apriori_IngestTransactionsServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "no dataset name received")
+	}
+	if err != nil {
+		return err
+	}
+	ns := grpcNamespace(first.GetNamespace())
+	jobReq := JobRequest{
+		Dataset:    first.GetName(),
+		MinSupport: first.GetMinSupport(),
+		WebhookURL: first.GetWebhookUrl(),
+	}
+	if key := apiKeyFromContext(stream.Context()); key != nil {
+		jobReq.submitter = key.Name
+	}
+	if !(jobReq.MinSupport >= 0 && jobReq.MinSupport <= 1) {
+		return status.Error(codes.InvalidArgument, "min_support must be in [0,1]; 0 skips mining")
+	}
+	ingest, err := g.server.beginIngest(ns, first.GetName())
+	if err != nil {
+		return grpcError(err)
+	}
+	for msg := first; ; {
+		if err := ingest.add(transactionsFromProto(msg.GetTransactions())); err != nil {
+			ingest.abort()
+			return grpcError(err)
+		}
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			ingest.abort()
+			return err
+		}
+	}
+	info, err := ingest.commit()
+	if err != nil {
+		return grpcError(err)
+	}
+	resp := &aprioripb.IngestTransactionsResponse{Dataset: datasetToProto(*info)}
+	if jobReq.MinSupport > 0 {
+		job, err := g.server.submitJob(ns, jobReq)
+		if err != nil {
+			return grpcError(err)
+		}
+		resp.Job = jobToProto(job)
+	}
+	return stream.SendAndClose(resp)
+}
+
+func datasetToProto(info DatasetInfo) *aprioripb.Dataset {
+	return &aprioripb.Dataset{
+		Namespace:    info.Namespace,
+		Name:         info.Name,
+		Path:         info.Path,
+		Transactions: int64(info.Transactions),
+		RegisteredAt: timestamppb.New(info.RegisteredAt),
+	}
+}
+
+func jobToProto(job Job) *aprioripb.Job {
+	msg := &aprioripb.Job{
+		Id:          job.ID,
+		Namespace:   job.Namespace,
+		Dataset:     job.Dataset,
+		MinSupport:  job.MinSupport,
+		Status:      job.Status,
+		Error:       job.Error,
+		SubmittedAt: timestamppb.New(job.SubmittedAt),
+		Metrics: &aprioripb.TimingMetrics{
+			DataLoadTime:   job.Metrics.DataLoadTime,
+			ProcessingTime: job.Metrics.ProcessingTime,
+			TotalTime:      job.Metrics.TotalTime,
+		},
+		Itemsets:   int64(job.Itemsets),
+		WebhookUrl: job.WebhookURL,
+	}
+	if job.FinishedAt != nil {
+		msg.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	return msg
+}
+
+// ListNamespaces lists the namespaces the caller may access with their
+// quotas and usage
+func (g *grpcService) ListNamespaces(ctx context.Context, req *aprioripb.ListNamespacesRequest) (*aprioripb.ListNamespacesResponse, error) {
+	resp := &aprioripb.ListNamespacesResponse{}
+	for _, usage := range g.server.listNamespaces(apiKeyFromContext(ctx)) {
+		resp.Namespaces = append(resp.Namespaces, &aprioripb.Namespace{
+			Name:            usage.Name,
+			MaxDatasets:     int64(usage.Quota.MaxDatasets),
+			MaxStorageBytes: usage.Quota.MaxStorageBytes,
+			MaxActiveJobs:   int64(usage.Quota.MaxActiveJobs),
+			Datasets:        int64(usage.Datasets),
+			StorageBytes:    usage.StorageBytes,
+			ActiveJobs:      int64(usage.ActiveJobs),
+		})
+	}
+	return resp, nil
+}
+
+// grpcNamespace maps an empty namespace field to the default namespace
+func grpcNamespace(ns string) string {
+	if ns == "" {
+		return DefaultNamespace
+	}
+	return ns
+}
+
+// grpcError converts a server error into a gRPC status
+func grpcError(err error) error {
+	switch {
+	case err == errJobNotFound || err == errNamespaceNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case err == errDraining:
+		return status.Error(codes.Unavailable, err.Error())
+	case isQuotaError(err) || isLimitError(err):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case strings.HasPrefix(err.Error(), "job is "):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
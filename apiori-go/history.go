@@ -0,0 +1,379 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultHistoryPath is the results database every run is recorded in
+var defaultHistoryPath = filepath.Join("results", "history.db")
+
+// historyBucket holds one JSON-encoded HistoryRun per run ID
+var historyBucket = []byte("runs")
+
+// HistoryLevel is what one level of a recorded run counted
+type HistoryLevel struct {
+	LevelProgress
+	// Seconds is the time since the previous level was counted, generating
+	// this level's candidates included
+	Seconds float64 `json:"seconds"`
+}
+
+// HistoryRun is a run as the results database keeps it: its manifest,
+// timings, per-level counts and its most frequent itemsets
+type HistoryRun struct {
+	Manifest runManifest `json:"manifest"`
+	// Results is the base name of the run's files in the results directory
+	Results       string         `json:"results"`
+	MinSupport    float64        `json:"minSupport"`
+	MinConfidence float64        `json:"minConfidence"`
+	Metrics       TimingMetrics  `json:"metrics"`
+	Levels        []HistoryLevel `json:"levels"`
+	Itemsets      int            `json:"itemsets"`
+	Rules         int            `json:"rules"`
+	// TopItemsets are the itemsets of highest support, at most -history-top
+	TopItemsets []ItemsetResult `json:"topItemsets"`
+}
+
+// topItemsets returns the n itemsets of highest support, larger ones first
+// among equal supports
+func topItemsets(results []ItemsetResult, n int) []ItemsetResult {
+	top := append([]ItemsetResult(nil), results...)
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Support != top[j].Support {
+			return top[i].Support > top[j].Support
+		}
+		return top[i].Size > top[j].Size
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// openHistory opens the results database at path, creating it when missing.
+// A run holding it open makes others wait up to a few seconds.
+func openHistory(path string, readOnly bool) (*bolt.DB, error) {
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("no run history at %s: %v", path, err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run history %s: %v", path, err)
+	}
+	return db, nil
+}
+
+// recordHistory adds a run to the results database at path, replacing any
+// earlier run of the same ID
+func recordHistory(path string, run HistoryRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	db, err := openHistory(path, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(historyBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(run.Manifest.RunID), data)
+	})
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// historyRuns reads every recorded run, oldest first
+func historyRuns(db *bolt.DB) ([]HistoryRun, error) {
+	var runs []HistoryRun
+	err := db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var run HistoryRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return fmt.Errorf("run %s: %v", k, err)
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Manifest.CreatedAt.Before(runs[j].Manifest.CreatedAt) })
+	return runs, err
+}
+
+// historyRun reads one recorded run by its ID
+func historyRun(db *bolt.DB, id string) (HistoryRun, error) {
+	var run HistoryRun
+	err := db.View(func(tx *bolt.Tx) error {
+		var data []byte
+		if b := tx.Bucket(historyBucket); b != nil {
+			data = b.Get([]byte(id))
+		}
+		if data == nil {
+			return fmt.Errorf("no run %s in the history", id)
+		}
+		return json.Unmarshal(data, &run)
+	})
+	return run, err
+}
+
+// runHistory implements the history subcommand, which queries the results
+// database: list the recorded runs, show one, or compare two
+func runHistory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: history list|show|compare [flags]")
+	}
+	switch args[0] {
+	case "list":
+		return runHistoryList(args[1:])
+	case "show":
+		return runHistoryShow(args[1:])
+	case "compare":
+		return runHistoryCompare(args[1:])
+	}
+	return fmt.Errorf("unknown history command %q: must be list, show or compare", args[0])
+}
+
+// runHistoryList prints the recorded runs, newest last
+func runHistoryList(args []string) error {
+	fs := flag.NewFlagSet("history list", flag.ExitOnError)
+	path := fs.String("db", defaultHistoryPath, "results database")
+	dataset := fs.String("dataset", "", "only runs whose dataset path or results name contains this")
+	since := fs.Duration("since", 0, "only runs of this recent past, e.g. 168h")
+	limit := fs.Int("limit", 50, "most recent runs listed (0 lists all)")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: history list [flags]")
+	}
+	db, err := openHistory(*path, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := historyRuns(db)
+	if err != nil {
+		return err
+	}
+	var kept []HistoryRun
+	for _, run := range runs {
+		if *dataset != "" && !strings.Contains(run.Manifest.Dataset, *dataset) && !strings.Contains(run.Results, *dataset) {
+			continue
+		}
+		if *since > 0 && time.Since(run.Manifest.CreatedAt) > *since {
+			continue
+		}
+		kept = append(kept, run)
+	}
+	if *limit > 0 && len(kept) > *limit {
+		kept = kept[len(kept)-*limit:]
+	}
+	if len(kept) == 0 {
+		fmt.Println("No runs recorded")
+		return nil
+	}
+	fmt.Printf("%-28s %-20s %-24s %10s %12s %9s %10s\n", "run", "created", "results", "minsupport", "transactions", "itemsets", "total (s)")
+	for _, run := range kept {
+		note := ""
+		if run.Manifest.Partial {
+			note = "  partial"
+		}
+		fmt.Printf("%-28s %-20s %-24s %10.4f %12d %9d %10.3f%s\n", run.Manifest.RunID,
+			run.Manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"), run.Results, run.MinSupport,
+			run.Manifest.Transactions, run.Itemsets, run.Metrics.TotalTime, note)
+	}
+	return nil
+}
+
+// runHistoryShow prints everything recorded about one run
+func runHistoryShow(args []string) error {
+	fs := flag.NewFlagSet("history show", flag.ExitOnError)
+	path := fs.String("db", defaultHistoryPath, "results database")
+	asJSON := fs.Bool("json", false, "print the record as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: history show [flags] <run-id>")
+	}
+	db, err := openHistory(*path, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	run, err := historyRun(db, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	}
+
+	m := run.Manifest
+	fmt.Printf("Run %s, %s\n", m.RunID, m.CreatedAt.Local().Format(time.RFC1123))
+	fmt.Printf("  dataset:      %s (%d transactions, %d items, sha256 %.12s)\n", m.Dataset, m.Transactions, m.Items, m.DatasetSHA256)
+	fmt.Printf("  results:      %s\n", run.Results)
+	fmt.Printf("  version:      %s\n", m.Version)
+	fmt.Printf("  thresholds:   minsupport %g, minconfidence %g\n", run.MinSupport, run.MinConfidence)
+	fmt.Printf("  timings:      load %.3fs, processing %.3fs, total %.3fs\n", run.Metrics.DataLoadTime, run.Metrics.ProcessingTime, run.Metrics.TotalTime)
+	fmt.Printf("  found:        %d itemsets, %d rules\n", run.Itemsets, run.Rules)
+	if m.Partial {
+		fmt.Printf("  partial:      %s\n", m.PartialReason)
+	}
+	if len(m.Parameters) > 0 {
+		fmt.Println("\nParameters:")
+		for _, name := range sortedParameterNames(m.Parameters) {
+			fmt.Printf("  -%s=%s\n", name, m.Parameters[name])
+		}
+	}
+	if len(run.Levels) > 0 {
+		fmt.Printf("\n%6s %11s %9s %11s %9s\n", "level", "candidates", "frequent", "minsupport", "seconds")
+		for _, l := range run.Levels {
+			fmt.Printf("%6d %11d %9d %11.4f %9.3f\n", l.Level, l.Candidates, l.Frequent, l.MinSupport, l.Seconds)
+		}
+	}
+	if len(run.TopItemsets) > 0 {
+		fmt.Printf("\nTop %d itemsets:\n", len(run.TopItemsets))
+		for _, r := range run.TopItemsets {
+			fmt.Printf("  %.4f  %s\n", r.Support, strings.Join(r.Items, ", "))
+		}
+	}
+	return nil
+}
+
+// sortedParameterNames returns the names of params in order
+func sortedParameterNames(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runHistoryCompare prints how two recorded runs differ: their parameters,
+// timings, level counts and top itemsets
+func runHistoryCompare(args []string) error {
+	fs := flag.NewFlagSet("history compare", flag.ExitOnError)
+	path := fs.String("db", defaultHistoryPath, "results database")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: history compare [flags] <run-a> <run-b>")
+	}
+	db, err := openHistory(*path, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	a, err := historyRun(db, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := historyRun(db, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("a: %s (%s)\nb: %s (%s)\n", a.Manifest.RunID, a.Results, b.Manifest.RunID, b.Results)
+	if a.Manifest.DatasetSHA256 != b.Manifest.DatasetSHA256 {
+		fmt.Printf("\nThe datasets differ: %s (%d transactions) and %s (%d transactions)\n",
+			a.Manifest.Dataset, a.Manifest.Transactions, b.Manifest.Dataset, b.Manifest.Transactions)
+	}
+
+	names := make(map[string]bool)
+	for name := range a.Manifest.Parameters {
+		names[name] = true
+	}
+	for name := range b.Manifest.Parameters {
+		names[name] = true
+	}
+	var changed []string
+	for name := range names {
+		// Every run has its own ID
+		if name != "run-id" && a.Manifest.Parameters[name] != b.Manifest.Parameters[name] {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	if len(changed) > 0 {
+		fmt.Println("\nParameters:")
+		for _, name := range changed {
+			fmt.Printf("  -%s: %q -> %q\n", name, a.Manifest.Parameters[name], b.Manifest.Parameters[name])
+		}
+	}
+
+	fmt.Printf("\n%-16s %12s %12s %12s\n", "", "a", "b", "change")
+	row := func(label string, x, y float64, format string) {
+		change := ""
+		if x != 0 {
+			change = fmt.Sprintf("%+.1f%%", (y-x)/x*100)
+		}
+		fmt.Printf("%-16s %12s %12s %12s\n", label, fmt.Sprintf(format, x), fmt.Sprintf(format, y), change)
+	}
+	row("load (s)", a.Metrics.DataLoadTime, b.Metrics.DataLoadTime, "%.3f")
+	row("processing (s)", a.Metrics.ProcessingTime, b.Metrics.ProcessingTime, "%.3f")
+	row("total (s)", a.Metrics.TotalTime, b.Metrics.TotalTime, "%.3f")
+	row("itemsets", float64(a.Itemsets), float64(b.Itemsets), "%.0f")
+	row("rules", float64(a.Rules), float64(b.Rules), "%.0f")
+	for k := 0; k < max(len(a.Levels), len(b.Levels)); k++ {
+		var x, y HistoryLevel
+		if k < len(a.Levels) {
+			x = a.Levels[k]
+		}
+		if k < len(b.Levels) {
+			y = b.Levels[k]
+		}
+		row(fmt.Sprintf("level %d frequent", k+1), float64(x.Frequent), float64(y.Frequent), "%.0f")
+	}
+
+	// Top itemsets present in one run only, or whose support moved
+	supports := make(map[string]float64)
+	for _, r := range a.TopItemsets {
+		supports[strings.Join(r.Items, ",")] = r.Support
+	}
+	var lines []string
+	seen := make(map[string]bool)
+	for _, r := range b.TopItemsets {
+		key := strings.Join(r.Items, ",")
+		seen[key] = true
+		if support, ok := supports[key]; !ok {
+			lines = append(lines, fmt.Sprintf("  + %s (%.4f)", key, r.Support))
+		} else if support != r.Support {
+			lines = append(lines, fmt.Sprintf("  ~ %s %.4f -> %.4f", key, support, r.Support))
+		}
+	}
+	for _, r := range a.TopItemsets {
+		if key := strings.Join(r.Items, ","); !seen[key] {
+			lines = append(lines, fmt.Sprintf("  - %s (%.4f)", key, r.Support))
+		}
+	}
+	if len(lines) > 0 {
+		fmt.Println("\nTop itemsets:")
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HoldoutRule is a rule mined on the training partition together with its
+// measures on the held-out partition
+type HoldoutRule struct {
+	Rule
+	TestSupport    float64 `json:"testSupport"`
+	TestConfidence float64 `json:"testConfidence"`
+	TestLift       float64 `json:"testLift"`
+	// Generalizes reports whether the rule still meets the confidence and
+	// lift thresholds on the held-out transactions
+	Generalizes bool `json:"generalizes"`
+}
+
+// splitDataset shuffles the transactions with seed and holds out testFraction
+// of them
+func splitDataset(dataset Dataset, testFraction float64, seed int64) (train, test Dataset) {
+	order := rand.New(rand.NewSource(seed)).Perm(len(dataset))
+	testSize := int(float64(len(dataset))*testFraction + 0.5)
+	for i, idx := range order {
+		if i < testSize {
+			test = append(test, dataset[idx])
+		} else {
+			train = append(train, dataset[idx])
+		}
+	}
+	return train, test
+}
+
+// evaluateHoldout measures rules on the test transactions. A rule
+// generalizes when its test confidence is at least minConfidence and its test
+// lift exceeds minLift.
+func evaluateHoldout(rules []Rule, test Dataset, minConfidence, minLift float64) []HoldoutRule {
+	// The unmined miner counts supports by scanning the test transactions
+	counter := NewAprioriMiner(test, 1)
+	total := float64(len(test))
+	out := make([]HoldoutRule, 0, len(rules))
+	for _, rule := range rules {
+		h := HoldoutRule{Rule: rule}
+		antecedent := toItemSet(rule.Antecedent)
+		consequent := toItemSet(rule.Consequent)
+		both := toItemSet(append(append([]string{}, rule.Antecedent...), rule.Consequent...))
+		if total > 0 {
+			h.TestSupport = float64(counter.countSupport(both)) / total
+			if a := counter.countSupport(antecedent); a > 0 {
+				h.TestConfidence = h.TestSupport / (float64(a) / total)
+			}
+			if c := counter.countSupport(consequent); c > 0 {
+				h.TestLift = h.TestConfidence / (float64(c) / total)
+			}
+		}
+		h.Generalizes = h.TestConfidence >= minConfidence && h.TestLift > minLift
+		out = append(out, h)
+	}
+	return out
+}
+
+// writeHoldoutCSV writes the evaluated rules to path
+func writeHoldoutCSV(path string, rules []HoldoutRule) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Antecedent,Consequent,TrainSupport,TrainConfidence,TrainLift,TestSupport,TestConfidence,TestLift,Generalizes")
+	for _, r := range rules {
+		fmt.Fprintf(f, "\"%s\",\"%s\",%f,%f,%f,%f,%f,%f,%t\n",
+			strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","),
+			r.Support, r.Confidence, r.Lift, r.TestSupport, r.TestConfidence, r.TestLift, r.Generalizes)
+	}
+	return f.Close()
+}
+
+// runHoldout implements the holdout subcommand, which mines rules on a
+// training partition of the dataset and reports how they hold up on the rest
+func runHoldout(args []string) error {
+	fs := flag.NewFlagSet("holdout", flag.ExitOnError)
+	testFraction := fs.Float64("test-fraction", 0.3, "fraction of transactions held out for evaluation")
+	seed := fs.Int64("seed", 1, "seed of the random split")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support on the training partition")
+	minConfidence := fs.Float64("minconfidence", 0.6, "minimum confidence, on training and held-out transactions")
+	minLift := fs.Float64("min-test-lift", 1, "held-out lift a rule must exceed to generalize")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_holdout.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: holdout [flags] <dataset>")
+	}
+	if !(*testFraction > 0 && *testFraction < 1) {
+		return fmt.Errorf("test-fraction must be in (0,1)")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	train, test := splitDataset(dataset, *testFraction, *seed)
+	if len(train) == 0 || len(test) == 0 {
+		return fmt.Errorf("dataset of %d transactions is too small to split", len(dataset))
+	}
+
+	miner := NewAprioriMiner(train, *minSupport)
+	miner.Mine()
+	rules := evaluateHoldout(miner.GenerateRules(*minConfidence), test, *minConfidence, *minLift)
+
+	generalizing := 0
+	for _, r := range rules {
+		if r.Generalizes {
+			generalizing++
+		}
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_holdout.csv")
+	if err := writeHoldoutCSV(path, rules); err != nil {
+		return err
+	}
+	fmt.Printf("Mined %d rules on %d training transactions; %d of them generalize to %d held-out transactions\n",
+		len(rules), len(train), generalizing, len(test))
+	fmt.Printf("Rule evaluation written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,85 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// datasetIngest accumulates streamed transactions in a temporary file that
+// becomes a registered dataset on commit
+type datasetIngest struct {
+	server *Server
+	ns     string
+	name   string
+	file   *os.File
+	w      *bufio.Writer
+	bytes  int64
+}
+
+// beginIngest starts a dataset called name in ns fed by streamed transactions
+func (s *Server) beginIngest(ns, name string) (*datasetIngest, error) {
+	if !datasetNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid dataset name")
+	}
+	s.mu.Lock()
+	err := s.checkDatasetQuotaLocked(ns, name, 0)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(s.dataDir, "datasets", ns)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	file, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	return &datasetIngest{server: s, ns: ns, name: name, file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// add appends transactions in the plain text format
+func (d *datasetIngest) add(transactions Dataset) error {
+	for _, transaction := range transactions {
+		for _, item := range transaction {
+			if item == "" || strings.ContainsAny(item, " \t\r\n\v\f") {
+				return fmt.Errorf("item %q is empty or contains whitespace", item)
+			}
+		}
+		line := strings.Join(transaction, " ") + "\n"
+		d.bytes += int64(len(line))
+		if err := d.server.checkDatasetSize(d.bytes); err != nil {
+			return err
+		}
+		if _, err := d.w.WriteString(line); err != nil {
+			return fmt.Errorf("failed to store dataset: %v", err)
+		}
+	}
+	return nil
+}
+
+// commit registers the accumulated transactions, moving them into place only
+// once they fit the namespace's quota
+func (d *datasetIngest) commit() (*DatasetInfo, error) {
+	if err := d.w.Flush(); err != nil {
+		d.abort()
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	if err := d.file.Close(); err != nil {
+		os.Remove(d.file.Name())
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	path := filepath.Join(filepath.Dir(d.file.Name()), d.name+".txt")
+	return d.server.installDataset(d.ns, d.name, d.file.Name(), path)
+}
+
+// abort discards the accumulated transactions
+func (d *datasetIngest) abort() {
+	d.file.Close()
+	os.Remove(d.file.Name())
+}
@@ -0,0 +1,237 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serverState is the persisted form of the server's datasets and jobs
+type serverState struct {
+	Namespaces []*Namespace   `json:"namespaces"`
+	Datasets   []*DatasetInfo `json:"datasets"`
+	Jobs       []*Job         `json:"jobs"`
+}
+
+func (s *Server) statePath() string {
+	return filepath.Join(s.dataDir, "state.json")
+}
+
+func (s *Server) resultsPath(id string) string {
+	return filepath.Join(s.dataDir, "results", id+".json")
+}
+
+// loadState restores datasets and jobs saved by a previous process and
+// re-queues every job that had not finished
+func (s *Server) loadState() error {
+	data, err := os.ReadFile(s.statePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read server state: %v", err)
+	}
+	var state serverState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse server state: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ns := range state.Namespaces {
+		s.namespaces[ns.Name] = ns
+	}
+	// State written before namespaces existed belongs to the default namespace
+	for _, info := range state.Datasets {
+		if info.Namespace == "" {
+			info.Namespace = DefaultNamespace
+		}
+		s.datasets[datasetKey(info.Namespace, info.Name)] = info
+	}
+	sort.Slice(state.Jobs, func(i, j int) bool {
+		return state.Jobs[i].SubmittedAt.Before(state.Jobs[j].SubmittedAt)
+	})
+	for _, job := range state.Jobs {
+		if job.Namespace == "" {
+			job.Namespace = DefaultNamespace
+		}
+		s.jobs[job.ID] = job
+		if job.Status == JobQueued || job.Status == JobRunning || job.Status == JobInterrupted {
+			job.Status = JobQueued
+			job.StartedAt = nil
+			s.enqueueLocked(job)
+		}
+	}
+	if len(s.pending) > 0 {
+		log.Printf("Resuming %d unfinished jobs", len(s.pending))
+	}
+	return nil
+}
+
+// saveStateLocked writes datasets and jobs to disk. The caller must hold s.mu.
+func (s *Server) saveStateLocked() error {
+	state := serverState{}
+	for _, ns := range s.namespaces {
+		state.Namespaces = append(state.Namespaces, ns)
+	}
+	for _, info := range s.datasets {
+		state.Datasets = append(state.Datasets, info)
+	}
+	for _, job := range s.jobs {
+		state.Jobs = append(state.Jobs, job)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.statePath(), data)
+}
+
+// enqueueLocked appends job to the queue. The caller must hold s.mu.
+func (s *Server) enqueueLocked(job *Job) {
+	s.pending = append(s.pending, job)
+	s.wake.Signal()
+}
+
+// worker runs queued jobs one at a time until the server starts draining
+func (s *Server) worker() {
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.draining {
+			s.wake.Wait()
+		}
+		if s.draining {
+			s.mu.Unlock()
+			return
+		}
+		s.running.Add(1)
+		job := s.pending[0]
+		s.pending = s.pending[1:]
+		info, ok := s.datasets[datasetKey(job.Namespace, job.Dataset)]
+		now := time.Now().UTC()
+		job.Status = JobRunning
+		job.StartedAt = &now
+		if err := s.saveStateLocked(); err != nil {
+			log.Printf("failed to save server state: %v", err)
+		}
+		s.mu.Unlock()
+
+		if ok {
+			s.runJob(job, *info)
+		} else {
+			s.finishJob(job, nil, TimingMetrics{}, fmt.Errorf("unknown dataset %q", job.Dataset))
+		}
+		s.running.Done()
+	}
+}
+
+// runJob loads the dataset, mines it and records the outcome on the job
+func (s *Server) runJob(job *Job, info DatasetInfo) {
+	ctx, span := tracer.Start(s.ctx, "job", trace.WithAttributes(
+		attribute.String("apriori.job_id", job.ID),
+		attribute.String("apriori.namespace", job.Namespace),
+		attribute.String("apriori.dataset", job.Dataset)))
+	defer span.End()
+
+	startTime := time.Now()
+	var dataset Dataset
+	err := traced(ctx, "load", func(context.Context) error {
+		var err error
+		dataset, err = LoadDataset(info.Path)
+		return err
+	}, attribute.String("apriori.path", info.Path))
+	loadTime := time.Since(startTime)
+	if err != nil {
+		endSpanError(span, err)
+		s.finishJob(job, nil, TimingMetrics{}, fmt.Errorf("failed to load dataset: %v", err))
+		return
+	}
+
+	processStart := time.Now()
+	miner := NewAprioriMiner(dataset, job.MinSupport)
+	miner.SetProgressFunc(func(p LevelProgress) {
+		s.mu.Lock()
+		s.publishEvent(job, JobEvent{Type: EventLevel, Level: &p})
+		s.mu.Unlock()
+	})
+	mineErr := miner.MineContext(ctx)
+	endSpanError(span, mineErr)
+	metrics := TimingMetrics{
+		DataLoadTime:   loadTime.Seconds(),
+		ProcessingTime: time.Since(processStart).Seconds(),
+		TotalTime:      time.Since(startTime).Seconds(),
+	}
+	results := miner.Results()
+	if mineErr == nil {
+		s.storeCachedResult(info, job.MinSupport, cachedResult{Results: results, Metrics: metrics})
+	}
+	s.finishJob(job, results, metrics, mineErr)
+}
+
+// finishJob persists the results or the error of a completed job. A
+// cancelled job keeps the results of the levels it finished.
+func (s *Server) finishJob(job *Job, results []ItemsetResult, metrics TimingMetrics, err error) {
+	interrupted := errors.Is(err, context.Canceled)
+	if err == nil || interrupted {
+		if saveErr := s.saveJobResults(job.ID, results); saveErr != nil {
+			err = fmt.Errorf("failed to save results: %v", saveErr)
+			interrupted = false
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Metrics = metrics
+	if interrupted {
+		job.Status = JobInterrupted
+		job.results = results
+		job.Itemsets = len(results)
+		log.Printf("job %s interrupted after %d itemsets", job.ID, len(results))
+	} else if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		log.Printf("job %s failed: %v", job.ID, err)
+	} else {
+		job.Status = JobDone
+		job.results = results
+		job.Itemsets = len(results)
+	}
+	if saveErr := s.saveStateLocked(); saveErr != nil {
+		log.Printf("failed to save server state: %v", saveErr)
+	}
+	s.publishEvent(job, JobEvent{Type: EventFinished, Status: job.Status, Error: job.Error})
+	s.notifyWebhook(*job)
+}
+
+func (s *Server) saveJobResults(id string, results []ItemsetResult) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.resultsPath(id), data)
+}
+
+func (s *Server) loadJobResults(id string) ([]ItemsetResult, error) {
+	data, err := os.ReadFile(s.resultsPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %v", err)
+	}
+	var results []ItemsetResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %v", err)
+	}
+	return results, nil
+}
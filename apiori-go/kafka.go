@@ -0,0 +1,94 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchSize bounds the number of rules sent in a single produce call
+const kafkaBatchSize = 500
+
+// ruleMessage is the JSON payload published for every mined rule
+type ruleMessage struct {
+	Dataset    string    `json:"dataset"`
+	MinedAt    time.Time `json:"minedAt"`
+	Antecedent []string  `json:"antecedent"`
+	Consequent []string  `json:"consequent"`
+	Support    float64   `json:"support"`
+	Confidence float64   `json:"confidence"`
+	Lift       float64   `json:"lift"`
+	ChiSquare  float64   `json:"chiSquare"`
+	PValue     float64   `json:"pValue"`
+	FisherP    float64   `json:"fisherPValue"`
+	AdjustedP  float64   `json:"adjustedPValue"`
+	AllConf    float64   `json:"allConfidence"`
+	Kulczynski float64   `json:"kulczynski"`
+	Cosine     float64   `json:"cosine"`
+	Leverage   float64   `json:"leverage"`
+	NormLev    float64   `json:"normalizedLeverage"`
+}
+
+// PublishRulesToKafka emits every rule as a JSON message on topic. Messages
+// are keyed by the rule's text so updates of the same rule land on the same
+// partition.
+func PublishRulesToKafka(brokers []string, topic, dataset string, rules []Rule) error {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+	defer writer.Close()
+
+	minedAt := time.Now().UTC()
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := writer.WriteMessages(ctx, batch...); err != nil {
+			return fmt.Errorf("failed to publish rules to kafka: %v", err)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, rule := range rules {
+		value, err := json.Marshal(ruleMessage{
+			Dataset:    dataset,
+			MinedAt:    minedAt,
+			Antecedent: rule.Antecedent,
+			Consequent: rule.Consequent,
+			Support:    rule.Support,
+			Confidence: rule.Confidence,
+			Lift:       rule.Lift,
+			ChiSquare:  rule.ChiSquare,
+			PValue:     rule.PValue,
+			FisherP:    rule.FisherPValue,
+			AdjustedP:  rule.AdjustedPValue,
+			AllConf:    rule.AllConfidence,
+			Kulczynski: rule.Kulczynski,
+			Cosine:     rule.Cosine,
+			Leverage:   rule.Leverage,
+			NormLev:    rule.NormalizedLeverage,
+		})
+		if err != nil {
+			return err
+		}
+		batch = append(batch, kafka.Message{Key: []byte(rule.String()), Value: value})
+		if len(batch) == kafkaBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SupportSchedule raises the minimum support from a level on: the threshold
+// of level k is that of the greatest scheduled level at most k
+type SupportSchedule map[int]float64
+
+// ParseSupportSchedule reads comma-separated level:support pairs such as
+// "3:0.1,5:0.2". Thresholds may not decrease with the level, which keeps the
+// subsets of every candidate in the levels below.
+func ParseSupportSchedule(s string) (SupportSchedule, error) {
+	schedule := make(SupportSchedule)
+	for _, entry := range strings.Split(s, ",") {
+		level, support, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		k, err := strconv.Atoi(level)
+		if !ok || err != nil || k < 1 {
+			return nil, fmt.Errorf("invalid schedule entry %q: expected level:support", entry)
+		}
+		p, err := strconv.ParseFloat(support, 64)
+		if err != nil || !(p > 0 && p <= 1) {
+			return nil, fmt.Errorf("invalid schedule entry %q: support must be in (0,1]", entry)
+		}
+		schedule[k] = p
+	}
+	levels := make([]int, 0, len(schedule))
+	for k := range schedule {
+		levels = append(levels, k)
+	}
+	sort.Ints(levels)
+	for i := 1; i < len(levels); i++ {
+		if schedule[levels[i]] < schedule[levels[i-1]] {
+			return nil, fmt.Errorf("schedule support of level %d is below that of level %d", levels[i], levels[i-1])
+		}
+	}
+	return schedule, nil
+}
+
+// at returns the scheduled support of level k, or 0 before the first entry
+func (s SupportSchedule) at(k int) float64 {
+	best, support := 0, 0.0
+	for level, p := range s {
+		if level <= k && level > best {
+			best, support = level, p
+		}
+	}
+	return support
+}
+
+// SetSupportSchedule raises the support threshold of deeper levels, bounding
+// their candidates while the shallow levels keep the minimum support
+func (am *AprioriMiner) SetSupportSchedule(schedule SupportSchedule) {
+	am.supportSchedule = schedule
+}
+
+// levelMinSupport is the threshold candidates of level k are counted against,
+// recording it for LevelSupports
+func (am *AprioriMiner) levelMinSupport(k int) float64 {
+	support := math.Max(am.minSupport, am.supportSchedule.at(k))
+	if am.levelSupports == nil {
+		am.levelSupports = make(map[int]float64)
+	}
+	am.levelSupports[k] = support
+	return support
+}
+
+// LevelSupports returns the threshold each counted level was mined at
+func (am *AprioriMiner) LevelSupports() map[int]float64 {
+	return am.levelSupports
+}
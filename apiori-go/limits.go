@@ -0,0 +1,188 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Limits protects the server from clients that submit too much work; zero
+// values disable the corresponding limit
+type Limits struct {
+	MaxDatasetBytes   int64
+	MaxJobsPerKey     int
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// limitError reports that a client exceeded a request or job limit
+type limitError struct {
+	msg string
+}
+
+func (e *limitError) Error() string { return e.msg }
+
+func isLimitError(err error) bool {
+	var le *limitError
+	return errors.As(err, &le)
+}
+
+// checkDatasetSize rejects datasets larger than the configured maximum
+func (s *Server) checkDatasetSize(size int64) error {
+	if s.limits.MaxDatasetBytes > 0 && size > s.limits.MaxDatasetBytes {
+		return &limitError{fmt.Sprintf("dataset of %d bytes exceeds the limit of %d bytes", size, s.limits.MaxDatasetBytes)}
+	}
+	return nil
+}
+
+// checkSubmitterLimitLocked rejects a job when its submitter already has the
+// maximum number of queued or running jobs. The caller must hold s.mu.
+func (s *Server) checkSubmitterLimitLocked(submitter string) error {
+	if s.limits.MaxJobsPerKey <= 0 || submitter == "" {
+		return nil
+	}
+	active := 0
+	for _, job := range s.jobs {
+		if job.SubmittedBy == submitter && (job.Status == JobQueued || job.Status == JobRunning) {
+			active++
+		}
+	}
+	if active >= s.limits.MaxJobsPerKey {
+		return &limitError{fmt.Sprintf("%s already has %d queued or running jobs", submitter, active)}
+	}
+	return nil
+}
+
+// tokenBucket refills at rate tokens per second up to burst
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter applies a token bucket per client
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	// auth resolves the API key of streaming gRPC calls, which auth only
+	// attaches to their context after the first message; nil without keys
+	auth *apiKeyAuth
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// bucketSweepInterval is how often allow drops the buckets of idle clients
+const bucketSweepInterval = time.Minute
+
+func newRateLimiter(rate float64, burst int, auth *apiKeyAuth) *rateLimiter {
+	if burst < 1 {
+		burst = int(rate) + 1
+	}
+	return &rateLimiter{rate: rate, burst: float64(burst), auth: auth, buckets: make(map[string]*tokenBucket)}
+}
+
+// allow consumes a token for client, returning false if none is available
+// and how long until the next one is
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets that have refilled to burst, which a new bucket
+// would start at anyway, so clients that went away are not kept forever.
+// The caller must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientID identifies the caller by API key name, falling back to its address
+func clientID(ctx context.Context, remoteAddr string) string {
+	if key := apiKeyFromContext(ctx); key != nil {
+		return "key:" + key.Name
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "addr:" + host
+}
+
+// Middleware rejects HTTP requests beyond the client's rate with 429
+func (l *rateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := l.allow(clientID(r.Context(), r.RemoteAddr)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait/time.Second)+1))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func grpcClientID(ctx context.Context) string {
+	addr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	return clientID(ctx, addr)
+}
+
+// UnaryInterceptor rejects unary gRPC calls beyond the client's rate
+func (l *rateLimiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if ok, _ := l.allow(grpcClientID(ctx)); !ok {
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rejects streaming gRPC calls beyond the client's rate,
+// identifying the caller by the key in the call's metadata
+func (l *rateLimiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	if l.auth != nil {
+		if key := l.auth.grpcKey(ctx); key != nil {
+			ctx = context.WithValue(ctx, apiKeyContextKey{}, key)
+		}
+	}
+	if ok, _ := l.allow(grpcClientID(ctx)); !ok {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,71 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// contextStream is a server stream that only carries a context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+func TestStreamRateLimitIsPerKey(t *testing.T) {
+	auth := &apiKeyAuth{keys: make(map[[sha256.Size]byte]*APIKey)}
+	for _, key := range []*APIKey{{Name: "a", Key: "secret-a"}, {Name: "b", Key: "secret-b"}} {
+		auth.keys[sha256.Sum256([]byte(key.Key))] = key
+	}
+	limiter := newRateLimiter(1e-9, 1, auth)
+	info := &grpc.StreamServerInfo{FullMethod: "/apriori.Apriori/WatchJob"}
+	handler := func(interface{}, grpc.ServerStream) error { return nil }
+	call := func(key, addr string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", key))
+		return limiter.StreamInterceptor(nil, &contextStream{ctx: ctx}, info, handler)
+	}
+
+	if err := call("secret-a", "10.0.0.1"); err != nil {
+		t.Fatalf("first stream of key a: %v", err)
+	}
+	if err := call("secret-b", "10.0.0.1"); err != nil {
+		t.Errorf("key b behind the same address shares key a's bucket: %v", err)
+	}
+	if err := call("secret-a", "10.0.0.2"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("key a from another address got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestRateLimiterDropsIdleBuckets(t *testing.T) {
+	limiter := newRateLimiter(1, 1, nil)
+	for _, client := range []string{"addr:10.0.0.1", "addr:10.0.0.2"} {
+		if ok, _ := limiter.allow(client); !ok {
+			t.Fatalf("first request of %s was limited", client)
+		}
+	}
+	// Only the bucket of 10.0.0.1 has refilled since its request
+	limiter.buckets["addr:10.0.0.1"].last = time.Now().Add(-time.Hour)
+	limiter.lastSweep = time.Now().Add(-2 * bucketSweepInterval)
+	if ok, _ := limiter.allow("addr:10.0.0.3"); !ok {
+		t.Fatal("first request of addr:10.0.0.3 was limited")
+	}
+	if _, ok := limiter.buckets["addr:10.0.0.1"]; ok {
+		t.Error("the idle bucket of addr:10.0.0.1 was kept")
+	}
+	if _, ok := limiter.buckets["addr:10.0.0.2"]; !ok {
+		t.Error("the empty bucket of addr:10.0.0.2 was dropped")
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Policies for malformed dataset lines
+const (
+	// LoadKeep reports malformed lines but keeps them as read, like LoadDataset
+	LoadKeep = "keep"
+	// LoadStrict fails on the first malformed line
+	LoadStrict = "strict"
+	// LoadSkip drops malformed lines
+	LoadSkip = "skip"
+	// LoadRepair cleans malformed lines and keeps them
+	LoadRepair = "repair"
+)
+
+// strayDelimiters are separators from other formats that end up glued to
+// items when a file is split on whitespace, as in "bread,milk" or "milk;"
+const strayDelimiters = ",;|"
+
+// LoadOptions controls how LoadDatasetWithOptions reads a dataset
+type LoadOptions struct {
+	Policy string
+	// Normalize puts items in Unicode NFC, trims them and joins internal
+	// whitespace with underscores, so differently encoded spellings of an
+	// item are counted together
+	Normalize bool
+	// Transliterate, when set, rewrites substrings of items after
+	// normalization, e.g. to fold "é" into "e"
+	Transliterate *strings.Replacer
+	// Encoding names the character encoding of the file, such as latin1 or
+	// gbk, converted to UTF-8 while reading; empty means UTF-8
+	Encoding string
+}
+
+// utf8BOM is the byte order mark some tools start UTF-8 files with
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// inputFile is an open input file read through its encoding conversion
+type inputFile struct {
+	io.Reader
+	io.Closer
+}
+
+// openInput opens a file for reading as UTF-8, converting it from encoding
+// (any WHATWG label, such as latin1, windows-1252, gbk or shift_jis) and
+// dropping a leading byte order mark
+func openInput(filename, encoding string) (*inputFile, error) {
+	var decode transform.Transformer
+	if encoding != "" {
+		label := strings.ToLower(encoding)
+		label = strings.Replace(label, "latin-", "latin", 1)
+		enc, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, fmt.Errorf("unknown encoding %q", encoding)
+		}
+		decode = enc.NewDecoder()
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = file
+	if decode != nil {
+		r = transform.NewReader(file, decode)
+	}
+	buffered := bufio.NewReader(r)
+	if start, _ := buffered.Peek(len(utf8BOM)); bytes.Equal(start, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	return &inputFile{Reader: buffered, Closer: file}, nil
+}
+
+// LoadTransliteration reads a transliteration table with one "from to" pair
+// per line; a line with a single field deletes it. Blank lines and lines
+// starting with # are ignored.
+func LoadTransliteration(filename string) (*strings.Replacer, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []string
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(fields[0], "#"):
+			continue
+		case len(fields) > 2:
+			return nil, fmt.Errorf("%s:%d: expected \"from to\"", filename, i+1)
+		}
+		from := norm.NFC.String(fields[0])
+		to := ""
+		if len(fields) == 2 {
+			to = norm.NFC.String(fields[1])
+		}
+		pairs = append(pairs, from, to)
+	}
+	return strings.NewReplacer(pairs...), nil
+}
+
+// normalizeItem applies the normalization of opts to an item
+func normalizeItem(item string, opts LoadOptions) string {
+	if opts.Normalize {
+		item = strings.Join(strings.Fields(norm.NFC.String(item)), "_")
+	}
+	if opts.Transliterate != nil {
+		item = opts.Transliterate.Replace(item)
+	}
+	return item
+}
+
+// DataQualityReport counts the problems found while loading a dataset
+type DataQualityReport struct {
+	Lines            int `json:"lines"`
+	Transactions     int `json:"transactions"`
+	EmptyLines       int `json:"emptyLines"`
+	InvalidUTF8Lines int `json:"invalidUtf8Lines"`
+	DelimiterLines   int `json:"delimiterLines"`
+	SkippedLines     int `json:"skippedLines"`
+	CleanedItems     int `json:"cleanedItems"`
+	DroppedItems     int `json:"droppedItems"`
+	NormalizedItems  int `json:"normalizedItems"`
+}
+
+// LoadDatasetWithOptions loads transactions from a file, applying the policy
+// to empty lines, lines with bytes that are not UTF-8 and items carrying
+// stray delimiters, normalizing the items, and reports what it found
+func LoadDatasetWithOptions(filename string, opts LoadOptions) (Dataset, DataQualityReport, error) {
+	var report DataQualityReport
+	switch opts.Policy {
+	case LoadKeep, LoadStrict, LoadSkip, LoadRepair:
+	default:
+		return nil, report, fmt.Errorf("load policy must be %s, %s, %s or %s", LoadKeep, LoadStrict, LoadSkip, LoadRepair)
+	}
+	file, err := openInput(filename, opts.Encoding)
+	if err != nil {
+		return nil, report, err
+	}
+	defer file.Close()
+
+	var dataset Dataset
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		report.Lines++
+		line := scanner.Text()
+		items, problem := cleanLine(line, &report)
+		if problem != "" {
+			switch opts.Policy {
+			case LoadStrict:
+				return nil, report, fmt.Errorf("%s:%d: %s", filename, report.Lines, problem)
+			case LoadSkip:
+				report.SkippedLines++
+				continue
+			case LoadKeep:
+				items = strings.Fields(line)
+			}
+		}
+		if opts.Normalize || opts.Transliterate != nil {
+			normalized := items[:0]
+			for _, item := range items {
+				n := normalizeItem(item, opts)
+				if n != item {
+					report.NormalizedItems++
+				}
+				if n == "" {
+					report.DroppedItems++
+					continue
+				}
+				normalized = append(normalized, n)
+			}
+			items = normalized
+		}
+		if opts.Policy == LoadKeep {
+			dataset = append(dataset, items)
+			continue
+		}
+		if len(items) == 0 {
+			// An empty transaction only dilutes supports, so it is never kept
+			report.SkippedLines++
+			continue
+		}
+		dataset = append(dataset, items)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, report, err
+	}
+	report.Transactions = len(dataset)
+	return dataset, report, nil
+}
+
+// cleanLine splits a line into items, repairing invalid UTF-8 and stray
+// delimiters, and describes the first problem found
+func cleanLine(line string, report *DataQualityReport) (Transaction, string) {
+	problem := ""
+	if !utf8.ValidString(line) {
+		report.InvalidUTF8Lines++
+		problem = "invalid UTF-8"
+		line = strings.ToValidUTF8(line, "")
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		report.EmptyLines++
+		if problem == "" {
+			problem = "empty line"
+		}
+		return nil, problem
+	}
+	items := make(Transaction, 0, len(fields))
+	delimited := false
+	for _, field := range fields {
+		if !strings.ContainsAny(field, strayDelimiters) {
+			items = append(items, field)
+			continue
+		}
+		delimited = true
+		report.CleanedItems++
+		parts := strings.FieldsFunc(field, func(r rune) bool { return strings.ContainsRune(strayDelimiters, r) })
+		if len(parts) == 0 {
+			report.DroppedItems++
+		}
+		items = append(items, parts...)
+	}
+	if delimited {
+		report.DelimiterLines++
+		if problem == "" {
+			problem = "stray delimiter"
+		}
+	}
+	return items, problem
+}
+
+// writeDataQualityCSV writes the report to path
+func writeDataQualityCSV(path string, report DataQualityReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Metric,Value")
+	fmt.Fprintf(f, "Lines,%d\n", report.Lines)
+	fmt.Fprintf(f, "Transactions,%d\n", report.Transactions)
+	fmt.Fprintf(f, "EmptyLines,%d\n", report.EmptyLines)
+	fmt.Fprintf(f, "InvalidUTF8Lines,%d\n", report.InvalidUTF8Lines)
+	fmt.Fprintf(f, "DelimiterLines,%d\n", report.DelimiterLines)
+	fmt.Fprintf(f, "SkippedLines,%d\n", report.SkippedLines)
+	fmt.Fprintf(f, "CleanedItems,%d\n", report.CleanedItems)
+	fmt.Fprintf(f, "DroppedItems,%d\n", report.DroppedItems)
+	fmt.Fprintf(f, "NormalizedItems,%d\n", report.NormalizedItems)
+	return f.Close()
+}
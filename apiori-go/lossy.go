@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"strings"
+)
+
+// lossyEntry is an item tracked by Lossy Counting: its count since it was
+// last inserted, and the most it can have been undercounted by before that
+type lossyEntry struct {
+	count int
+	delta int
+}
+
+// LossyCounter finds the approximately frequent items of a stream of
+// transactions in one pass with Lossy Counting (Manku and Motwani). Every
+// item of support at least s is reported for a threshold s, and none below
+// s-epsilon, while at most (1/epsilon)·log(epsilon·N) items are tracked
+// after N transactions whatever the size of the item dictionary.
+type LossyCounter struct {
+	width   int
+	epsilon float64
+	n       int
+	entries map[string]lossyEntry
+	peak    int
+}
+
+// NewLossyCounter returns a counter undercounting supports by at most epsilon
+func NewLossyCounter(epsilon float64) *LossyCounter {
+	return &LossyCounter{
+		width:   int(math.Ceil(1 / epsilon)),
+		epsilon: epsilon,
+		entries: make(map[string]lossyEntry),
+	}
+}
+
+// Add counts the distinct items of a transaction, dropping the items whose
+// counts show them infrequent at the end of every bucket of 1/epsilon
+// transactions
+func (lc *LossyCounter) Add(t Transaction) {
+	lc.n++
+	bucket := (lc.n + lc.width - 1) / lc.width
+	seen := make(map[string]bool, len(t))
+	for _, item := range t {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		e, ok := lc.entries[item]
+		if !ok {
+			e.delta = bucket - 1
+		}
+		e.count++
+		lc.entries[item] = e
+	}
+	lc.peak = max(lc.peak, len(lc.entries))
+	if lc.n%lc.width == 0 {
+		for item, e := range lc.entries {
+			if e.count+e.delta <= bucket {
+				delete(lc.entries, item)
+			}
+		}
+	}
+}
+
+// Frequent returns the items possibly reaching minSupport: those whose
+// count is at least (minSupport-epsilon)·N
+func (lc *LossyCounter) Frequent(minSupport float64) map[string]bool {
+	threshold := (minSupport - lc.epsilon) * float64(lc.n)
+	items := make(map[string]bool)
+	for item, e := range lc.entries {
+		if float64(e.count) >= threshold {
+			items[item] = true
+		}
+	}
+	return items
+}
+
+// Transactions returns the number of transactions counted
+func (lc *LossyCounter) Transactions() int { return lc.n }
+
+// Peak returns the most items tracked at once
+func (lc *LossyCounter) Peak() int { return lc.peak }
+
+// scanTransactions calls fn with the whitespace-separated items of every
+// line of filename, as LoadDataset reads them
+func scanTransactions(filename string, fn func(Transaction)) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fn(strings.Fields(scanner.Text()))
+	}
+	return scanner.Err()
+}
+
+// LossyFrequentItems makes a Lossy Counting pass over a dataset file and
+// returns the items that may be frequent at minSupport, with the counter
+func LossyFrequentItems(filename string, minSupport, epsilon float64) (map[string]bool, *LossyCounter, error) {
+	lc := NewLossyCounter(epsilon)
+	if err := scanTransactions(filename, lc.Add); err != nil {
+		return nil, nil, err
+	}
+	return lc.Frequent(minSupport), lc, nil
+}
+
+// LoadDatasetRestricted loads a dataset file as LoadDataset does, keeping
+// only the given items; transactions left empty are kept so supports stay
+// relative to every transaction
+func LoadDatasetRestricted(filename string, keep map[string]bool) (Dataset, error) {
+	var dataset Dataset
+	err := scanTransactions(filename, func(t Transaction) {
+		kept := make(Transaction, 0, len(t))
+		for _, item := range t {
+			if keep[item] {
+				kept = append(kept, item)
+			}
+		}
+		dataset = append(dataset, kept)
+	})
+	return dataset, err
+}
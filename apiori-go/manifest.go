@@ -0,0 +1,263 @@
+//go:build !js
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// manifestSuffix names the manifest written next to the results of a run
+const manifestSuffix = "_manifest.json"
+
+// manifestRedactedFlags may carry credentials, or the privacy noise seed and
+// pseudonym salt that would let the results be undone, and are only
+// recorded as set
+var manifestRedactedFlags = map[string]bool{
+	"postgres-dsn":   true,
+	"clickhouse-url": true,
+	"dp-seed":        true,
+	"pseudonym-salt": true,
+}
+
+// runManifest records what a run was computed from, so that a stored result
+// can be traced back to and re-checked against its dataset
+type runManifest struct {
+	RunID     string    `json:"runId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Algorithm string    `json:"algorithm"`
+	Version   string    `json:"version"`
+	// Dataset is the path of the dataset file, empty for the example dataset
+	Dataset       string `json:"dataset"`
+	DatasetSHA256 string `json:"datasetSha256"`
+	// ItemDictionarySHA256 hashes the sorted distinct items as loaded, so it
+	// survives reordering of the file but not changes to its items
+	ItemDictionarySHA256 string            `json:"itemDictionarySha256"`
+	Transactions         int               `json:"transactions"`
+	Items                int               `json:"items"`
+	Parameters           map[string]string `json:"parameters"`
+	// Partial marks a run stopped before mining finished, such as by Ctrl-C;
+	// its results hold the complete levels only
+	Partial       bool   `json:"partial,omitempty"`
+	PartialReason string `json:"partialReason,omitempty"`
+	// LevelSupports is the support threshold of each level when a schedule
+	// raised it above the minimum support
+	LevelSupports map[int]float64 `json:"levelSupports,omitempty"`
+	// Outputs maps the result files of the run to their SHA-256
+	Outputs map[string]string `json:"outputs"`
+}
+
+// buildVersion identifies the binary by its VCS revision when it was built
+// from a checkout
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version, modified := info.Main.Version, false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			version = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if modified {
+		version += "-dirty"
+	}
+	return version
+}
+
+// datasetFingerprint returns the hash of the dataset, from its file when it
+// has one, and of its item dictionary, with the number of distinct items
+func datasetFingerprint(filename string, dataset Dataset) (string, string, int, error) {
+	var sum string
+	// A directory of text documents is hashed through its transactions
+	info, err := os.Stat(filename)
+	if filename != "" && err == nil && !info.IsDir() {
+		if sum, err = fileSHA256(filename); err != nil {
+			return "", "", 0, err
+		}
+	} else {
+		h := sha256.New()
+		for _, transaction := range dataset {
+			io.WriteString(h, strings.Join(transaction, " ")+"\n")
+		}
+		sum = hex.EncodeToString(h.Sum(nil))
+	}
+	return sum, itemDictionaryHash(dataset), len(distinctItems(dataset)), nil
+}
+
+// distinctItems returns the items of a dataset in sorted order
+func distinctItems(dataset Dataset) []string {
+	seen := make(map[string]bool)
+	var items []string
+	for _, transaction := range dataset {
+		for _, item := range transaction {
+			if !seen[item] {
+				seen[item] = true
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Strings(items)
+	return items
+}
+
+// itemDictionaryHash returns the hex SHA-256 of the sorted distinct items
+func itemDictionaryHash(dataset Dataset) string {
+	h := sha256.New()
+	for _, item := range distinctItems(dataset) {
+		io.WriteString(h, item+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// commandLineParameters returns the value of every flag of the command line,
+// with credentials redacted
+func commandLineParameters(fs *flag.FlagSet) map[string]string {
+	params := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if manifestRedactedFlags[f.Name] && value != f.DefValue {
+			value = "(redacted)"
+		}
+		params[f.Name] = value
+	})
+	return params
+}
+
+// writeManifest hashes the result files of base in dir into m and writes it
+// next to them
+func writeManifest(dir, base string, m runManifest) (string, error) {
+	m.Outputs = make(map[string]string)
+	for _, suffix := range resultFileSuffixes {
+		name := base + suffix
+		if suffix == manifestSuffix {
+			continue
+		}
+		sum, err := fileSHA256(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		m.Outputs[name] = sum
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, base+manifestSuffix)
+	return path, os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// loadOptionsFromParameters rebuilds the load options of a recorded run
+func loadOptionsFromParameters(params map[string]string) (LoadOptions, error) {
+	opts := LoadOptions{Policy: params["load-policy"], Normalize: params["normalize"] == "true", Encoding: params["encoding"]}
+	if path := params["transliterate"]; path != "" {
+		var err error
+		if opts.Transliterate, err = LoadTransliteration(path); err != nil {
+			return opts, err
+		}
+	}
+	if opts.Policy == "" && (opts.Normalize || opts.Transliterate != nil || opts.Encoding != "") {
+		opts.Policy = LoadKeep
+	}
+	return opts, nil
+}
+
+// runCheckRun implements the check-run subcommand, which re-verifies that a
+// run's manifest still matches its dataset and result files
+func runCheckRun(args []string) error {
+	fs := flag.NewFlagSet("check-run", flag.ExitOnError)
+	datasetPath := fs.String("dataset", "", "dataset to check instead of the one recorded in the manifest")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: check-run [-dataset file] <manifest.json>")
+	}
+	manifestPath := fs.Arg(0)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+	var m runManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("invalid manifest %s: %v", manifestPath, err)
+	}
+
+	filename := m.Dataset
+	if *datasetPath != "" {
+		filename = *datasetPath
+	}
+	if filename == "" {
+		return fmt.Errorf("run %s mined the example dataset; pass -dataset to check a file", m.RunID)
+	}
+	opts, err := loadOptionsFromParameters(m.Parameters)
+	if err != nil {
+		return err
+	}
+	var dataset Dataset
+	if opts.Policy == "" {
+		dataset, err = LoadDataset(filename)
+	} else {
+		dataset, _, err = LoadDatasetWithOptions(filename, opts)
+	}
+	if err != nil {
+		return err
+	}
+	fileHash, dictHash, items, err := datasetFingerprint(filename, dataset)
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	check := func(what string, ok bool, detail string) {
+		status := "ok"
+		if !ok {
+			status = "MISMATCH"
+			problems++
+		}
+		fmt.Printf("%-32s %-8s %s\n", what, status, detail)
+	}
+	fmt.Printf("Run %s (%s %s, version %s)\n", m.RunID, m.Algorithm, m.CreatedAt.Format(time.RFC3339), m.Version)
+	if m.Partial {
+		fmt.Printf("Partial run: %s\n", m.PartialReason)
+	}
+	check("dataset", fileHash == m.DatasetSHA256, filename)
+	check("item dictionary", dictHash == m.ItemDictionarySHA256, fmt.Sprintf("%d items, %d recorded", items, m.Items))
+	check("transactions", len(dataset) == m.Transactions, fmt.Sprintf("%d, %d recorded", len(dataset), m.Transactions))
+
+	names := make([]string, 0, len(m.Outputs))
+	for name := range m.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dir := filepath.Dir(manifestPath)
+	for _, name := range names {
+		sum, err := fileSHA256(filepath.Join(dir, name))
+		detail := "unchanged"
+		if err != nil {
+			detail = err.Error()
+		} else if sum != m.Outputs[name] {
+			detail = "modified"
+		}
+		check(name, err == nil && sum == m.Outputs[name], detail)
+	}
+	if problems > 0 {
+		return fmt.Errorf("%d checks failed for run %s", problems, m.RunID)
+	}
+	return nil
+}
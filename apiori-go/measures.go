@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// measureDoc identifies how the values of an output column are computed.
+// The ID changes whenever the computation does, so consumers can tell apart
+// numbers that share a column name but not a definition.
+type measureDoc struct {
+	ID      string
+	Formula string
+}
+
+// measureDocs documents the computed columns of the itemset and rule outputs.
+// s(·) is the fraction of transactions containing the items, X the
+// antecedent and Y the consequent of a rule, or the items of an itemset.
+var measureDocs = map[string]measureDoc{
+	"Size":               {"size.v1", "number of items in X ∪ Y"},
+	"Count":              {"count.v1", "number of transactions containing X ∪ Y"},
+	"Support":            {"support.v1", "s(XY)"},
+	"Confidence":         {"confidence.v1", "s(XY) / s(X)"},
+	"Lift":               {"lift.v1", "s(XY) / (s(X) s(Y))"},
+	"ChiSquare":          {"chi2.pearson.v1", "Pearson chi-square of the 2x2 table of X and Y, no continuity correction"},
+	"PValue":             {"chi2.p.v1", "upper tail of chi-square with 1 degree of freedom at ChiSquare"},
+	"FisherPValue":       {"fisher.greater.v1", "one-sided Fisher exact test of positive association of X and Y"},
+	"AdjustedPValue":     {"fisher.adjusted.v1", "FisherPValue adjusted across the rules by the -p-adjust method"},
+	"AllConfidence":      {"allconf.v1", "s(XY) / max of the part supports"},
+	"Kulczynski":         {"kulc.v1", "mean over the parts P of s(XY) / s(P)"},
+	"Cosine":             {"cosine.v1", "s(XY) / geometric mean of the part supports"},
+	"Leverage":           {"leverage.v1", "s(XY) - s(X) s(Y)"},
+	"NormalizedLeverage": {"leverage.normalized.v1", "Leverage / (min(s(X),s(Y)) - s(X) s(Y)) when positive, Leverage / (s(X) s(Y) - max(0, s(X)+s(Y)-1)) when negative"},
+}
+
+// writeMeasureDocs writes a "# Column: id = formula" comment line for every
+// documented column, in order, ahead of a CSV header
+func writeMeasureDocs(w io.Writer, columns []string) error {
+	for _, column := range columns {
+		doc, ok := measureDocs[column]
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# %s: %s = %s\n", column, doc.ID, doc.Formula); err != nil {
+			return err
+		}
+	}
+	return nil
+}
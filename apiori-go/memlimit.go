@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Policies applied when the next candidate level would exceed the memory limit
+const (
+	// MemoryRaiseSupport raises the minimum support until the level fits,
+	// dropping the itemsets of earlier levels below the new threshold
+	MemoryRaiseSupport = "raise-support"
+	// MemoryCapLevel stops mining, keeping the levels found so far
+	MemoryCapLevel = "cap-level"
+	// MemoryAbort stops mining like MemoryCapLevel but reports ErrMemoryLimit
+	MemoryAbort = "abort"
+)
+
+// ErrMemoryLimit is returned by MineContext when the abort policy stopped it
+var ErrMemoryLimit = errors.New("memory limit reached")
+
+// SetMemoryLimit bounds the heap of the run to limit bytes, applying policy
+// before generating a level of candidates that would not fit. The Go runtime
+// is given the same soft limit so it collects garbage harder near it.
+func (am *AprioriMiner) SetMemoryLimit(limit int64, policy string) error {
+	switch policy {
+	case MemoryRaiseSupport, MemoryCapLevel, MemoryAbort:
+	default:
+		return fmt.Errorf("memory policy must be %s, %s or %s", MemoryRaiseSupport, MemoryCapLevel, MemoryAbort)
+	}
+	am.memoryLimit, am.memoryPolicy = limit, policy
+	if limit > 0 {
+		debug.SetMemoryLimit(limit)
+	}
+	return nil
+}
+
+// MemoryLimited describes how the memory limit changed the run, or is empty
+// when it did not
+func (am *AprioriMiner) MemoryLimited() string {
+	return am.memoryNote
+}
+
+// joinSize is the number of candidates of size k+1 the join of the frequent
+// k-itemsets produces before subset pruning: every pair of sets sharing their
+// first k-1 items
+func joinSize(frequent []ItemSet, k int) int64 {
+	groups := make(map[string]int64)
+	for _, set := range frequent {
+		groups[strings.Join(sortedItems(set)[:k-1], "\x00")]++
+	}
+	total := int64(0)
+	for _, g := range groups {
+		total += g * (g - 1) / 2
+	}
+	return total
+}
+
+// heapInUse reads the live heap of the process
+func heapInUse() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
+
+// fitMemory applies the memory policy before the candidates following the
+// frequent k-itemsets are generated. It returns the frequent sets to join,
+// which are nil when mining should stop.
+func (am *AprioriMiner) fitMemory(frequent []ItemSet, k int) ([]ItemSet, error) {
+	heap := heapInUse()
+	fits := func(sets []ItemSet) bool {
+		return heap+joinSize(sets, k)*candidateBytes <= am.memoryLimit
+	}
+	if fits(frequent) {
+		return frequent, nil
+	}
+	estimate := heap + joinSize(frequent, k)*candidateBytes
+	if am.memoryPolicy == MemoryRaiseSupport {
+		// The join shrinks as the threshold rises through the supports of the level
+		supports := make([]float64, len(frequent))
+		for i, set := range frequent {
+			supports[i] = am.calculateSupport(set)
+		}
+		sort.Float64s(supports)
+		above := func(t float64) []ItemSet {
+			var kept []ItemSet
+			for _, set := range frequent {
+				if am.calculateSupport(set) >= t {
+					kept = append(kept, set)
+				}
+			}
+			return kept
+		}
+		i := sort.Search(len(supports), func(i int) bool { return fits(above(supports[i])) })
+		if i < len(supports) {
+			am.raiseSupport(supports[i])
+			am.memoryNote = fmt.Sprintf("minimum support raised to %.4f at level %d to stay within the memory limit", am.minSupport, k+1)
+			log.Printf("Warning: level %d needs ~%s over the %s memory limit; %s",
+				k+1, formatBytes(estimate), formatBytes(am.memoryLimit), am.memoryNote)
+			// Sets without a required item are stored filtered out but still joined
+			return above(supports[i]), nil
+		}
+	}
+	am.memoryNote = fmt.Sprintf("mining stopped after level %d to stay within the memory limit", k)
+	log.Printf("Warning: level %d needs ~%s over the %s memory limit; %s",
+		k+1, formatBytes(estimate), formatBytes(am.memoryLimit), am.memoryNote)
+	if am.memoryPolicy == MemoryAbort {
+		return nil, ErrMemoryLimit
+	}
+	return nil, nil
+}
+
+// raiseSupport sets a higher minimum support and drops the itemsets found so
+// far that fall below it
+func (am *AprioriMiner) raiseSupport(minSupport float64) {
+	am.minSupport = minSupport
+	am.filterReleased(minSupport)
+	for k, sets := range am.frequentSets {
+		kept := sets[:0]
+		for _, set := range sets {
+			if am.calculateSupport(set) >= minSupport {
+				kept = append(kept, set)
+			}
+		}
+		if len(kept) == 0 {
+			delete(am.frequentSets, k)
+		} else {
+			am.frequentSets[k] = kept
+		}
+	}
+}
+
+// ParseByteSize reads a size such as 512MB, 2GB or 1048576; the units are
+// powers of 1024
+func ParseByteSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		scale  int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+	value, scale := strings.ToUpper(strings.TrimSpace(s)), int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(value, u.suffix) {
+			value, scale = strings.TrimSpace(strings.TrimSuffix(value, u.suffix)), u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(scale)), nil
+}
+
+// formatBytes renders a size in the largest unit of ParseByteSize below it
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+}
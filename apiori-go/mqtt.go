@@ -0,0 +1,311 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// deviceEvent is one event reported by a device
+type deviceEvent struct {
+	Device string
+	Events []string
+	Time   time.Time
+}
+
+// eventFields names the payload fields read from JSON messages
+type eventFields struct {
+	Device string
+	Event  string
+	Time   string
+	// DeviceLevel is the topic level holding the device when the payload has none
+	DeviceLevel int
+}
+
+// parseDeviceEvent decodes a message. JSON objects provide the device, the
+// event (a string or an array of strings) and an optional time in RFC 3339 or
+// Unix seconds or milliseconds; any other payload is taken as the event name.
+// The device falls back to a level of the topic and the time to now.
+func parseDeviceEvent(topic string, payload []byte, fields eventFields, now time.Time) (deviceEvent, error) {
+	ev := deviceEvent{Time: now}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(payload, &obj); err == nil {
+		if device, ok := obj[fields.Device].(string); ok {
+			ev.Device = device
+		}
+		switch event := obj[fields.Event].(type) {
+		case string:
+			ev.Events = []string{event}
+		case []interface{}:
+			for _, e := range event {
+				if s, ok := e.(string); ok {
+					ev.Events = append(ev.Events, s)
+				}
+			}
+		}
+		switch t := obj[fields.Time].(type) {
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return ev, fmt.Errorf("invalid %s: %v", fields.Time, err)
+			}
+			ev.Time = parsed
+		case float64:
+			if t > 1e12 {
+				ev.Time = time.UnixMilli(int64(t))
+			} else {
+				ev.Time = time.Unix(int64(t), 0)
+			}
+		}
+	} else if event := strings.TrimSpace(string(payload)); event != "" {
+		ev.Events = []string{event}
+	}
+
+	if ev.Device == "" {
+		levels := strings.Split(topic, "/")
+		if fields.DeviceLevel >= 0 && fields.DeviceLevel < len(levels) {
+			ev.Device = levels[fields.DeviceLevel]
+		}
+	}
+	if ev.Device == "" {
+		return ev, fmt.Errorf("message on %s has no device", topic)
+	}
+	// Items are whitespace-separated in dataset files, so keep them single words
+	events := ev.Events[:0]
+	for _, e := range ev.Events {
+		if e = strings.Join(strings.Fields(e), "_"); e != "" {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return ev, fmt.Errorf("message on %s has no event", topic)
+	}
+	ev.Events = events
+	return ev, nil
+}
+
+// basketKey identifies the transaction of one device in one time window
+type basketKey struct {
+	device string
+	start  time.Time
+}
+
+// windowedTransaction is a closed basket with the start of its window
+type windowedTransaction struct {
+	start time.Time
+	items Transaction
+}
+
+// eventBaskets groups device events into one transaction per device and
+// tumbling time window, keeping closed baskets for the history duration
+type eventBaskets struct {
+	window  time.Duration
+	history time.Duration
+
+	mu     sync.Mutex
+	open   map[basketKey]map[string]bool
+	closed []windowedTransaction
+}
+
+func newEventBaskets(window, history time.Duration) *eventBaskets {
+	return &eventBaskets{window: window, history: history, open: make(map[basketKey]map[string]bool)}
+}
+
+// add files an event into the basket of its device and window
+func (b *eventBaskets) add(ev deviceEvent) {
+	key := basketKey{device: ev.Device, start: ev.Time.UTC().Truncate(b.window)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	basket := b.open[key]
+	if basket == nil {
+		basket = make(map[string]bool)
+		b.open[key] = basket
+	}
+	for _, e := range ev.Events {
+		basket[e] = true
+	}
+}
+
+// snapshot closes the baskets whose window ended before now, drops baskets
+// older than the history and returns the closed baskets as a dataset
+func (b *eventBaskets) snapshot(now time.Time) Dataset {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, basket := range b.open {
+		if key.start.Add(b.window).After(now) {
+			continue
+		}
+		items := make(Transaction, 0, len(basket))
+		for item := range basket {
+			items = append(items, item)
+		}
+		sort.Strings(items)
+		b.closed = append(b.closed, windowedTransaction{start: key.start, items: items})
+		delete(b.open, key)
+	}
+
+	cutoff := now.Add(-b.history)
+	kept := b.closed[:0]
+	for _, t := range b.closed {
+		if !t.start.Before(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.closed = kept
+
+	dataset := make(Dataset, len(b.closed))
+	for i, t := range b.closed {
+		dataset[i] = t.items
+	}
+	return dataset
+}
+
+// mineBaskets mines the closed baskets into a new timestamped result generation
+func mineBaskets(ctx context.Context, dataset Dataset, minSupport float64, outputDir string, keep int, now time.Time) (err error) {
+	ctx, span := tracer.Start(ctx, "mqtt_mine")
+	defer func() {
+		endSpanError(span, err)
+		span.End()
+	}()
+	span.SetAttributes(attribute.Int("apriori.transactions", len(dataset)))
+
+	start := time.Now()
+	miner := NewAprioriMiner(dataset, minSupport)
+	if err := miner.MineContext(ctx); err != nil {
+		return err
+	}
+	elapsed := time.Since(start).Seconds()
+	metrics := TimingMetrics{ProcessingTime: elapsed, TotalTime: elapsed}
+
+	generation := now.UTC().Format("20060102T150405Z")
+	err = traced(ctx, "output", func(context.Context) error {
+		return miner.OutputResultsTo(filepath.Join(outputDir, generation), "mqtt", metrics)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(outputDir, "LATEST"), []byte(generation+"\n")); err != nil {
+		return err
+	}
+	log.Printf("Mined %d baskets into %d frequent itemsets (%s)", len(dataset), miner.getTotalFrequentItemsets(), generation)
+	return rotateGenerations(outputDir, keep)
+}
+
+// runMQTT implements the mqtt subcommand, which subscribes to device events,
+// groups them into per-device baskets and periodically mines the baskets of
+// the recent history for co-occurring events
+func runMQTT(args []string) error {
+	fs := flag.NewFlagSet("mqtt", flag.ExitOnError)
+	broker := fs.String("broker", "tcp://localhost:1883", "MQTT broker URL (tcp://, ssl:// or ws://)")
+	topics := fs.String("topics", "devices/+/events", "comma-separated topic filters to subscribe to")
+	qos := fs.Int("qos", 1, "subscription QoS (0, 1 or 2)")
+	clientID := fs.String("client-id", "", "MQTT client ID (default: apriori-<random>)")
+	username := fs.String("username", "", "MQTT username")
+	deviceField := fs.String("device-field", "device", "JSON payload field holding the device ID")
+	eventField := fs.String("event-field", "event", "JSON payload field holding the event or events")
+	timeField := fs.String("time-field", "timestamp", "JSON payload field holding the event time")
+	deviceLevel := fs.Int("device-level", 1, "topic level holding the device ID when the payload has none")
+	window := fs.Duration("window", 5*time.Minute, "length of the time window grouping a device's events into one transaction")
+	history := fs.Duration("history", 24*time.Hour, "how far back baskets are kept for mining")
+	interval := fs.Duration("interval", 15*time.Minute, "how often the baskets are mined")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support")
+	outputDir := fs.String("output-dir", filepath.Join("results", "mqtt"), "directory receiving a result generation per mining run")
+	keep := fs.Int("keep", 24, "number of result generations retained")
+	fs.Parse(args)
+
+	if *window <= 0 || *interval <= 0 || *history < *window {
+		return fmt.Errorf("window and interval must be positive and history at least one window")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if *qos < 0 || *qos > 2 {
+		return fmt.Errorf("qos must be 0, 1 or 2")
+	}
+	if *clientID == "" {
+		*clientID = "apriori-" + newJobID()[:8]
+	}
+	filters := make(map[string]byte)
+	for _, topic := range strings.Split(*topics, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			filters[topic] = byte(*qos)
+		}
+	}
+	if len(filters) == 0 {
+		return fmt.Errorf("no topics to subscribe to")
+	}
+
+	fields := eventFields{Device: *deviceField, Event: *eventField, Time: *timeField, DeviceLevel: *deviceLevel}
+	baskets := newEventBaskets(*window, *history)
+	onMessage := func(_ mqtt.Client, msg mqtt.Message) {
+		ev, err := parseDeviceEvent(msg.Topic(), msg.Payload(), fields, time.Now())
+		if err != nil {
+			log.Printf("Skipping message: %v", err)
+			return
+		}
+		baskets.add(ev)
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(*broker).
+		SetClientID(*clientID).
+		SetUsername(*username).
+		SetPassword(os.Getenv("MQTT_PASSWORD")).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			// Subscriptions are renewed on every (re)connect
+			token := c.SubscribeMultiple(filters, onMessage)
+			if token.Wait() && token.Error() != nil {
+				log.Printf("failed to subscribe: %v", token.Error())
+				return
+			}
+			log.Printf("Subscribed to %s on %s", *topics, *broker)
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("MQTT connection lost: %v", err)
+		})
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.WaitTimeout(30*time.Second) && token.Error() != nil {
+		return fmt.Errorf("failed to connect to %s: %v", *broker, token.Error())
+	}
+	defer client.Disconnect(1000)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("MQTT adapter stopped")
+			return nil
+		case <-ticker.C:
+		}
+		now := time.Now()
+		dataset := baskets.snapshot(now)
+		if len(dataset) == 0 {
+			log.Printf("No closed baskets to mine yet")
+			continue
+		}
+		if err := mineBaskets(ctx, dataset, *minSupport, *outputDir, *keep, now); err != nil {
+			log.Printf("mining baskets failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// multiplicitySeparator joins an item and its multiplicity in multiset mode
+const multiplicitySeparator = "×"
+
+// ExpandMultiset rewrites every transaction so that the n-th occurrence of
+// an item becomes the item "item×n" (the first stays "item"). A transaction
+// then contains item×n exactly when it holds at least n of the item, and
+// ordinary support counting becomes quantity-aware.
+func ExpandMultiset(dataset Dataset) Dataset {
+	expanded := make(Dataset, len(dataset))
+	for i, transaction := range dataset {
+		seen := make(map[string]int, len(transaction))
+		items := make(Transaction, 0, len(transaction))
+		for _, item := range transaction {
+			seen[item]++
+			if n := seen[item]; n == 1 {
+				items = append(items, item)
+			} else {
+				items = append(items, item+multiplicitySeparator+strconv.Itoa(n))
+			}
+		}
+		expanded[i] = items
+	}
+	return expanded
+}
+
+// multisetBase returns the item of an expanded multiset item and its
+// multiplicity
+func multisetBase(item string) (string, int) {
+	if i := strings.LastIndex(item, multiplicitySeparator); i > 0 {
+		if n, err := strconv.Atoi(item[i+len(multiplicitySeparator):]); err == nil && n > 1 {
+			return item[:i], n
+		}
+	}
+	return item, 1
+}
+
+// hasRepeatedItem reports whether an itemset holds two multiplicities of the
+// same item. Such itemsets are implied by the larger multiplicity alone.
+func hasRepeatedItem(set ItemSet) bool {
+	bases := make(map[string]bool, len(set))
+	for item := range set {
+		base, _ := multisetBase(item)
+		if bases[base] {
+			return true
+		}
+		bases[base] = true
+	}
+	return false
+}
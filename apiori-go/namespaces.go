@@ -0,0 +1,196 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// DefaultNamespace holds datasets and jobs addressed without a namespace
+const DefaultNamespace = "default"
+
+// errNamespaceNotFound is returned for operations on unknown namespaces
+var errNamespaceNotFound = errors.New("namespace not found")
+
+// Quota limits the resources a namespace may use; zero means unlimited
+type Quota struct {
+	MaxDatasets     int   `json:"maxDatasets,omitempty"`
+	MaxStorageBytes int64 `json:"maxStorageBytes,omitempty"`
+	MaxActiveJobs   int   `json:"maxActiveJobs,omitempty"`
+}
+
+// Namespace isolates the datasets, jobs and results of one team or project
+type Namespace struct {
+	Name      string    `json:"name"`
+	Quota     Quota     `json:"quota"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NamespaceUsage reports a namespace together with its current resource usage
+type NamespaceUsage struct {
+	Namespace
+	Datasets     int   `json:"datasets"`
+	StorageBytes int64 `json:"storageBytes"`
+	ActiveJobs   int   `json:"activeJobs"`
+	Jobs         int   `json:"jobs"`
+}
+
+// quotaError reports that an operation would exceed a namespace quota
+type quotaError struct {
+	msg string
+}
+
+func (e *quotaError) Error() string { return e.msg }
+
+func isQuotaError(err error) bool {
+	var qe *quotaError
+	return errors.As(err, &qe)
+}
+
+// datasetKey identifies a dataset across namespaces
+func datasetKey(ns, name string) string {
+	return ns + "/" + name
+}
+
+// namespaceFromRequest returns the namespace addressed by a request
+func namespaceFromRequest(r *http.Request) string {
+	if ns := r.PathValue("ns"); ns != "" {
+		return ns
+	}
+	return DefaultNamespace
+}
+
+// usageLocked computes the resource usage of ns. The caller must hold s.mu.
+func (s *Server) usageLocked(ns *Namespace) NamespaceUsage {
+	usage := NamespaceUsage{Namespace: *ns}
+	for _, info := range s.datasets {
+		if info.Namespace == ns.Name {
+			usage.Datasets++
+			usage.StorageBytes += info.Bytes
+		}
+	}
+	for _, job := range s.jobs {
+		if job.Namespace != ns.Name {
+			continue
+		}
+		usage.Jobs++
+		if job.Status == JobQueued || job.Status == JobRunning {
+			usage.ActiveJobs++
+		}
+	}
+	return usage
+}
+
+// checkDatasetQuotaLocked verifies that storing a dataset of size bytes under
+// name fits the quota of ns, replacing any dataset of the same name. The
+// caller must hold s.mu.
+func (s *Server) checkDatasetQuotaLocked(ns, name string, size int64) error {
+	namespace, ok := s.namespaces[ns]
+	if !ok {
+		return errNamespaceNotFound
+	}
+	usage := s.usageLocked(namespace)
+	if existing, ok := s.datasets[datasetKey(ns, name)]; ok {
+		usage.Datasets--
+		usage.StorageBytes -= existing.Bytes
+	}
+	quota := namespace.Quota
+	if quota.MaxDatasets > 0 && usage.Datasets+1 > quota.MaxDatasets {
+		return &quotaError{fmt.Sprintf("namespace %s is limited to %d datasets", ns, quota.MaxDatasets)}
+	}
+	if quota.MaxStorageBytes > 0 && usage.StorageBytes+size > quota.MaxStorageBytes {
+		return &quotaError{fmt.Sprintf("namespace %s is limited to %d bytes of datasets", ns, quota.MaxStorageBytes)}
+	}
+	return nil
+}
+
+// checkJobQuotaLocked verifies that ns may queue another job. The caller
+// must hold s.mu.
+func (s *Server) checkJobQuotaLocked(ns string) error {
+	namespace, ok := s.namespaces[ns]
+	if !ok {
+		return errNamespaceNotFound
+	}
+	limit := namespace.Quota.MaxActiveJobs
+	if limit > 0 && s.usageLocked(namespace).ActiveJobs >= limit {
+		return &quotaError{fmt.Sprintf("namespace %s is limited to %d queued or running jobs", ns, limit)}
+	}
+	return nil
+}
+
+// listNamespaces returns the namespaces key may access with their usage
+// ordered by name; a nil key, as without authentication, sees them all
+func (s *Server) listNamespaces(key *APIKey) []NamespaceUsage {
+	s.mu.Lock()
+	list := make([]NamespaceUsage, 0, len(s.namespaces))
+	for _, ns := range s.namespaces {
+		if key == nil || key.allowsNamespace(ns.Name) {
+			list = append(list, s.usageLocked(ns))
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// handleListNamespaces lists the namespaces the caller may access with their
+// quotas and usage
+func (s *Server) handleListNamespaces(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.listNamespaces(apiKeyFromContext(r.Context())))
+}
+
+// handleGetNamespace reports the quota and usage of a namespace
+func (s *Server) handleGetNamespace(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns, ok := s.namespaces[r.PathValue("ns")]
+	if !ok {
+		writeError(w, http.StatusNotFound, errNamespaceNotFound.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.usageLocked(ns))
+}
+
+// handlePutNamespace creates a namespace or updates its quota. An empty body
+// applies the server's default quota.
+func (s *Server) handlePutNamespace(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("ns")
+	if !datasetNamePattern.MatchString(name) {
+		writeError(w, http.StatusBadRequest, "invalid namespace name")
+		return
+	}
+	var req struct {
+		Quota *Quota `json:"quota"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns, exists := s.namespaces[name]
+	if !exists {
+		ns = &Namespace{Name: name, Quota: s.defaultQuota, CreatedAt: time.Now().UTC()}
+	}
+	if req.Quota != nil {
+		ns.Quota = *req.Quota
+	}
+	s.namespaces[name] = ns
+	if err := s.saveStateLocked(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if !exists {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, s.usageLocked(ns))
+}
@@ -0,0 +1,53 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"algo-project/aprioripb"
+)
+
+func TestListNamespacesOnlyShowsTheKeysNamespaces(t *testing.T) {
+	s, err := NewServer(t.TempDir(), 1, Quota{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+	for _, name := range []string{"team", "other"} {
+		s.namespaces[name] = &Namespace{Name: name, CreatedAt: time.Now().UTC()}
+	}
+	key := &APIKey{Name: "team-reader", Key: "secret", Scopes: []string{ScopeRead}, Namespaces: []string{"team"}}
+	auth := &apiKeyAuth{keys: map[[sha256.Size]byte]*APIKey{sha256.Sum256([]byte(key.Key)): key}}
+
+	req := httptest.NewRequest(http.MethodGet, "/namespaces", nil)
+	req.Header.Set("X-API-Key", key.Key)
+	rec := httptest.NewRecorder()
+	auth.Middleware(s.Handler()).ServeHTTP(rec, req)
+	var list []NamespaceUsage
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("GET /namespaces: %d %v", rec.Code, err)
+	}
+	if len(list) != 1 || list[0].Name != "team" {
+		t.Errorf("GET /namespaces listed %+v, want only team", list)
+	}
+
+	ctx := context.WithValue(context.Background(), apiKeyContextKey{}, key)
+	resp, err := (&grpcService{server: s}).ListNamespaces(ctx, &aprioripb.ListNamespacesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Namespaces) != 1 || resp.Namespaces[0].Name != "team" {
+		t.Errorf("gRPC ListNamespaces listed %v, want only team", resp.Namespaces)
+	}
+
+	if all := s.listNamespaces(nil); len(all) != 3 {
+		t.Errorf("without authentication %d namespaces are listed, want 3", len(all))
+	}
+}
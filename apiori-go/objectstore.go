@@ -0,0 +1,455 @@
+//go:build !js
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resultFileSuffixes lists the files a CLI run writes for a basename
+var resultFileSuffixes = []string{
+	"_summary.csv",
+	"_rules.csv",
+	"_size_distribution.csv",
+	"_support_distribution.csv",
+	"_performance.csv",
+	"_bootstrap.csv",
+	"_clusters.csv",
+	"_item_similarity.csv",
+	"_data_quality.csv",
+	"_pruning.csv",
+	"_pruned_items.csv",
+	"_animation.json",
+	"_trace.jsonl",
+	manifestSuffix,
+}
+
+// newRunID returns an identifier for a run that sorts by start time
+func newRunID() string {
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + newJobID()[:8]
+}
+
+// objectStore stores objects in a bucket
+type objectStore interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// parseObjectURL resolves an s3://bucket/prefix or gs://bucket/prefix URL
+// into a store and key prefix. Credentials come from the environment:
+//
+//   - S3: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, optional
+//     AWS_SESSION_TOKEN, AWS_REGION (default us-east-1) and AWS_ENDPOINT_URL
+//     for S3-compatible stores, which are addressed path-style.
+//   - GCS: GOOGLE_OAUTH_ACCESS_TOKEN, else the service account key file named
+//     by GOOGLE_APPLICATION_CREDENTIALS, else the GCE metadata server.
+func parseObjectURL(raw string) (objectStore, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, "", fmt.Errorf("invalid upload destination %q", raw)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		store, err := newS3Store(u.Host)
+		return store, prefix, err
+	case "gs":
+		return newGCSStore(u.Host), prefix, nil
+	}
+	return nil, "", fmt.Errorf("upload destination must start with s3:// or gs://")
+}
+
+// uploadResults copies the result files of a run to store under
+// prefix/runID/, or as a single prefix/runID/<base>.tar.gz when archive is set
+func uploadResults(ctx context.Context, store objectStore, prefix, runID, dir, base string, archive bool) ([]string, error) {
+	files := runResultFiles(dir, base)
+	keyPrefix := path.Join(prefix, runID)
+
+	if archive {
+		var buf bytes.Buffer
+		if err := writeTarGz(&buf, dir, files); err != nil {
+			return nil, err
+		}
+		key := path.Join(keyPrefix, base+".tar.gz")
+		if err := store.Put(ctx, key, buf.Bytes(), "application/gzip"); err != nil {
+			return nil, err
+		}
+		return []string{key}, nil
+	}
+
+	var keys []string
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return keys, err
+		}
+		key := path.Join(keyPrefix, name)
+		contentType := "text/csv"
+		if strings.HasSuffix(name, ".json") {
+			contentType = "application/json"
+		}
+		if err := store.Put(ctx, key, data, contentType); err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// writeTarGz archives the named files of dir into w
+func writeTarGz(w io.Writer, dir string, files []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// uploadAttempts bounds the attempts made for each uploaded object
+const uploadAttempts = 3
+
+// sendWithRetry sends the request built by newReq, retrying network errors,
+// server errors and throttling with exponential backoff up to attempts times
+func sendWithRetry(ctx context.Context, attempts int, newReq func() (*http.Request, error)) error {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	backoff := time.Second
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		req, err := newReq()
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// s3Store uploads objects to S3 or an S3-compatible store with SigV4
+type s3Store struct {
+	bucket       string
+	region       string
+	endpoint     string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+func newS3Store(bucket string) (*s3Store, error) {
+	s := &s3Store{
+		bucket:       bucket,
+		region:       os.Getenv("AWS_REGION"),
+		endpoint:     strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for S3 uploads")
+	}
+	return s, nil
+}
+
+// objectURL addresses key virtual-host style on AWS and path-style on custom endpoints
+func (s *s3Store) objectURL(key string) string {
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s.bucket + "/" + s3EscapePath(key)
+	}
+	return "https://" + s.bucket + ".s3." + s.region + ".amazonaws.com/" + s3EscapePath(key)
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	return sendWithRetry(ctx, uploadAttempts, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+		if s.sessionToken != "" {
+			req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		}
+		signV4(req, payloadHash, s.accessKey, s.secretKey, s.region, "s3", time.Now())
+		return req, nil
+	})
+}
+
+// s3EscapePath URI-encodes each segment of key as SigV4 requires
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		var b strings.Builder
+		for _, c := range []byte(seg) {
+			if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		segments[i] = b.String()
+	}
+	return strings.Join(segments, "/")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// signV4 adds an AWS Signature Version 4 Authorization header covering the
+// host, the X-Amz-Date it sets and every header already set on req. S3 also
+// needs the payload hash sent as X-Amz-Content-Sha256, which the caller sets.
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// gcsStore uploads objects to Google Cloud Storage through the JSON API
+type gcsStore struct {
+	bucket string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newGCSStore(bucket string) *gcsStore {
+	return &gcsStore{bucket: bucket}
+}
+
+func (g *gcsStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get GCS credentials: %v", err)
+	}
+	u := "https://storage.googleapis.com/upload/storage/v1/b/" + url.PathEscape(g.bucket) +
+		"/o?uploadType=media&name=" + url.QueryEscape(key)
+	return sendWithRetry(ctx, uploadAttempts, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req, nil
+	})
+}
+
+// accessToken returns a cached OAuth token, refreshing it shortly before expiry
+func (g *gcsStore) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Until(g.expires) > time.Minute {
+		return g.token, nil
+	}
+	var tok oauthToken
+	var err error
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		tok, err = serviceAccountToken(ctx, path)
+	} else {
+		tok, err = metadataToken(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	g.token = tok.AccessToken
+	g.expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return g.token, nil
+}
+
+type oauthToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// metadataToken asks the GCE metadata server for the instance's token
+func metadataToken(ctx context.Context) (oauthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return fetchToken(req)
+}
+
+// serviceAccountToken exchanges a JWT signed with a service account key for a token
+func serviceAccountToken(ctx context.Context, keyFile string) (oauthToken, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	var sa struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return oauthToken{}, fmt.Errorf("invalid service account key: %v", err)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return oauthToken{}, fmt.Errorf("invalid service account private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return oauthToken{}, fmt.Errorf("service account key is not RSA")
+	}
+
+	now := time.Now()
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   sa.ClientEmail,
+		"scope": gcsScope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := header + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return oauthToken{}, err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {unsigned + "." + enc.EncodeToString(sig)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sa.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return fetchToken(req)
+}
+
+func fetchToken(req *http.Request) (oauthToken, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return oauthToken{}, fmt.Errorf("token request failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var tok oauthToken
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return oauthToken{}, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,20 @@
+//go:build !js
+
+package main
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec describes the REST API served by Handler; keep it in sync when
+// routes or JSON fields change
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// handleOpenAPI serves the OpenAPI document of the REST API
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
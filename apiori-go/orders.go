@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// OrderOptions names the fields of order documents
+type OrderOptions struct {
+	// ItemsField holds the array of line items of an order; a dotted name
+	// such as "cart.lines" reaches into nested objects
+	ItemsField string
+	// SKUField identifies the product of a line item
+	SKUField string
+	// QuantityField, when set, repeats every SKU by the quantity ordered, for
+	// multiset mining; otherwise each SKU is counted once per order
+	QuantityField string
+}
+
+// jsonField returns the value at a dotted path of a decoded JSON object
+func jsonField(doc map[string]any, path string) (any, bool) {
+	var v any = doc
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[name]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// orderBasket extracts the SKUs of an order, in line item order
+func orderBasket(order map[string]any, opts OrderOptions) Transaction {
+	value, _ := jsonField(order, opts.ItemsField)
+	lines, _ := value.([]any)
+	seen := make(map[string]bool)
+	var basket Transaction
+	for _, line := range lines {
+		item, ok := line.(map[string]any)
+		if !ok {
+			continue
+		}
+		var sku string
+		value, _ := jsonField(item, opts.SKUField)
+		switch v := value.(type) {
+		case string:
+			sku = strings.Join(strings.Fields(v), "_")
+		case json.Number:
+			sku = v.String()
+		}
+		if sku == "" {
+			continue
+		}
+		if opts.QuantityField == "" {
+			if !seen[sku] {
+				seen[sku] = true
+				basket = append(basket, sku)
+			}
+			continue
+		}
+		quantity := int64(1)
+		value, _ = jsonField(item, opts.QuantityField)
+		if n, ok := value.(json.Number); ok {
+			if q, err := n.Int64(); err == nil && q > 0 {
+				quantity = q
+			}
+		}
+		for ; quantity > 0; quantity-- {
+			basket = append(basket, sku)
+		}
+	}
+	return basket
+}
+
+// LoadOrderDataset reads order documents as a transaction of SKUs per order.
+// The file may hold a JSON array of orders, an object with an "orders"
+// array as in store exports, or one order per line; orders without SKUs
+// become empty transactions so supports stay relative to every order.
+func LoadOrderDataset(filename string, opts OrderOptions) (Dataset, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var dataset Dataset
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	decoder.UseNumber()
+	for {
+		var doc any
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		orders, ok := doc.([]any)
+		if obj, isObject := doc.(map[string]any); isObject {
+			if orders, ok = obj["orders"].([]any); !ok {
+				orders = []any{obj}
+			}
+		} else if !ok {
+			return nil, fmt.Errorf("%s: expected orders as JSON objects", filename)
+		}
+		for _, o := range orders {
+			order, ok := o.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: expected orders as JSON objects", filename)
+			}
+			dataset = append(dataset, orderBasket(order, opts))
+		}
+	}
+	return dataset, nil
+}
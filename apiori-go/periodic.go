@@ -0,0 +1,179 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PeriodicPattern is a frequent itemset with the regularity of its
+// occurrences in time
+type PeriodicPattern struct {
+	ItemsetResult
+	// Occurrences is the number of distinct time slots containing the itemset
+	Occurrences int `json:"occurrences"`
+	// Period is the median gap between consecutive occurrences
+	Period    time.Duration `json:"period"`
+	MeanGap   time.Duration `json:"meanGap"`
+	GapStdDev time.Duration `json:"gapStdDev"`
+	// MaxGap is the largest gap, including those from the start and to the
+	// end of the dataset, so a pattern that stopped occurring has a large one
+	MaxGap time.Duration `json:"maxGap"`
+	// Regularity is 1 less the coefficient of variation of the gaps, floored
+	// at 0: 1 for gaps of equal length
+	Regularity float64 `json:"regularity"`
+}
+
+// occurrenceSlots returns the distinct slots of granularity, in time order,
+// of the transactions containing set
+func occurrenceSlots(set ItemSet, dataset Dataset, times []time.Time, granularity time.Duration) []time.Time {
+	seen := make(map[time.Time]bool)
+	var slots []time.Time
+	for i, transaction := range dataset {
+		if !isSubset(set, transaction) {
+			continue
+		}
+		slot := times[i].UTC().Truncate(granularity)
+		if !seen[slot] {
+			seen[slot] = true
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+	return slots
+}
+
+// periodicity measures the gaps between the occurrence slots of a pattern
+// seen between first and last
+func periodicity(slots []time.Time, first, last time.Time) PeriodicPattern {
+	p := PeriodicPattern{Occurrences: len(slots)}
+	if len(slots) == 0 {
+		return p
+	}
+	p.MaxGap = max(slots[0].Sub(first), last.Sub(slots[len(slots)-1]))
+	if len(slots) < 2 {
+		return p
+	}
+	gaps := make([]float64, len(slots)-1)
+	sum := 0.0
+	for i := 1; i < len(slots); i++ {
+		gap := slots[i].Sub(slots[i-1])
+		gaps[i-1] = float64(gap)
+		sum += float64(gap)
+		p.MaxGap = max(p.MaxGap, gap)
+	}
+	mean := sum / float64(len(gaps))
+	variance := 0.0
+	for _, g := range gaps {
+		variance += (g - mean) * (g - mean)
+	}
+	std := math.Sqrt(variance / float64(len(gaps)))
+	sort.Float64s(gaps)
+	median := gaps[len(gaps)/2]
+	if len(gaps)%2 == 0 {
+		median = (gaps[len(gaps)/2-1] + gaps[len(gaps)/2]) / 2
+	}
+	p.Period, p.MeanGap, p.GapStdDev = time.Duration(median), time.Duration(mean), time.Duration(std)
+	p.Regularity = math.Max(0, 1-std/mean)
+	return p
+}
+
+// findPeriodicPatterns mines the frequent itemsets and keeps those occurring
+// in at least minOccurrences slots with no gap above maxGap (0 for any) and
+// a regularity of at least minRegularity, most regular first
+func findPeriodicPatterns(dataset Dataset, times []time.Time, minSupport float64, granularity, maxGap time.Duration,
+	minOccurrences int, minRegularity float64) []PeriodicPattern {
+	first, last := times[0], times[0]
+	for _, t := range times {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	first, last = first.UTC().Truncate(granularity), last.UTC().Truncate(granularity)
+
+	miner := NewAprioriMiner(dataset, minSupport)
+	miner.Mine()
+	var patterns []PeriodicPattern
+	for _, r := range miner.Results() {
+		p := periodicity(occurrenceSlots(toItemSet(r.Items), dataset, times, granularity), first, last)
+		if p.Occurrences < minOccurrences || (maxGap > 0 && p.MaxGap > maxGap) || p.Regularity < minRegularity {
+			continue
+		}
+		p.ItemsetResult = r
+		patterns = append(patterns, p)
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		if patterns[i].Regularity != patterns[j].Regularity {
+			return patterns[i].Regularity > patterns[j].Regularity
+		}
+		return patterns[i].Support > patterns[j].Support
+	})
+	return patterns
+}
+
+// writePeriodicCSV writes the patterns with their gaps in hours
+func writePeriodicCSV(path string, patterns []PeriodicPattern) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Size,Items,Support,Occurrences,PeriodHours,MeanGapHours,GapStdDevHours,MaxGapHours,Regularity")
+	for _, p := range patterns {
+		fmt.Fprintf(f, "%d,\"%s\",%f,%d,%f,%f,%f,%f,%f\n", p.Size, strings.Join(p.Items, ","), p.Support, p.Occurrences,
+			p.Period.Hours(), p.MeanGap.Hours(), p.GapStdDev.Hours(), p.MaxGap.Hours(), p.Regularity)
+	}
+	return f.Close()
+}
+
+// runPeriodic implements the periodic subcommand, which finds itemsets of a
+// timestamped dataset that recur at regular intervals
+func runPeriodic(args []string) error {
+	fs := flag.NewFlagSet("periodic", flag.ExitOnError)
+	minSupport := fs.Float64("minsupport", 0.01, "minimum support of an itemset over the whole dataset")
+	granularity := fs.Duration("granularity", 24*time.Hour, "time slot occurrences are counted in; several within a slot count once")
+	maxGap := fs.Duration("max-gap", 0, "largest gap between occurrences of a periodic itemset, e.g. 192h for weekly (0 for any)")
+	minOccurrences := fs.Int("min-occurrences", 3, "fewest slots an itemset must occur in")
+	minRegularity := fs.Float64("min-regularity", 0.5, "minimum regularity, 1 less the coefficient of variation of the gaps")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_periodic.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: periodic [flags] <timestamped dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if *granularity <= 0 || *maxGap < 0 || *minOccurrences < 2 {
+		return fmt.Errorf("granularity must be positive, max-gap non-negative and min-occurrences at least 2")
+	}
+
+	filename := fs.Arg(0)
+	dataset, times, err := LoadTimestampedDataset(filename)
+	if err != nil {
+		return err
+	}
+	if len(dataset) == 0 {
+		return fmt.Errorf("%s holds no transactions", filename)
+	}
+	patterns := findPeriodicPatterns(dataset, times, *minSupport, *granularity, *maxGap, *minOccurrences, *minRegularity)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_periodic.csv")
+	if err := writePeriodicCSV(path, patterns); err != nil {
+		return err
+	}
+	fmt.Printf("Found %d periodic itemsets; written to %s\n", len(patterns), path)
+	return nil
+}
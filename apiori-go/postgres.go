@@ -0,0 +1,152 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// postgresTables are created on first use; items are stored as text arrays
+// so dashboards can filter with the array operators
+var postgresTables = []string{
+	`CREATE TABLE IF NOT EXISTS %s (
+		run_id             text PRIMARY KEY,
+		dataset            text NOT NULL,
+		min_support        double precision NOT NULL,
+		min_confidence     double precision NOT NULL,
+		transactions       integer NOT NULL,
+		itemsets           integer NOT NULL,
+		rules              integer NOT NULL,
+		data_load_seconds  double precision NOT NULL,
+		processing_seconds double precision NOT NULL,
+		total_seconds      double precision NOT NULL,
+		finished_at        timestamptz NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS %s (
+		run_id         text NOT NULL,
+		size           integer NOT NULL,
+		items          text[] NOT NULL,
+		count          integer NOT NULL,
+		support        double precision NOT NULL,
+		all_confidence double precision,
+		kulczynski     double precision,
+		cosine         double precision
+	)`,
+	`CREATE TABLE IF NOT EXISTS %s (
+		run_id           text NOT NULL,
+		antecedent       text[] NOT NULL,
+		consequent       text[] NOT NULL,
+		support          double precision NOT NULL,
+		confidence       double precision NOT NULL,
+		lift             double precision NOT NULL,
+		chi_square       double precision,
+		p_value          double precision,
+		fisher_p_value   double precision,
+		adjusted_p_value double precision,
+		all_confidence   double precision,
+		kulczynski       double precision,
+		cosine           double precision,
+		leverage         double precision,
+		normalized_leverage double precision
+	)`,
+}
+
+// postgresAddedColumns were added to the tables after their first release
+// and are added to tables created before them
+var postgresAddedColumns = []struct{ table, column string }{
+	{"apriori_rules", "chi_square double precision"},
+	{"apriori_rules", "p_value double precision"},
+	{"apriori_rules", "fisher_p_value double precision"},
+	{"apriori_rules", "adjusted_p_value double precision"},
+	{"apriori_itemsets", "all_confidence double precision"},
+	{"apriori_itemsets", "kulczynski double precision"},
+	{"apriori_itemsets", "cosine double precision"},
+	{"apriori_rules", "all_confidence double precision"},
+	{"apriori_rules", "kulczynski double precision"},
+	{"apriori_rules", "cosine double precision"},
+	{"apriori_rules", "leverage double precision"},
+	{"apriori_rules", "normalized_leverage double precision"},
+}
+
+// WriteResultsToPostgres stores a run with its itemsets and rules in the
+// apriori_runs, apriori_itemsets and apriori_rules tables of schema, creating
+// them if needed. The run is written in one transaction and replaces any
+// earlier rows with the same run ID.
+func WriteResultsToPostgres(ctx context.Context, dsn, schema string, run runRecord, results []ItemsetResult, rules []Rule) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	runs := pgx.Identifier{schema, "apriori_runs"}
+	itemsets := pgx.Identifier{schema, "apriori_itemsets"}
+	ruleTable := pgx.Identifier{schema, "apriori_rules"}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to create schema %s: %v", schema, err)
+	}
+	for i, table := range []pgx.Identifier{runs, itemsets, ruleTable} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(postgresTables[i], table.Sanitize())); err != nil {
+			return fmt.Errorf("failed to create table %s: %v", table.Sanitize(), err)
+		}
+	}
+	for _, added := range postgresAddedColumns {
+		table := pgx.Identifier{schema, added.table}.Sanitize()
+		if _, err := tx.Exec(ctx, "ALTER TABLE "+table+" ADD COLUMN IF NOT EXISTS "+added.column); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %v", table, err)
+		}
+	}
+	for _, table := range []pgx.Identifier{itemsets, ruleTable, runs} {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table.Sanitize()+" WHERE run_id = $1", run.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx, runs,
+		[]string{"run_id", "dataset", "min_support", "min_confidence", "transactions", "itemsets", "rules",
+			"data_load_seconds", "processing_seconds", "total_seconds", "finished_at"},
+		pgx.CopyFromRows([][]interface{}{{
+			run.ID, run.Dataset, run.MinSupport, run.MinConfidence, run.Transactions, len(results), len(rules),
+			run.Metrics.DataLoadTime, run.Metrics.ProcessingTime, run.Metrics.TotalTime, time.Now().UTC(),
+		}}))
+	if err != nil {
+		return fmt.Errorf("failed to write run: %v", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, itemsets,
+		[]string{"run_id", "size", "items", "count", "support", "all_confidence", "kulczynski", "cosine"},
+		pgx.CopyFromSlice(len(results), func(i int) ([]interface{}, error) {
+			r := results[i]
+			return []interface{}{run.ID, r.Size, r.Items, r.Count, r.Support, r.AllConfidence, r.Kulczynski, r.Cosine}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("failed to write itemsets: %v", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, ruleTable,
+		[]string{"run_id", "antecedent", "consequent", "support", "confidence", "lift",
+			"chi_square", "p_value", "fisher_p_value", "adjusted_p_value", "all_confidence", "kulczynski", "cosine",
+			"leverage", "normalized_leverage"},
+		pgx.CopyFromSlice(len(rules), func(i int) ([]interface{}, error) {
+			r := rules[i]
+			return []interface{}{run.ID, r.Antecedent, r.Consequent, r.Support, r.Confidence, r.Lift,
+				r.ChiSquare, r.PValue, r.FisherPValue, r.AdjustedPValue, r.AllConfidence, r.Kulczynski, r.Cosine,
+				r.Leverage, r.NormalizedLeverage}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("failed to write rules: %v", err)
+	}
+
+	return tx.Commit(ctx)
+}
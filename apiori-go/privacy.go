@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// laplace draws from the Laplace distribution of mean 0 and the given scale
+func laplace(rng *rand.Rand, scale float64) float64 {
+	u := rng.Float64() - 0.5
+	return -scale * math.Copysign(1, u) * math.Log(1-2*math.Abs(u))
+}
+
+// PrivatizeSupports replaces the counts of the frequent itemsets by noisy
+// ones before anything is published. Every count gets Laplace noise of
+// scale n/epsilon for the n itemsets found, so that the counts together are
+// epsilon-differentially private by sequential composition; itemsets whose
+// noisy count falls below floor, or below 1 whatever it is, are suppressed
+// with their supersets. Noisy counts are clamped to [0, transactions] and to
+// those of their subsets, which costs no privacy.
+//
+// Only the counts are noised. Which itemsets are published is decided by the
+// exact counts against the minimum support, and n, which sets the noise
+// scale, is counted on the exact data too, so the output as a whole is not
+// differentially private: the presence of an itemset, and the scale, can
+// reveal exact counts near the threshold. The floor should sit well above
+// the noise scale for rare itemsets not to show through. Call it after
+// mining and before any output; it returns the itemsets kept and suppressed.
+func (am *AprioriMiner) PrivatizeSupports(epsilon float64, floor int, rng *rand.Rand) (published, suppressed int) {
+	scale := float64(am.getTotalFrequentItemsets()) / epsilon
+	// An itemset no transaction holds has no support to publish, and would
+	// leave its rules dividing by zero
+	floor = max(floor, 1)
+	kept := make(map[string]bool)
+	for _, k := range am.levels() {
+		var level []ItemSet
+		for _, itemset := range am.frequentSets[k] {
+			key := itemsetKey(itemset)
+			noisy := float64(am.supportCounts[key]) + laplace(rng, scale)
+			count := min(max(0, int(math.Round(noisy))), am.transactionLen)
+			// A subset suppressed or less frequent bounds the itemset
+			closed := true
+			if k > 1 {
+				items := sortedItems(itemset)
+				for skip := range items {
+					subset := make([]string, 0, k-1)
+					subset = append(append(subset, items[:skip]...), items[skip+1:]...)
+					subsetKey := itemsetKey(toItemSet(subset))
+					if !kept[subsetKey] {
+						closed = false
+						break
+					}
+					count = min(count, am.supportCounts[subsetKey])
+				}
+			}
+			if !closed || count < floor {
+				delete(am.supportCounts, key)
+				suppressed++
+				continue
+			}
+			am.supportCounts[key] = count
+			kept[key] = true
+			level = append(level, itemset)
+		}
+		if len(level) == 0 {
+			delete(am.frequentSets, k)
+		} else {
+			am.frequentSets[k] = level
+		}
+		published += len(level)
+	}
+	// Pair and pruned item counts are exact and must not leave the miner
+	am.pairCounts = nil
+	am.prunedItems = nil
+	return published, suppressed
+}
+
+// SuppressBelowCount withholds from every output the frequent itemsets held
+// by fewer than k transactions, whatever the minimum support, and their
+// rules with them. The supersets of a withheld itemset are never held by
+// more transactions, so what remains stays closed under subsets. It returns
+// the number of itemsets withheld.
+func (am *AprioriMiner) SuppressBelowCount(k int) int {
+	withheld := 0
+	for _, level := range am.levels() {
+		kept := am.frequentSets[level][:0]
+		for _, itemset := range am.frequentSets[level] {
+			key := itemsetKey(itemset)
+			if am.supportCounts[key] >= k {
+				kept = append(kept, itemset)
+				continue
+			}
+			delete(am.supportCounts, key)
+			withheld++
+		}
+		if len(kept) == 0 {
+			delete(am.frequentSets, level)
+		} else {
+			am.frequentSets[level] = kept
+		}
+	}
+	// The pruned items are rarer still
+	kept := am.prunedItems[:0]
+	for _, p := range am.prunedItems {
+		if p.Count >= k {
+			kept = append(kept, p)
+		}
+	}
+	am.prunedItems = kept
+	return withheld
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Noise large against the counts of a small dataset must still publish
+// supports in [0,1] and rules with finite measures
+func TestPrivatizedRulesStayInRange(t *testing.T) {
+	dataset := Dataset{
+		{"a", "b", "c"},
+		{"a", "b"},
+		{"b", "c"},
+		{"a", "c"},
+		{"a", "b", "c"},
+	}
+	for seed := int64(1); seed <= 50; seed++ {
+		miner := NewAprioriMiner(dataset, 0.2)
+		miner.Mine()
+		miner.PrivatizeSupports(0.05, 0, rand.New(rand.NewSource(seed)))
+		for _, result := range miner.Results() {
+			if result.Support < 0 || result.Support > 1 {
+				t.Fatalf("seed %d: %v has support %g", seed, result.Items, result.Support)
+			}
+		}
+		for _, rule := range miner.GenerateRules(0) {
+			if rule.Support < 0 || rule.Support > 1 {
+				t.Fatalf("seed %d: rule %v => %v has support %g", seed, rule.Antecedent, rule.Consequent, rule.Support)
+			}
+			for name, value := range map[string]float64{"confidence": rule.Confidence, "lift": rule.Lift,
+				"kulczynski": rule.Kulczynski, "cosine": rule.Cosine} {
+				if math.IsNaN(value) || math.IsInf(value, 0) {
+					t.Fatalf("seed %d: rule %v => %v has %s %g", seed, rule.Antecedent, rule.Consequent, name, value)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Pseudonymizer replaces item names by salted hashes, so result files can
+// circulate without the names. The same salt always yields the same
+// pseudonyms; without the salt or the mapping they cannot be reversed.
+type Pseudonymizer struct {
+	salt  []byte
+	items map[string]string
+}
+
+// NewPseudonymizer returns a pseudonymizer salted with salt, or with a random
+// salt when it is empty, making the pseudonyms differ on every run
+func NewPseudonymizer(salt string) (*Pseudonymizer, error) {
+	key := []byte(salt)
+	if salt == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &Pseudonymizer{salt: key, items: make(map[string]string)}, nil
+}
+
+// name returns the pseudonym of item: "h" and the first 16 hex digits of its
+// HMAC-SHA256 under the salt
+func (p *Pseudonymizer) name(item string) (string, error) {
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(item))
+	pseudonym := "h" + hex.EncodeToString(mac.Sum(nil))[:16]
+	if other, ok := p.items[pseudonym]; ok && other != item {
+		return "", fmt.Errorf("items %q and %q share the pseudonym %s", other, item, pseudonym)
+	}
+	p.items[pseudonym] = item
+	return pseudonym, nil
+}
+
+// Names returns the pseudonyms of items
+func (p *Pseudonymizer) Names(items []string) ([]string, error) {
+	names := make([]string, len(items))
+	for i, item := range items {
+		var err error
+		if names[i], err = p.name(item); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// Dataset replaces the items of every transaction by their pseudonyms in
+// place
+func (p *Pseudonymizer) Dataset(dataset Dataset) error {
+	for _, t := range dataset {
+		for i, item := range t {
+			var err error
+			if t[i], err = p.name(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Attributes returns the attribute table keyed by pseudonyms
+func (p *Pseudonymizer) Attributes(attributes ItemAttributes) (ItemAttributes, error) {
+	out := make(ItemAttributes, len(attributes))
+	for item, values := range attributes {
+		pseudonym, err := p.name(item)
+		if err != nil {
+			return nil, err
+		}
+		out[pseudonym] = values
+	}
+	return out, nil
+}
+
+// WriteMapping writes every pseudonym issued with its item name, the key to
+// reversing the result files that must be kept apart from them
+func (p *Pseudonymizer) WriteMapping(path string) error {
+	pseudonyms := make([]string, 0, len(p.items))
+	for pseudonym := range p.items {
+		pseudonyms = append(pseudonyms, pseudonym)
+	}
+	sort.Strings(pseudonyms)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Pseudonym,Item")
+	for _, pseudonym := range pseudonyms {
+		fmt.Fprintf(f, "%s,\"%s\"\n", pseudonym, p.items[pseudonym])
+	}
+	return f.Close()
+}
@@ -0,0 +1,112 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Recommendation is an item suggested for a basket with the rules that
+// predict it, best first
+type Recommendation struct {
+	Item  string `json:"item"`
+	Rules []Rule `json:"rules"`
+}
+
+// Recommend suggests up to n items missing from basket, ranked by the best
+// rule predicting each one in the order of rules. A rule applies when its
+// antecedent is in the basket. Only single-item consequents are used: a
+// larger consequent never has a higher confidence than its items alone.
+func Recommend(rules []Rule, basket []string, n int) []Recommendation {
+	in := toItemSet(basket)
+	var out []Recommendation
+	index := make(map[string]int)
+	for _, rule := range rules {
+		if len(rule.Consequent) != 1 || in[rule.Consequent[0]] {
+			continue
+		}
+		applies := true
+		for _, item := range rule.Antecedent {
+			if !in[item] {
+				applies = false
+				break
+			}
+		}
+		if !applies {
+			continue
+		}
+		item := rule.Consequent[0]
+		i, ok := index[item]
+		if !ok {
+			i = len(out)
+			index[item] = i
+			out = append(out, Recommendation{Item: item})
+		}
+		out[i].Rules = append(out[i].Rules, rule)
+	}
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// modelSummaryPath resolves the -model of recommend, the prefix of a run's
+// result files or its summary file, to the summary file
+func modelSummaryPath(model string) string {
+	if strings.HasSuffix(model, ".csv") {
+		return model
+	}
+	return model + "_summary.csv"
+}
+
+// runRecommend implements the recommend subcommand, which suggests items for
+// a basket from the rules of a saved result
+func runRecommend(args []string) error {
+	fs := flag.NewFlagSet("recommend", flag.ExitOnError)
+	model := fs.String("model", "", "saved result, e.g. results/run1 for results/run1_summary.csv")
+	basket := fs.String("basket", "", "comma-separated items in the basket")
+	n := fs.Int("n", 5, "items suggested")
+	minConfidence := fs.Float64("minconfidence", 0.1, "minimum confidence of a supporting rule")
+	rankBy := fs.String("rank-by", RankConfidence, "order of the suggestions: confidence, lift, leverage or normalized-leverage")
+	transactions := fs.Int("transactions", 0, "transactions of the mined dataset (default: from the run manifest)")
+	show := fs.Int("show-rules", 3, "supporting rules printed per suggestion")
+	fs.Parse(args)
+	if *model == "" || *basket == "" || fs.NArg() != 0 {
+		return fmt.Errorf("usage: recommend -model results/<run> -basket item,item [flags]")
+	}
+	if *n < 1 {
+		return fmt.Errorf("n must be at least 1")
+	}
+	if err := SortRulesBy(nil, *rankBy); err != nil {
+		return err
+	}
+	var items []string
+	for _, item := range strings.Split(*basket, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	miner, _, err := loadSavedItemsets(modelSummaryPath(*model), *transactions)
+	if err != nil {
+		return err
+	}
+	rules := miner.GenerateRules(*minConfidence)
+	SortRulesBy(rules, *rankBy)
+	suggestions := Recommend(rules, items, *n)
+
+	fmt.Printf("Suggestions for {%s}:\n", strings.Join(items, ", "))
+	if len(suggestions) == 0 {
+		fmt.Println("  none: no rule applies to this basket")
+	}
+	for i, s := range suggestions {
+		best := s.Rules[0]
+		fmt.Printf("%3d. %-20s confidence %.3f  lift %.3f  support %.3f\n", i+1, s.Item, best.Confidence, best.Lift, best.Support)
+		for _, rule := range s.Rules[:min(*show, len(s.Rules))] {
+			fmt.Printf("       %s (confidence %.3f, lift %.3f)\n", rule, rule.Confidence, rule.Lift)
+		}
+	}
+	return nil
+}
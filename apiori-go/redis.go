@@ -0,0 +1,194 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errRedisNil is returned for replies that carry no value, such as GET on a missing key
+var errRedisNil = errors.New("redis: nil reply")
+
+// RedisCache is a minimal Redis client speaking RESP over a single connection,
+// sufficient for caching mining results with GET and SET EX
+type RedisCache struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisCache creates a cache client for the Redis server at addr. The
+// connection is established on first use and re-established after errors.
+func NewRedisCache(addr, password string, db int) *RedisCache {
+	return &RedisCache{
+		addr:     addr,
+		password: password,
+		db:       db,
+		timeout:  5 * time.Second,
+	}
+}
+
+// Get returns the value stored under key and whether it was present
+func (c *RedisCache) Get(key string) ([]byte, bool, error) {
+	reply, err := c.do("GET", key)
+	if err == errRedisNil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+	return []byte(value), true, nil
+}
+
+// Set stores value under key, expiring it after ttl
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) error {
+	seconds := int(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	_, err := c.do("SET", key, string(value), "EX", strconv.Itoa(seconds))
+	return err
+}
+
+// Close closes the underlying connection
+func (c *RedisCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// do sends a command and reads its reply, reconnecting if needed
+func (c *RedisCache) do(args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(args)
+	if err != nil && err != errRedisNil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// The connection is in an unknown state; drop it so the next call reconnects
+			c.conn.Close()
+			c.conn = nil
+		}
+	}
+	return reply, err
+}
+
+func (c *RedisCache) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, c.timeout)
+	if err != nil {
+		return fmt.Errorf("redis: %v", err)
+	}
+	c.conn = conn
+	c.rd = bufio.NewReader(conn)
+	if c.password != "" {
+		if _, err := c.roundTrip([]string{"AUTH", c.password}); err != nil {
+			c.conn.Close()
+			c.conn = nil
+			return err
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip([]string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			c.conn.Close()
+			c.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RedisCache) roundTrip(args []string) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, fmt.Errorf("redis: %v", err)
+	}
+	return readRESP(c.rd)
+}
+
+// redisError is an error reply sent by the server
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readRESP parses a single RESP reply
+func readRESP(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %v", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, fmt.Errorf("redis: %v", err)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := readRESP(rd)
+			if err != nil && err != errRedisNil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
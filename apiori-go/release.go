@@ -0,0 +1,59 @@
+package main
+
+import "strings"
+
+// SetReleaseLevels makes the miner hold each level's itemsets only as their
+// keys once the next level's candidates are generated, instead of as sets,
+// lowering the peak memory of deep mines. The supports output and rule
+// generation need stay counted by key, and the levels are rebuilt as sets
+// when mining ends.
+func (am *AprioriMiner) SetReleaseLevels(release bool) {
+	am.releaseLevels = release
+}
+
+// releaseLevel replaces the itemsets of level k by their keys, in order
+func (am *AprioriMiner) releaseLevel(k int) {
+	sets, ok := am.frequentSets[k]
+	if !ok {
+		return
+	}
+	if am.released == nil {
+		am.released = make(map[int][]string)
+	}
+	keys := make([]string, len(sets))
+	for i, set := range sets {
+		keys[i] = itemsetKey(set)
+	}
+	am.released[k] = keys
+	delete(am.frequentSets, k)
+}
+
+// filterReleased drops the released itemsets below minSupport, as
+// raiseSupport does for the held ones
+func (am *AprioriMiner) filterReleased(minSupport float64) {
+	for k, keys := range am.released {
+		kept := keys[:0]
+		for _, key := range keys {
+			if float64(am.supportCounts[key])/float64(am.transactionLen) >= minSupport {
+				kept = append(kept, key)
+			}
+		}
+		if len(kept) == 0 {
+			delete(am.released, k)
+		} else {
+			am.released[k] = kept
+		}
+	}
+}
+
+// restoreLevels rebuilds the released levels as sets
+func (am *AprioriMiner) restoreLevels() {
+	for k, keys := range am.released {
+		sets := make([]ItemSet, len(keys))
+		for i, key := range keys {
+			sets[i] = toItemSet(strings.Split(key, "\x00"))
+		}
+		am.frequentSets[k] = sets
+	}
+	am.released = nil
+}
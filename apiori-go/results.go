@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ItemsetResult is a frequent itemset in a form suitable for serialization
+type ItemsetResult struct {
+	Size    int      `json:"size"`
+	Items   []string `json:"items"`
+	Count   int      `json:"count"`
+	Support float64  `json:"support"`
+	// Null-invariant measures of how strongly the items occur together
+	AllConfidence float64 `json:"allConfidence"`
+	Kulczynski    float64 `json:"kulczynski"`
+	Cosine        float64 `json:"cosine"`
+	// SupportCI is set when bootstrap intervals were requested
+	SupportCI *Interval `json:"supportCI,omitempty"`
+}
+
+// Results returns all frequent itemsets ordered by size and then by items
+func (am *AprioriMiner) Results() []ItemsetResult {
+	out := make([]ItemsetResult, 0, am.getTotalFrequentItemsets())
+	for k, itemsets := range am.frequentSets {
+		for _, itemset := range itemsets {
+			count := am.countSupport(itemset)
+			support := float64(count) / float64(am.transactionLen)
+			allConfidence, kulczynski, cosine := am.itemsetMeasures(itemset, support)
+			out = append(out, ItemsetResult{
+				Size:          k,
+				Items:         sortedItems(itemset),
+				Count:         count,
+				Support:       support,
+				AllConfidence: allConfidence,
+				Kulczynski:    kulczynski,
+				Cosine:        cosine,
+			})
+		}
+	}
+	sortResults(out)
+	return out
+}
+
+// itemsetMeasures returns the null-invariant measures of a frequent itemset
+// from the supports of its items
+func (am *AprioriMiner) itemsetMeasures(itemset ItemSet, support float64) (float64, float64, float64) {
+	itemSupports := make([]float64, 0, len(itemset))
+	for item := range itemset {
+		itemSupports = append(itemSupports, am.calculateSupport(ItemSet{item: true}))
+	}
+	return nullInvariantMeasures(support, itemSupports)
+}
+
+// sortResults orders results by size and then lexicographically by items
+func sortResults(results []ItemsetResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Size != results[j].Size {
+			return results[i].Size < results[j].Size
+		}
+		return strings.Join(results[i].Items, "\x00") < strings.Join(results[j].Items, "\x00")
+	})
+}
+
+// WriteResultsCSV writes itemset results using the layout of the summary file
+func WriteResultsCSV(w io.Writer, results []ItemsetResult) error {
+	if _, err := io.WriteString(w, "Size,Items,Count,Support,AllConfidence,Kulczynski,Cosine\n"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		items := strings.Join(r.Items, ",")
+		if _, err := fmt.Fprintf(w, "%d,\"%s\",%d,%f,%f,%f,%f\n", r.Size, items, r.Count, r.Support, r.AllConfidence, r.Kulczynski, r.Cosine); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadResultsCSV reads itemsets written in the layout of the summary file.
+// Only the Size, Items and Support columns are required; the counts are left
+// unset for files written before the summary recorded them.
+func ReadResultsCSV(r io.Reader) ([]ItemsetResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	// Skips the measure documentation written by -measure-docs
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"Size", "Items", "Support"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("results file has no %s column", name)
+		}
+	}
+	float := func(record []string, name string) (float64, error) {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return 0, nil
+		}
+		return strconv.ParseFloat(record[i], 64)
+	}
+
+	var results []ItemsetResult
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) <= columns["Support"] || len(record) <= columns["Items"] || len(record) <= columns["Size"] {
+			return nil, fmt.Errorf("line %d: missing columns", line)
+		}
+		var r ItemsetResult
+		if r.Size, err = strconv.Atoi(record[columns["Size"]]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid size: %v", line, err)
+		}
+		r.Items = strings.Split(record[columns["Items"]], ",")
+		sort.Strings(r.Items)
+		if r.Support, err = float(record, "Support"); err != nil {
+			return nil, fmt.Errorf("line %d: invalid support: %v", line, err)
+		}
+		if i, ok := columns["Count"]; ok && i < len(record) {
+			if r.Count, err = strconv.Atoi(record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid count: %v", line, err)
+			}
+		}
+		r.AllConfidence, _ = float(record, "AllConfidence")
+		r.Kulczynski, _ = float(record, "Kulczynski")
+		r.Cosine, _ = float(record, "Cosine")
+		results = append(results, r)
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// runRecord is the metadata stored by the results sinks next to a run's
+// itemsets and rules
+type runRecord struct {
+	ID            string
+	Dataset       string
+	MinSupport    float64
+	MinConfidence float64
+	Transactions  int
+	Metrics       TimingMetrics
+}
+
+// minerFromResults rebuilds a miner holding previously mined itemsets so that
+// rules can be derived from stored results without the dataset
+func minerFromResults(results []ItemsetResult, minSupport float64) *AprioriMiner {
+	miner := NewAprioriMiner(nil, minSupport)
+	for _, r := range results {
+		if miner.transactionLen == 0 && r.Support > 0 {
+			miner.transactionLen = int(math.Round(float64(r.Count) / r.Support))
+		}
+		set := toItemSet(r.Items)
+		miner.frequentSets[r.Size] = append(miner.frequentSets[r.Size], set)
+		miner.supportCounts[itemsetKey(set)] = r.Count
+	}
+	return miner
+}
@@ -0,0 +1,240 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// categoryPrefix marks category items in a dataset mixing both levels
+const categoryPrefix = "category="
+
+// RuleDecomposition is a category rule with the item rules beneath it
+type RuleDecomposition struct {
+	Category Rule   `json:"category"`
+	Items    []Rule `json:"items"`
+}
+
+// LoadItemCategories reads an "item category" line per item. Blank lines and
+// lines starting with # are ignored.
+func LoadItemCategories(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"item category\"", filename, i+1)
+		}
+		categories[fields[0]] = fields[1]
+	}
+	return categories, nil
+}
+
+// categoryOf maps an item to its category; unmapped items are their own
+func categoryOf(categories map[string]string, item string) string {
+	if c, ok := categories[item]; ok {
+		return c
+	}
+	return item
+}
+
+// rollUpDataset replaces every item by its category, once per transaction
+func rollUpDataset(dataset Dataset, categories map[string]string) Dataset {
+	out := make(Dataset, len(dataset))
+	for i, transaction := range dataset {
+		seen := make(map[string]bool, len(transaction))
+		for _, item := range transaction {
+			c := categoryOf(categories, item)
+			if !seen[c] {
+				seen[c] = true
+				out[i] = append(out[i], c)
+			}
+		}
+	}
+	return out
+}
+
+// extendDataset adds the category of every mapped item to its transaction,
+// marked with categoryPrefix, for mining both levels together
+func extendDataset(dataset Dataset, categories map[string]string) Dataset {
+	out := make(Dataset, len(dataset))
+	for i, transaction := range dataset {
+		seen := make(map[string]bool)
+		out[i] = append(Transaction{}, transaction...)
+		for _, item := range transaction {
+			c, ok := categories[item]
+			if ok && !seen[c] {
+				seen[c] = true
+				out[i] = append(out[i], categoryPrefix+c)
+			}
+		}
+	}
+	return out
+}
+
+// redundantAcrossLevels reports whether a mixed-level rule holds an item
+// together with its own category, which it contains by construction
+func redundantAcrossLevels(rule Rule, categories map[string]string) bool {
+	present := make(map[string]bool)
+	for _, side := range [][]string{rule.Antecedent, rule.Consequent} {
+		for _, item := range side {
+			present[item] = true
+		}
+	}
+	for item := range present {
+		if c, ok := categories[item]; ok && present[categoryPrefix+c] {
+			return true
+		}
+	}
+	return false
+}
+
+// decomposeRules groups the item rules under the category rule whose sides
+// are the categories of their sides
+func decomposeRules(categoryRules, itemRules []Rule, categories map[string]string) []RuleDecomposition {
+	side := func(items []string) string {
+		seen := make(map[string]bool)
+		var cats []string
+		for _, item := range items {
+			c := categoryOf(categories, item)
+			if !seen[c] {
+				seen[c] = true
+				cats = append(cats, c)
+			}
+		}
+		sort.Strings(cats)
+		return strings.Join(cats, ",")
+	}
+	byKey := make(map[string][]Rule)
+	for _, rule := range itemRules {
+		key := side(rule.Antecedent) + " -> " + side(rule.Consequent)
+		byKey[key] = append(byKey[key], rule)
+	}
+	out := make([]RuleDecomposition, 0, len(categoryRules))
+	for _, rule := range categoryRules {
+		out = append(out, RuleDecomposition{Category: rule, Items: byKey[rule.String()]})
+	}
+	return out
+}
+
+// writeDecompositionCSV writes one line per category rule and item rule
+// beneath it; category rules without item rules get a line of their own
+func writeDecompositionCSV(path string, decompositions []RuleDecomposition) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "CategoryAntecedent,CategoryConsequent,CategorySupport,CategoryConfidence,CategoryLift,Antecedent,Consequent,Support,Confidence,Lift,SupportShare")
+	for _, d := range decompositions {
+		c := d.Category
+		prefix := fmt.Sprintf("\"%s\",\"%s\",%f,%f,%f", strings.Join(c.Antecedent, ","), strings.Join(c.Consequent, ","),
+			c.Support, c.Confidence, c.Lift)
+		if len(d.Items) == 0 {
+			fmt.Fprintf(f, "%s,,,,,,\n", prefix)
+		}
+		for _, r := range d.Items {
+			fmt.Fprintf(f, "%s,\"%s\",\"%s\",%f,%f,%f,%f\n", prefix, strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","),
+				r.Support, r.Confidence, r.Lift, r.Support/c.Support)
+		}
+	}
+	return f.Close()
+}
+
+// writeRulesFile writes rules with WriteRulesCSV to path, after the formulas
+// of the measures when measureDocs is set
+func writeRulesFile(path string, rules []Rule, measureDocs bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if measureDocs {
+		if err := writeMeasureDocs(f, ruleColumns); err != nil {
+			return err
+		}
+	}
+	if err := WriteRulesCSV(f, rules); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// runRollup implements the rollup subcommand, which mines a dataset at
+// category level and breaks the category rules down into item rules
+func runRollup(args []string) error {
+	fs := flag.NewFlagSet("rollup", flag.ExitOnError)
+	categoriesPath := fs.String("categories", "", "file of \"item category\" lines")
+	minSupport := fs.Float64("minsupport", 0.1, "minimum support of category itemsets")
+	itemMinSupport := fs.Float64("item-minsupport", 0, "minimum support of item itemsets (default: minsupport)")
+	minConfidence := fs.Float64("minconfidence", 0.5, "minimum confidence of the rules")
+	both := fs.Bool("both", false, "also mine items and categories together into <dataset>_multilevel_rules.csv")
+	outputDir := fs.String("output-dir", "results", "directory receiving the roll-up reports")
+	fs.Parse(args)
+	if fs.NArg() != 1 || *categoriesPath == "" {
+		return fmt.Errorf("usage: rollup -categories file [flags] <dataset>")
+	}
+	if *itemMinSupport == 0 {
+		*itemMinSupport = *minSupport
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) || !(*itemMinSupport > 0 && *itemMinSupport <= 1) {
+		return fmt.Errorf("minsupport and item-minsupport must be in (0,1]")
+	}
+	categories, err := LoadItemCategories(*categoriesPath)
+	if err != nil {
+		return err
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	categoryMiner := NewAprioriMiner(rollUpDataset(dataset, categories), *minSupport)
+	categoryMiner.Mine()
+	categoryRules := categoryMiner.GenerateRules(*minConfidence)
+	itemMiner := NewAprioriMiner(dataset, *itemMinSupport)
+	itemMiner.Mine()
+	decompositions := decomposeRules(categoryRules, itemMiner.GenerateRules(*minConfidence), categories)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	base := getOutputBasename(filename)
+	if err := writeRulesFile(filepath.Join(*outputDir, base+"_category_rules.csv"), categoryRules, false); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, base+"_rule_decomposition.csv")
+	if err := writeDecompositionCSV(path, decompositions); err != nil {
+		return err
+	}
+	fmt.Printf("Mined %d category rules; decomposition written to %s\n", len(categoryRules), path)
+
+	if *both {
+		miner := NewAprioriMiner(extendDataset(dataset, categories), *itemMinSupport)
+		miner.Mine()
+		var rules []Rule
+		for _, rule := range miner.GenerateRules(*minConfidence) {
+			if !redundantAcrossLevels(rule, categories) {
+				rules = append(rules, rule)
+			}
+		}
+		path := filepath.Join(*outputDir, base+"_multilevel_rules.csv")
+		if err := writeRulesFile(path, rules, false); err != nil {
+			return err
+		}
+		fmt.Printf("Mined %d rules across both levels; written to %s\n", len(rules), path)
+	}
+	return nil
+}
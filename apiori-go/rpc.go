@@ -0,0 +1,376 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// rpcProtocolVersion is bumped on incompatible changes to the rpc protocol
+const rpcProtocolVersion = 1
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	// rpcAppError reports failures of a valid call, e.g. querying before mining
+	rpcAppError = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+func rpcErrorf(code int, format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// rpcSession holds the datasets and miners of one rpc process, by name
+type rpcSession struct {
+	datasets map[string]Dataset
+	miners   map[string]*AprioriMiner
+}
+
+// rpcDatasetName defaults the dataset name used by every method
+func rpcDatasetName(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+type rpcLoadParams struct {
+	Name         string        `json:"name"`
+	Path         string        `json:"path"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+type rpcMineParams struct {
+	Name       string  `json:"name"`
+	MinSupport float64 `json:"minSupport"`
+	MinLift    float64 `json:"minLift"`
+	Multiset   bool    `json:"multiset"`
+}
+
+type rpcQueryParams struct {
+	Name       string   `json:"name"`
+	Contains   []string `json:"contains"`
+	Size       int      `json:"size"`
+	MinSupport float64  `json:"minSupport"`
+	Limit      int      `json:"limit"`
+	rpcBootstrapParams
+}
+
+type rpcRulesParams struct {
+	Name              string   `json:"name"`
+	MinConfidence     *float64 `json:"minConfidence"`
+	MinLift           float64  `json:"minLift"`
+	MaxPValue         *float64 `json:"maxPValue"`
+	PAdjust           string   `json:"pAdjust"`
+	MaxAdjustedPValue *float64 `json:"maxAdjustedPValue"`
+	MinImprovement    *float64 `json:"minImprovement"`
+	Limit             int      `json:"limit"`
+	rpcBootstrapParams
+}
+
+// rpcBootstrapParams request bootstrap intervals on query and rules results
+type rpcBootstrapParams struct {
+	Bootstrap      int     `json:"bootstrap"`
+	BootstrapLevel float64 `json:"bootstrapLevel"`
+	BootstrapSeed  int64   `json:"bootstrapSeed"`
+}
+
+// apply attaches intervals to results and rules when bootstrap is requested
+func (p rpcBootstrapParams) apply(dataset Dataset, results []ItemsetResult, rules []Rule) error {
+	if p.Bootstrap <= 0 {
+		return nil
+	}
+	level := p.BootstrapLevel
+	if level == 0 {
+		level = 0.95
+	}
+	if !(level > 0 && level < 1) {
+		return rpcErrorf(rpcInvalidParams, "bootstrapLevel must be in (0,1)")
+	}
+	seed := p.BootstrapSeed
+	if seed == 0 {
+		seed = 1
+	}
+	BootstrapIntervals(dataset, results, rules, p.Bootstrap, level, seed)
+	return nil
+}
+
+// decodeParams strictly decodes params into v so typos surface as errors
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return rpcErrorf(rpcInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+// miner returns the miner of a mined dataset
+func (s *rpcSession) miner(name string) (*AprioriMiner, error) {
+	miner, ok := s.miners[rpcDatasetName(name)]
+	if !ok {
+		return nil, rpcErrorf(rpcAppError, "dataset %q has not been mined", rpcDatasetName(name))
+	}
+	return miner, nil
+}
+
+// call dispatches one method
+func (s *rpcSession) call(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "version":
+		return map[string]int{"protocol": rpcProtocolVersion}, nil
+
+	case "load":
+		var p rpcLoadParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		dataset := Dataset(p.Transactions)
+		if p.Path != "" {
+			if p.Transactions != nil {
+				return nil, rpcErrorf(rpcInvalidParams, "give either path or transactions")
+			}
+			var err error
+			if dataset, err = LoadDataset(p.Path); err != nil {
+				return nil, rpcErrorf(rpcAppError, "failed to load dataset: %v", err)
+			}
+		}
+		name := rpcDatasetName(p.Name)
+		s.datasets[name] = dataset
+		delete(s.miners, name)
+		items := make(map[string]bool)
+		for _, transaction := range dataset {
+			for _, item := range transaction {
+				items[item] = true
+			}
+		}
+		return map[string]interface{}{"name": name, "transactions": len(dataset), "items": len(items)}, nil
+
+	case "mine":
+		var p rpcMineParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if !(p.MinSupport > 0 && p.MinSupport <= 1) {
+			return nil, rpcErrorf(rpcInvalidParams, "minSupport must be in (0,1]")
+		}
+		name := rpcDatasetName(p.Name)
+		dataset, ok := s.datasets[name]
+		if !ok {
+			return nil, rpcErrorf(rpcAppError, "dataset %q is not loaded", name)
+		}
+		miner := NewAprioriMiner(dataset, p.MinSupport)
+		miner.SetMinLift(p.MinLift)
+		miner.SetMultiset(p.Multiset)
+		var levels []LevelProgress
+		miner.SetProgressFunc(func(l LevelProgress) { levels = append(levels, l) })
+		miner.Mine()
+		s.miners[name] = miner
+		return map[string]interface{}{"name": name, "itemsets": miner.getTotalFrequentItemsets(), "levels": levels}, nil
+
+	case "query":
+		var p rpcQueryParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		miner, err := s.miner(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]ItemsetResult, 0)
+		for _, r := range miner.Results() {
+			if (p.Size > 0 && r.Size != p.Size) || r.Support < p.MinSupport || !containsAll(r.Items, p.Contains) {
+				continue
+			}
+			if p.Limit > 0 && len(out) == p.Limit {
+				break
+			}
+			out = append(out, r)
+		}
+		if err := p.apply(s.datasets[rpcDatasetName(p.Name)], out, nil); err != nil {
+			return nil, err
+		}
+		return out, nil
+
+	case "rules":
+		var p rpcRulesParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		miner, err := s.miner(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		minConfidence := 0.6
+		if p.MinConfidence != nil {
+			minConfidence = *p.MinConfidence
+		}
+		rules := miner.GenerateRules(minConfidence)
+		if p.PAdjust != "" {
+			if err := AdjustPValues(rules, p.PAdjust); err != nil {
+				return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+			}
+		}
+		if p.MinImprovement != nil {
+			rules = miner.FilterByImprovement(rules, *p.MinImprovement)
+		}
+		out := make([]Rule, 0)
+		for _, rule := range rules {
+			if rule.Lift < p.MinLift || (p.MaxPValue != nil && rule.PValue > *p.MaxPValue) ||
+				(p.MaxAdjustedPValue != nil && rule.AdjustedPValue > *p.MaxAdjustedPValue) {
+				continue
+			}
+			if p.Limit > 0 && len(out) == p.Limit {
+				break
+			}
+			out = append(out, rule)
+		}
+		if err := p.apply(s.datasets[rpcDatasetName(p.Name)], nil, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	return nil, rpcErrorf(rpcMethodNotFound, "unknown method %q", method)
+}
+
+// containsAll reports whether every item of want appears in the sorted items
+func containsAll(items, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, item := range items {
+			if item == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// handle processes one request, returning nil for notifications
+func (s *rpcSession) handle(raw json.RawMessage) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpcErrorf(rpcInvalidRequest, "invalid request")}
+	}
+	result, err := s.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	resp := &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		rerr, ok := err.(*rpcError)
+		if !ok {
+			rerr = rpcErrorf(rpcAppError, "%v", err)
+		}
+		resp.Result, resp.Error = nil, rerr
+	}
+	return resp
+}
+
+// serveRPC reads newline-delimited JSON-RPC 2.0 requests or batches from r
+// and writes one response line per request or batch to w
+func serveRPC(r io.Reader, w io.Writer) error {
+	session := &rpcSession{datasets: make(map[string]Dataset), miners: make(map[string]*AprioriMiner)}
+	reader := bufio.NewReader(r)
+	enc := json.NewEncoder(w)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var out interface{}
+			trimmed := bytes.TrimSpace(line)
+			switch {
+			case !json.Valid(trimmed):
+				out = &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpcErrorf(rpcParseError, "parse error")}
+			case trimmed[0] == '[':
+				var batch []json.RawMessage
+				json.Unmarshal(trimmed, &batch)
+				responses := make([]*rpcResponse, 0, len(batch))
+				for _, raw := range batch {
+					if resp := session.handle(raw); resp != nil {
+						responses = append(responses, resp)
+					}
+				}
+				if len(batch) == 0 {
+					out = &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpcErrorf(rpcInvalidRequest, "empty batch")}
+				} else if len(responses) > 0 {
+					out = responses
+				}
+			default:
+				if resp := session.handle(trimmed); resp != nil {
+					out = resp
+				}
+			}
+			if out != nil {
+				if err := enc.Encode(out); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// runRPC drives the miner over JSON-RPC 2.0 on stdin and stdout, one message
+// per line, for language bindings. Methods:
+//
+//	version                                              -> {protocol}
+//	load  {name?, path | transactions}                   -> {name, transactions, items}
+//	mine  {name?, minSupport, minLift?, multiset?}       -> {name, itemsets, levels}
+//	query {name?, contains?, size?, minSupport?, limit?,
+//	       bootstrap?, bootstrapLevel?, bootstrapSeed?}  -> [itemset]
+//	rules {name?, minConfidence?, minLift?, maxPValue?,
+//	       pAdjust?, maxAdjustedPValue?, minImprovement?,
+//	       limit?, bootstrap?, bootstrapLevel?,
+//	       bootstrapSeed?}                               -> [rule]
+//
+// Itemsets and rules use the JSON layout of the server API.
+func runRPC(args []string) error {
+	fs := flag.NewFlagSet("rpc", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: rpc (requests are read from stdin)")
+	}
+	return serveRPC(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,170 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// missingSubset returns a subset of an itemset absent from results, or nil
+// when every subset needed to derive its rules is present
+func missingSubset(results []ItemsetResult) []string {
+	present := make(map[string]bool, len(results))
+	for _, r := range results {
+		present[strings.Join(r.Items, "\x00")] = true
+	}
+	for _, r := range results {
+		if r.Size < 2 {
+			continue
+		}
+		for skip := range r.Items {
+			subset := make([]string, 0, len(r.Items)-1)
+			subset = append(subset, r.Items[:skip]...)
+			subset = append(subset, r.Items[skip+1:]...)
+			if !present[strings.Join(subset, "\x00")] {
+				return subset
+			}
+		}
+	}
+	return nil
+}
+
+// transactionsFromManifest returns the transaction count recorded in the
+// manifest next to a summary file, or 0 when there is none
+func transactionsFromManifest(summaryPath string) int {
+	base := strings.TrimSuffix(filepath.Base(summaryPath), "_summary.csv")
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(summaryPath), base+manifestSuffix))
+	if err != nil {
+		return 0
+	}
+	var m runManifest
+	if json.Unmarshal(data, &m) != nil {
+		return 0
+	}
+	return m.Transactions
+}
+
+// loadSavedItemsets reads a summary file into a miner that can derive rules
+// from it. The number of transactions comes from the run manifest unless
+// given.
+func loadSavedItemsets(path string, transactions int) (*AprioriMiner, []ItemsetResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := ReadResultsCSV(f)
+	f.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if subset := missingSubset(results); subset != nil {
+		return nil, nil, fmt.Errorf("%s lacks the itemset {%s}; rules need every subset of an itemset", path, strings.Join(subset, ","))
+	}
+	n := transactions
+	if n == 0 {
+		n = transactionsFromManifest(path)
+	}
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("the number of transactions is unknown: pass -transactions")
+	}
+
+	// Summaries written before the Count column only record supports, whose
+	// counts are estimated from the number of transactions
+	minSupport := 1.0
+	for i := range results {
+		if results[i].Count == 0 {
+			results[i].Count = int(math.Round(results[i].Support * float64(n)))
+		}
+		minSupport = math.Min(minSupport, results[i].Support)
+	}
+	miner := minerFromResults(results, minSupport)
+	miner.transactionLen = n
+	return miner, results, nil
+}
+
+// runRules implements the rules subcommand, which derives association rules
+// from a saved itemset result without mining the dataset again
+func runRules(args []string) error {
+	fs := flag.NewFlagSet("rules", flag.ExitOnError)
+	transactions := fs.Int("transactions", 0, "transactions of the mined dataset (default: from the run manifest)")
+	minConfidence := fs.Float64("minconfidence", 0.6, "minimum confidence")
+	minLift := fs.Float64("min-lift", 0, "minimum lift")
+	pAdjust := fs.String("p-adjust", AdjustBenjaminiHochberg, "correction of the Fisher p-values across rules: bh or bonferroni")
+	maxPValue := fs.Float64("max-pvalue", 1, "drop rules whose chi-square p-value exceeds this")
+	maxAdjustedPValue := fs.Float64("max-adjusted-pvalue", 1, "drop rules whose adjusted Fisher p-value exceeds this")
+	minImprovement := fs.Float64("min-improvement", 0, "drop rules whose confidence beats every sub-rule's by less than this (0 disables)")
+	where := fs.String("where", "", "keep only rules matching this expression, e.g. \"lift > 1.5 && consequent('beer')\"")
+	rankBy := fs.String("rank-by", RankConfidence, "order of the rules: confidence, lift, leverage or normalized-leverage")
+	measureDocs := fs.Bool("measure-docs", false, "start the rules file with comment lines giving the formula of each measure")
+	output := fs.String("output", "", "rules file (default: <dataset>_rules.csv next to the itemsets)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: rules [flags] <dataset>_summary.csv")
+	}
+	if err := AdjustPValues(nil, *pAdjust); err != nil {
+		return err
+	}
+	if err := SortRulesBy(nil, *rankBy); err != nil {
+		return err
+	}
+	var filter *Filter
+	if *where != "" {
+		var err error
+		if filter, err = ParseFilter(*where); err != nil {
+			return err
+		}
+	}
+
+	path := fs.Arg(0)
+	miner, results, err := loadSavedItemsets(path, *transactions)
+	if err != nil {
+		return err
+	}
+	rules := miner.GenerateRules(*minConfidence)
+	kept := rules[:0]
+	for _, rule := range rules {
+		if rule.Lift >= *minLift {
+			kept = append(kept, rule)
+		}
+	}
+	rules = kept
+	AdjustPValues(rules, *pAdjust)
+	rules = FilterSignificantRules(rules, *maxPValue, *maxAdjustedPValue)
+	if *minImprovement > 0 {
+		rules = miner.FilterByImprovement(rules, *minImprovement)
+	}
+	if filter != nil {
+		if rules, err = FilterRules(filter, rules); err != nil {
+			return err
+		}
+	}
+	SortRulesBy(rules, *rankBy)
+
+	if *output == "" {
+		*output = strings.TrimSuffix(path, "_summary.csv") + "_rules.csv"
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if *measureDocs {
+		if err := writeMeasureDocs(out, ruleColumns); err != nil {
+			return err
+		}
+	}
+	if err := WriteRulesCSV(out, rules); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Derived %d rules from %d itemsets; written to %s\n", len(rules), len(results), *output)
+	return nil
+}
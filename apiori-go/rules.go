@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule is an association rule Antecedent -> Consequent derived from a frequent itemset
+type Rule struct {
+	Antecedent []string `json:"antecedent"`
+	Consequent []string `json:"consequent"`
+	// Count is the number of transactions containing both sides
+	Count      int     `json:"count"`
+	Support    float64 `json:"support"`
+	Confidence float64 `json:"confidence"`
+	Lift       float64 `json:"lift"`
+	// ChiSquare and PValue test the rule against independence of its sides
+	ChiSquare float64 `json:"chiSquare"`
+	PValue    float64 `json:"pValue"`
+	// FisherPValue is the exact one-sided test of a positive association;
+	// AdjustedPValue corrects it for the number of rules generated
+	FisherPValue   float64 `json:"fisherPValue"`
+	AdjustedPValue float64 `json:"adjustedPValue"`
+	// Null-invariant measures of the antecedent and consequent occurring together
+	AllConfidence float64 `json:"allConfidence"`
+	Kulczynski    float64 `json:"kulczynski"`
+	Cosine        float64 `json:"cosine"`
+	// Leverage is the support beyond independence of the two sides;
+	// NormalizedLeverage scales it into [-1,1]
+	Leverage           float64 `json:"leverage"`
+	NormalizedLeverage float64 `json:"normalizedLeverage"`
+	// SupportCI and ConfidenceCI are set when bootstrap intervals were requested
+	SupportCI    *Interval `json:"supportCI,omitempty"`
+	ConfidenceCI *Interval `json:"confidenceCI,omitempty"`
+}
+
+// GenerateRules derives all association rules meeting minConfidence from the
+// frequent itemsets found by Mine. Every split of a frequent itemset into a
+// non-empty antecedent and consequent is considered. Adjusted p-values use
+// Benjamini-Hochberg across the returned rules; see AdjustPValues.
+func (am *AprioriMiner) GenerateRules(minConfidence float64) []Rule {
+	rules := make([]Rule, 0)
+	for k, itemsets := range am.frequentSets {
+		if k < 2 {
+			continue
+		}
+		for _, itemset := range itemsets {
+			rules = append(rules, am.rulesFromItemset(sortedItems(itemset), minConfidence)...)
+		}
+	}
+	AdjustPValues(rules, AdjustBenjaminiHochberg)
+	sortRules(rules)
+	return rules
+}
+
+// rulesFromItemset enumerates the antecedent/consequent splits of items
+func (am *AprioriMiner) rulesFromItemset(items []string, minConfidence float64) []Rule {
+	rules := make([]Rule, 0)
+	total := float64(am.transactionLen)
+	count := am.countSupport(toItemSet(items))
+	support := float64(count) / total
+
+	// Each bitmask selects the antecedent; the remaining items form the consequent
+	for mask := 1; mask < (1<<len(items))-1; mask++ {
+		antecedent := make([]string, 0, len(items))
+		consequent := make([]string, 0, len(items))
+		for i, item := range items {
+			if mask&(1<<i) != 0 {
+				antecedent = append(antecedent, item)
+			} else {
+				consequent = append(consequent, item)
+			}
+		}
+		antecedentCount := am.countSupport(toItemSet(antecedent))
+		consequentCount := am.countSupport(toItemSet(consequent))
+		// Suppressed or noised counts can leave a side held by no
+		// transaction, whose measures are undefined
+		if antecedentCount == 0 || consequentCount == 0 {
+			continue
+		}
+		confidence := support / (float64(antecedentCount) / total)
+		if confidence < minConfidence {
+			continue
+		}
+		table := contingency{n: am.transactionLen, x: antecedentCount, y: consequentCount, xy: count}
+		chiSquare, pValue := table.chiSquare()
+		antecedentSupport := float64(antecedentCount) / total
+		consequentSupport := float64(consequentCount) / total
+		allConfidence, kulczynski, cosine := nullInvariantMeasures(support,
+			[]float64{antecedentSupport, consequentSupport})
+		leverage, normalizedLeverage := leverageMeasures(support, antecedentSupport, consequentSupport)
+		rules = append(rules, Rule{
+			Antecedent:         antecedent,
+			Consequent:         consequent,
+			Count:              count,
+			Support:            support,
+			Confidence:         confidence,
+			Lift:               confidence / consequentSupport,
+			ChiSquare:          chiSquare,
+			PValue:             pValue,
+			FisherPValue:       table.fisherExact(),
+			AllConfidence:      allConfidence,
+			Kulczynski:         kulczynski,
+			Cosine:             cosine,
+			Leverage:           leverage,
+			NormalizedLeverage: normalizedLeverage,
+		})
+	}
+	return rules
+}
+
+// FilterSignificantRules keeps the rules whose chi-square p-value is at most
+// maxPValue and whose adjusted Fisher p-value is at most maxAdjustedPValue
+func FilterSignificantRules(rules []Rule, maxPValue, maxAdjustedPValue float64) []Rule {
+	out := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.PValue <= maxPValue && rule.AdjustedPValue <= maxAdjustedPValue {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// FilterByImprovement keeps the rules whose confidence exceeds that of every
+// proper sub-rule with the same consequent by at least minImprovement. The
+// empty antecedent counts as a sub-rule, so a kept rule also beats the base
+// rate of its consequent. This drops rules that only extend a simpler rule
+// with items that do not change its confidence.
+func (am *AprioriMiner) FilterByImprovement(rules []Rule, minImprovement float64) []Rule {
+	out := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if am.improvement(rule) >= minImprovement {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// improvement is the smallest gain in confidence of rule over its proper
+// sub-rules. Every subset of a frequent itemset is frequent, so all needed
+// counts are known to the miner.
+func (am *AprioriMiner) improvement(rule Rule) float64 {
+	consequent := toItemSet(rule.Consequent)
+	improvement := rule.Confidence - float64(am.countSupport(consequent))/float64(am.transactionLen)
+	n := len(rule.Antecedent)
+	for mask := 1; mask < (1<<n)-1; mask++ {
+		sub := make(ItemSet, n+len(rule.Consequent))
+		for i, item := range rule.Antecedent {
+			if mask&(1<<i) != 0 {
+				sub[item] = true
+			}
+		}
+		antecedentCount := am.countSupport(sub)
+		for item := range consequent {
+			sub[item] = true
+		}
+		confidence := float64(am.countSupport(sub)) / float64(antecedentCount)
+		improvement = math.Min(improvement, rule.Confidence-confidence)
+	}
+	return improvement
+}
+
+// sortRules orders rules by descending confidence and support, then by items
+func sortRules(rules []Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Confidence != rules[j].Confidence {
+			return rules[i].Confidence > rules[j].Confidence
+		}
+		if rules[i].Support != rules[j].Support {
+			return rules[i].Support > rules[j].Support
+		}
+		return rules[i].String() < rules[j].String()
+	})
+}
+
+// Measures rules can be ranked by
+const (
+	RankConfidence         = "confidence"
+	RankLift               = "lift"
+	RankLeverage           = "leverage"
+	RankNormalizedLeverage = "normalized-leverage"
+)
+
+// SortRulesBy orders rules by descending measure, breaking ties like
+// sortRules
+func SortRulesBy(rules []Rule, measure string) error {
+	var value func(Rule) float64
+	switch measure {
+	case RankConfidence:
+		sortRules(rules)
+		return nil
+	case RankLift:
+		value = func(r Rule) float64 { return r.Lift }
+	case RankLeverage:
+		value = func(r Rule) float64 { return r.Leverage }
+	case RankNormalizedLeverage:
+		value = func(r Rule) float64 { return r.NormalizedLeverage }
+	default:
+		return fmt.Errorf("rank-by must be %s, %s, %s or %s", RankConfidence, RankLift, RankLeverage, RankNormalizedLeverage)
+	}
+	sortRules(rules)
+	sort.SliceStable(rules, func(i, j int) bool { return value(rules[i]) > value(rules[j]) })
+	return nil
+}
+
+// String formats the rule as "a,b -> c"
+func (r Rule) String() string {
+	return strings.Join(r.Antecedent, ",") + " -> " + strings.Join(r.Consequent, ",")
+}
+
+// ruleColumns are the columns written by WriteRulesCSV
+var ruleColumns = []string{"Antecedent", "Consequent", "Count", "Support", "Confidence", "Lift", "ChiSquare", "PValue",
+	"FisherPValue", "AdjustedPValue", "AllConfidence", "Kulczynski", "Cosine", "Leverage", "NormalizedLeverage"}
+
+// WriteRulesCSV writes rules with their measures, one per line
+func WriteRulesCSV(w io.Writer, rules []Rule) error {
+	if _, err := io.WriteString(w, strings.Join(ruleColumns, ",")+"\n"); err != nil {
+		return err
+	}
+	for _, r := range rules {
+		_, err := fmt.Fprintf(w, "\"%s\",\"%s\",%d,%f,%f,%f,%f,%g,%g,%g,%f,%f,%f,%f,%f\n",
+			strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","), r.Count, r.Support, r.Confidence, r.Lift,
+			r.ChiSquare, r.PValue, r.FisherPValue, r.AdjustedPValue, r.AllConfidence, r.Kulczynski, r.Cosine,
+			r.Leverage, r.NormalizedLeverage)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRulesCSV reads rules written by WriteRulesCSV. Only the Antecedent,
+// Consequent and Confidence columns are required; measures missing from the
+// file are left zero.
+func ReadRulesCSV(r io.Reader) ([]Rule, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	// Skips the measure documentation written by -measure-docs
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"Antecedent", "Consequent", "Confidence"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("rules file has no %s column", name)
+		}
+	}
+
+	var rules []Rule
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(name string) (float64, error) {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return 0, nil
+			}
+			return strconv.ParseFloat(record[i], 64)
+		}
+		if len(record) <= max(columns["Antecedent"], columns["Consequent"], columns["Confidence"]) {
+			return nil, fmt.Errorf("line %d: missing columns", line)
+		}
+		rule := Rule{
+			Antecedent: strings.Split(record[columns["Antecedent"]], ","),
+			Consequent: strings.Split(record[columns["Consequent"]], ","),
+		}
+		if rule.Confidence, err = value("Confidence"); err != nil {
+			return nil, fmt.Errorf("line %d: invalid confidence: %v", line, err)
+		}
+		if i, ok := columns["Count"]; ok && i < len(record) {
+			if rule.Count, err = strconv.Atoi(record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid count: %v", line, err)
+			}
+		}
+		for name, field := range map[string]*float64{
+			"Support": &rule.Support, "Lift": &rule.Lift, "ChiSquare": &rule.ChiSquare, "PValue": &rule.PValue,
+			"FisherPValue": &rule.FisherPValue, "AdjustedPValue": &rule.AdjustedPValue,
+			"AllConfidence": &rule.AllConfidence, "Kulczynski": &rule.Kulczynski, "Cosine": &rule.Cosine,
+			"Leverage": &rule.Leverage, "NormalizedLeverage": &rule.NormalizedLeverage,
+		} {
+			if *field, err = value(name); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %v", line, name, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// toItemSet converts a list of items into an ItemSet
+func toItemSet(items []string) ItemSet {
+	set := make(ItemSet, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
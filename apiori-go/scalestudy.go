@@ -0,0 +1,407 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// scalePoint is the cost of one algorithm on one dataset size
+type scalePoint struct {
+	Algorithm    string
+	Transactions int
+	Seconds      float64
+	// AllocatedBytes is the memory the run allocated in total and
+	// PeakHeapBytes the largest live heap above the one it started with
+	AllocatedBytes uint64
+	PeakHeapBytes  uint64
+	Itemsets       int
+}
+
+// scaleFit is the power law cost = Coefficient * n^Exponent fitted to one
+// measure of an algorithm by least squares on the log-log curve
+type scaleFit struct {
+	Algorithm   string
+	Measure     string
+	Exponent    float64
+	Coefficient float64
+	R2          float64
+}
+
+// growth names the complexity class the exponent is closest to
+func (f scaleFit) growth() string {
+	switch {
+	case f.Exponent < 0.85:
+		return "sublinear"
+	case f.Exponent <= 1.15:
+		return "linear"
+	case f.Exponent <= 1.85:
+		return "superlinear"
+	case f.Exponent <= 2.15:
+		return "quadratic"
+	}
+	return "superquadratic"
+}
+
+// parseSizes parses a comma-separated list of dataset sizes and returns them
+// in ascending order
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, part := range parseParamList(s) {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid dataset size %q: must be a positive integer", part)
+		}
+		sizes = append(sizes, n)
+	}
+	sort.Ints(sizes)
+	return sizes, nil
+}
+
+// doublingSizes returns steps sizes doubling up to largest
+func doublingSizes(largest, steps int) []int {
+	var sizes []int
+	for i := steps - 1; i >= 0; i-- {
+		if n := largest >> i; n > 0 && (len(sizes) == 0 || n > sizes[len(sizes)-1]) {
+			sizes = append(sizes, n)
+		}
+	}
+	return sizes
+}
+
+// generateTransactions draws n synthetic transactions in the manner of the
+// IBM Quest generator: a pool of patterns with exponentially distributed
+// weights is drawn over the items, and each transaction joins weighted
+// patterns, dropping a tenth of their items, until it reaches a length
+// around avgLength
+func generateTransactions(rng *rand.Rand, n, items, avgLength, patterns int) Dataset {
+	names := make([]string, items)
+	for i := range names {
+		names[i] = "i" + strconv.Itoa(i+1)
+	}
+	pool := make([][]string, patterns)
+	weights := make([]float64, patterns)
+	total := 0.0
+	for p := range pool {
+		size := min(items, 2+rng.Intn(max(1, avgLength/2)))
+		for _, i := range rng.Perm(items)[:size] {
+			pool[p] = append(pool[p], names[i])
+		}
+		weights[p] = rng.ExpFloat64()
+		total += weights[p]
+	}
+	cumulative := make([]float64, patterns)
+	acc := 0.0
+	for p, w := range weights {
+		acc += w / total
+		cumulative[p] = acc
+	}
+
+	dataset := make(Dataset, n)
+	for t := range dataset {
+		length := max(1, int(math.Round(float64(avgLength)+rng.NormFloat64()*float64(avgLength)/4)))
+		seen := make(map[string]bool, length)
+		var transaction []string
+		for attempts := 0; len(transaction) < length && attempts < 4*length; attempts++ {
+			p := sort.SearchFloat64s(cumulative, rng.Float64())
+			for _, item := range pool[min(p, patterns-1)] {
+				if !seen[item] && rng.Float64() >= 0.1 {
+					seen[item] = true
+					transaction = append(transaction, item)
+				}
+			}
+		}
+		sort.Strings(transaction)
+		dataset[t] = transaction
+	}
+	return dataset
+}
+
+// sampleSizes returns nested samples of the dataset, one per size: prefixes
+// of a shuffled copy, topped up by draws with replacement beyond its length
+func sampleSizes(rng *rand.Rand, dataset Dataset, sizes []int) []Dataset {
+	largest := sizes[len(sizes)-1]
+	shuffled := make(Dataset, 0, max(largest, len(dataset)))
+	for _, i := range rng.Perm(len(dataset)) {
+		shuffled = append(shuffled, dataset[i])
+	}
+	for len(shuffled) < largest {
+		shuffled = append(shuffled, dataset[rng.Intn(len(dataset))])
+	}
+	samples := make([]Dataset, len(sizes))
+	for i, n := range sizes {
+		samples[i] = shuffled[:n]
+	}
+	return samples
+}
+
+// measureRun times mine on the dataset, sampling the live heap while it runs
+func measureRun(mine MiningAlgorithm, dataset Dataset, minSupport float64) (seconds float64, allocated, peak uint64, itemsets int) {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	baseHeap, baseAlloc := stats.HeapAlloc, stats.TotalAlloc
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			var s runtime.MemStats
+			runtime.ReadMemStats(&s)
+			if s.HeapAlloc > baseHeap {
+				peak = max(peak, s.HeapAlloc-baseHeap)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	start := time.Now()
+	results := mine(dataset, minSupport)
+	seconds = time.Since(start).Seconds()
+	close(done)
+	wg.Wait()
+
+	runtime.ReadMemStats(&stats)
+	return seconds, stats.TotalAlloc - baseAlloc, peak, len(results)
+}
+
+// fitPowerLaw fits y = c * x^b by least squares on log y against log x,
+// ignoring points where either is not positive
+func fitPowerLaw(xs, ys []float64) (b, c, r2 float64, ok bool) {
+	var lx, ly []float64
+	for i := range xs {
+		if xs[i] > 0 && ys[i] > 0 {
+			lx = append(lx, math.Log(xs[i]))
+			ly = append(ly, math.Log(ys[i]))
+		}
+	}
+	if len(lx) < 2 {
+		return 0, 0, 0, false
+	}
+	meanX, meanY := 0.0, 0.0
+	for i := range lx {
+		meanX += lx[i] / float64(len(lx))
+		meanY += ly[i] / float64(len(ly))
+	}
+	sxx, sxy, syy := 0.0, 0.0, 0.0
+	for i := range lx {
+		sxx += (lx[i] - meanX) * (lx[i] - meanX)
+		sxy += (lx[i] - meanX) * (ly[i] - meanY)
+		syy += (ly[i] - meanY) * (ly[i] - meanY)
+	}
+	if sxx == 0 {
+		return 0, 0, 0, false
+	}
+	b = sxy / sxx
+	c = math.Exp(meanY - b*meanX)
+	r2 = 1.0
+	if syy > 0 {
+		r2 = sxy * sxy / (sxx * syy)
+	}
+	return b, c, r2, true
+}
+
+// fitScaling fits the runtime and both memory measures of every algorithm
+func fitScaling(algorithms []string, points []scalePoint) []scaleFit {
+	var fits []scaleFit
+	for _, algorithm := range algorithms {
+		var sizes, seconds, allocated, peak []float64
+		for _, p := range points {
+			if p.Algorithm == algorithm {
+				sizes = append(sizes, float64(p.Transactions))
+				seconds = append(seconds, p.Seconds)
+				allocated = append(allocated, float64(p.AllocatedBytes))
+				peak = append(peak, float64(p.PeakHeapBytes))
+			}
+		}
+		for _, m := range []struct {
+			name   string
+			values []float64
+		}{{"Seconds", seconds}, {"AllocatedBytes", allocated}, {"PeakHeapBytes", peak}} {
+			if b, c, r2, ok := fitPowerLaw(sizes, m.values); ok {
+				fits = append(fits, scaleFit{Algorithm: algorithm, Measure: m.name, Exponent: b, Coefficient: c, R2: r2})
+			}
+		}
+	}
+	return fits
+}
+
+// writeScaleReports writes the measured curves, with the fitted runtime at
+// each size, and the fitted exponents to dir
+func writeScaleReports(dir, base string, minSupport float64, points []scalePoint, fits []scaleFit) error {
+	fitted := make(map[string]scaleFit)
+	for _, f := range fits {
+		if f.Measure == "Seconds" {
+			fitted[f.Algorithm] = f
+		}
+	}
+	f, err := os.Create(filepath.Join(dir, base+"_scale.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Algorithm,MinSupport,Transactions,Seconds,FittedSeconds,AllocatedBytes,PeakHeapBytes,Itemsets")
+	for _, p := range points {
+		fittedSeconds := ""
+		if fit, ok := fitted[p.Algorithm]; ok {
+			fittedSeconds = fmt.Sprintf("%f", fit.Coefficient*math.Pow(float64(p.Transactions), fit.Exponent))
+		}
+		fmt.Fprintf(f, "%s,%f,%d,%f,%s,%d,%d,%d\n", p.Algorithm, minSupport, p.Transactions, p.Seconds, fittedSeconds,
+			p.AllocatedBytes, p.PeakHeapBytes, p.Itemsets)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.Create(filepath.Join(dir, base+"_scale_fit.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Algorithm,Measure,Exponent,Coefficient,R2,Growth")
+	for _, fit := range fits {
+		fmt.Fprintf(f, "%s,%s,%f,%g,%f,%s\n", fit.Algorithm, fit.Measure, fit.Exponent, fit.Coefficient, fit.R2, fit.growth())
+	}
+	return f.Close()
+}
+
+// runScaleStudy implements the scale-study subcommand, which mines samples
+// of a dataset, or synthetic datasets, of increasing size at a fixed support
+// and fits how the runtime and memory of each algorithm grow with the size
+func runScaleStudy(args []string) error {
+	fs := flag.NewFlagSet("scale-study", flag.ExitOnError)
+	algorithms := fs.String("algorithms", "apriori,eclat", "comma-separated algorithms to measure")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support held fixed across the sizes")
+	sizes := fs.String("sizes", "", "comma-separated dataset sizes in transactions (default: -steps sizes doubling up to the dataset, or to -transactions)")
+	steps := fs.Int("steps", 5, "number of doubling sizes when -sizes is not given")
+	repeat := fs.Int("repeat", 1, "runs per algorithm and size, keeping the fastest")
+	generate := fs.Bool("generate", false, "mine synthetic datasets instead of samples of a dataset")
+	transactions := fs.Int("transactions", 100000, "largest synthetic dataset")
+	items := fs.Int("items", 100, "distinct items of the synthetic datasets")
+	avgLength := fs.Int("avg-length", 10, "average transaction length of the synthetic datasets")
+	patterns := fs.Int("patterns", 20, "patterns the synthetic transactions are built from")
+	seed := fs.Int64("seed", 1, "seed of the sampling and of the synthetic datasets")
+	keep := fs.Bool("keep-datasets", false, "also write each dataset mined to <dataset>_scale_<n>.txt")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_scale.csv and <dataset>_scale_fit.csv")
+	fs.Parse(args)
+	if *generate != (fs.NArg() == 0) || fs.NArg() > 1 {
+		return fmt.Errorf("usage: scale-study [flags] <dataset> or scale-study -generate [flags]")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+	if *repeat < 1 || *steps < 2 {
+		return fmt.Errorf("repeat must be at least 1 and steps at least 2")
+	}
+	if *generate && (*transactions < 1 || *items < 1 || *avgLength < 1 || *patterns < 1) {
+		return fmt.Errorf("transactions, items, avg-length and patterns must be at least 1")
+	}
+	names := parseParamList(*algorithms)
+	if len(names) == 0 {
+		return fmt.Errorf("algorithms must name at least one algorithm")
+	}
+	mines := make([]MiningAlgorithm, len(names))
+	for i, name := range names {
+		algorithm, err := lookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		mines[i] = algorithm
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	var dataset Dataset
+	base := "synthetic"
+	largest := *transactions
+	if !*generate {
+		filename := fs.Arg(0)
+		var err error
+		if dataset, err = LoadDataset(filename); err != nil {
+			return err
+		}
+		if len(dataset) == 0 {
+			return fmt.Errorf("%s holds no transactions", filename)
+		}
+		base = getOutputBasename(filename)
+		largest = len(dataset)
+	}
+	var sizeList []int
+	if *sizes != "" {
+		var err error
+		if sizeList, err = parseSizes(*sizes); err != nil {
+			return err
+		}
+	} else {
+		sizeList = doublingSizes(largest, *steps)
+	}
+	if len(sizeList) < 2 {
+		return fmt.Errorf("a scale study needs at least two distinct sizes")
+	}
+	var samples []Dataset
+	if *generate {
+		// Smaller datasets are prefixes of the largest, drawn from the same patterns
+		all := generateTransactions(rng, sizeList[len(sizeList)-1], *items, *avgLength, *patterns)
+		for _, n := range sizeList {
+			samples = append(samples, all[:n])
+		}
+	} else {
+		if sizeList[len(sizeList)-1] > len(dataset) {
+			fmt.Printf("Sizes beyond the %d transactions of the dataset are sampled with replacement\n", len(dataset))
+		}
+		samples = sampleSizes(rng, dataset, sizeList)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	var points []scalePoint
+	fmt.Printf("%-14s %12s %10s %12s %12s %9s\n", "algorithm", "transactions", "seconds", "allocated", "peak heap", "itemsets")
+	for s, sample := range samples {
+		if *keep {
+			if err := writeTransactions(filepath.Join(*outputDir, fmt.Sprintf("%s_scale_%d.txt", base, len(sample))), sample); err != nil {
+				return err
+			}
+		}
+		for i, mine := range mines {
+			p := scalePoint{Algorithm: names[i], Transactions: sizeList[s], Seconds: math.Inf(1)}
+			for r := 0; r < *repeat; r++ {
+				seconds, allocated, peak, itemsets := measureRun(mine, sample, *minSupport)
+				if seconds < p.Seconds {
+					p.Seconds, p.AllocatedBytes, p.PeakHeapBytes = seconds, allocated, peak
+				}
+				p.Itemsets = itemsets
+			}
+			points = append(points, p)
+			fmt.Printf("%-14s %12d %10.3f %12s %12s %9d\n", p.Algorithm, p.Transactions, p.Seconds,
+				formatBytes(int64(p.AllocatedBytes)), formatBytes(int64(p.PeakHeapBytes)), p.Itemsets)
+		}
+	}
+
+	fits := fitScaling(names, points)
+	fmt.Println("Fitted growth (cost ~ n^exponent):")
+	for _, f := range fits {
+		fmt.Printf("  %-14s %-15s n^%.2f  R2 %.3f  %s\n", f.Algorithm, f.Measure, f.Exponent, f.R2, f.growth())
+	}
+	if err := writeScaleReports(*outputDir, base, *minSupport, points, fits); err != nil {
+		return err
+	}
+	fmt.Printf("Scale study written to %s\n", filepath.Join(*outputDir, base+"_scale{,_fit}.csv"))
+	return nil
+}
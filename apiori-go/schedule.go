@@ -0,0 +1,204 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScheduleEntry configures one periodically re-mined dataset
+type ScheduleEntry struct {
+	Name       string  `json:"name"`
+	Dataset    string  `json:"dataset"`
+	MinSupport float64 `json:"minSupport"`
+	Cron       string  `json:"cron"`
+	// Keep is the number of result generations retained; older ones are removed
+	Keep int `json:"keep"`
+}
+
+// ScheduleConfig is the file format read by the schedule subcommand
+type ScheduleConfig struct {
+	ResultsDir string          `json:"resultsDir"`
+	Entries    []ScheduleEntry `json:"entries"`
+}
+
+// scheduledEntry pairs an entry with its parsed schedule and next run time
+type scheduledEntry struct {
+	ScheduleEntry
+	cron *cronSchedule
+	next time.Time
+}
+
+// loadScheduleConfig reads and validates a schedule configuration file
+func loadScheduleConfig(path string) (*ScheduleConfig, []*scheduledEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var config ScheduleConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse schedule config: %v", err)
+	}
+	if config.ResultsDir == "" {
+		config.ResultsDir = filepath.Join("results", "scheduled")
+	}
+	if len(config.Entries) == 0 {
+		return nil, nil, fmt.Errorf("schedule config has no entries")
+	}
+
+	entries := make([]*scheduledEntry, 0, len(config.Entries))
+	seen := make(map[string]bool)
+	for _, entry := range config.Entries {
+		if !datasetNamePattern.MatchString(entry.Name) || seen[entry.Name] {
+			return nil, nil, fmt.Errorf("entry names must be unique and use letters, digits, '.', '_' or '-': %q", entry.Name)
+		}
+		seen[entry.Name] = true
+		if !(entry.MinSupport > 0 && entry.MinSupport <= 1) {
+			return nil, nil, fmt.Errorf("entry %s: minSupport must be in (0,1]", entry.Name)
+		}
+		cron, err := parseCron(entry.Cron)
+		if err != nil {
+			return nil, nil, fmt.Errorf("entry %s: %v", entry.Name, err)
+		}
+		if entry.Keep < 1 {
+			entry.Keep = 7
+		}
+		entries = append(entries, &scheduledEntry{ScheduleEntry: entry, cron: cron})
+	}
+	return &config, entries, nil
+}
+
+// runScheduledEntry mines one entry into a new timestamped result generation
+// and removes generations beyond the retention limit
+func runScheduledEntry(ctx context.Context, resultsDir string, entry *scheduledEntry, now time.Time) (err error) {
+	ctx, span := tracer.Start(ctx, "scheduled_run", trace.WithAttributes(
+		attribute.String("apriori.entry", entry.Name),
+		attribute.String("apriori.dataset", entry.Dataset)))
+	defer func() {
+		endSpanError(span, err)
+		span.End()
+	}()
+
+	startTime := time.Now()
+	var dataset Dataset
+	err = traced(ctx, "load", func(context.Context) error {
+		var err error
+		dataset, err = LoadDataset(entry.Dataset)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load dataset: %v", err)
+	}
+	loadTime := time.Since(startTime)
+
+	processStart := time.Now()
+	miner := NewAprioriMiner(dataset, entry.MinSupport)
+	if err := miner.MineContext(ctx); err != nil {
+		return err
+	}
+	metrics := TimingMetrics{
+		DataLoadTime:   loadTime.Seconds(),
+		ProcessingTime: time.Since(processStart).Seconds(),
+		TotalTime:      time.Since(startTime).Seconds(),
+	}
+
+	entryDir := filepath.Join(resultsDir, entry.Name)
+	generation := now.UTC().Format("20060102T150405Z")
+	err = traced(ctx, "output", func(context.Context) error {
+		return miner.OutputResultsTo(filepath.Join(entryDir, generation), getOutputBasename(entry.Dataset), metrics)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(entryDir, "LATEST"), []byte(generation+"\n")); err != nil {
+		return err
+	}
+	return rotateGenerations(entryDir, entry.Keep)
+}
+
+// rotateGenerations deletes the oldest result generations in dir beyond keep
+func rotateGenerations(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	generations := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			generations = append(generations, e.Name())
+		}
+	}
+	// Generation names are UTC timestamps so lexical order is chronological
+	sort.Strings(generations)
+	for len(generations) > keep {
+		if err := os.RemoveAll(filepath.Join(dir, generations[0])); err != nil {
+			return err
+		}
+		generations = generations[1:]
+	}
+	return nil
+}
+
+// runSchedule implements the schedule subcommand
+func runSchedule(args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	configPath := fs.String("config", "schedule.json", "schedule configuration file")
+	runNow := fs.Bool("run-now", false, "mine every entry once at startup before following the schedule")
+	fs.Parse(args)
+
+	config, entries, err := loadScheduleConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	now := time.Now()
+	for _, entry := range entries {
+		if *runNow {
+			entry.next = now
+		} else {
+			entry.next = entry.cron.Next(now)
+		}
+		log.Printf("Scheduled %s (%s), next run at %s", entry.Name, entry.Cron, entry.next.Format(time.RFC3339))
+	}
+
+	for {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].next.Before(entries[j].next) })
+		entry := entries[0]
+		if entry.next.IsZero() {
+			return fmt.Errorf("entry %s never runs", entry.Name)
+		}
+		timer := time.NewTimer(time.Until(entry.next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("Scheduler stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		log.Printf("Re-mining %s", entry.Name)
+		runAt := time.Now()
+		if err := runScheduledEntry(ctx, config.ResultsDir, entry, runAt); err != nil {
+			log.Printf("scheduled run of %s failed: %v", entry.Name, err)
+		} else {
+			log.Printf("Finished %s in %.2f seconds", entry.Name, time.Since(runAt).Seconds())
+		}
+		entry.next = entry.cron.Next(time.Now())
+	}
+}
@@ -0,0 +1,184 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calendarPeriod is the transactions dated in one calendar month or quarter
+type calendarPeriod struct {
+	Label   string
+	Dataset Dataset
+}
+
+// SeasonalRule is a rule with its confidence or lift in each period; NaN
+// marks periods in which the antecedent never occurs
+type SeasonalRule struct {
+	Antecedent []string  `json:"antecedent"`
+	Consequent []string  `json:"consequent"`
+	Values     []float64 `json:"values"`
+	// Spread is the largest minus the smallest value across the periods
+	Spread float64 `json:"spread"`
+}
+
+// periodLabel names the calendar month ("2024-03") or quarter ("2024-Q1")
+// of t
+func periodLabel(t time.Time, unit string) string {
+	if unit == "quarter" {
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	}
+	return t.Format("2006-01")
+}
+
+// splitCalendar groups the transactions by calendar period in time order
+func splitCalendar(dataset Dataset, times []time.Time, unit string) []calendarPeriod {
+	byLabel := make(map[string]int)
+	var periods []calendarPeriod
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return times[order[a]].Before(times[order[b]]) })
+	for _, i := range order {
+		label := periodLabel(times[i].UTC(), unit)
+		idx, ok := byLabel[label]
+		if !ok {
+			idx = len(periods)
+			byLabel[label] = idx
+			periods = append(periods, calendarPeriod{Label: label})
+		}
+		periods[idx].Dataset = append(periods[idx].Dataset, dataset[i])
+	}
+	return periods
+}
+
+// compareSeasons mines every period and measures each rule found in any of
+// them in all periods, ordering the rules by descending spread
+func compareSeasons(periods []calendarPeriod, minSupport, minConfidence float64, measure string) []SeasonalRule {
+	miners := make([]*AprioriMiner, len(periods))
+	seen := make(map[string]bool)
+	var rules []Rule
+	for i, p := range periods {
+		miners[i] = NewAprioriMiner(p.Dataset, minSupport)
+		miners[i].Mine()
+		for _, rule := range miners[i].GenerateRules(minConfidence) {
+			if key := rule.String(); !seen[key] {
+				seen[key] = true
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	out := make([]SeasonalRule, 0, len(rules))
+	for _, rule := range rules {
+		sr := SeasonalRule{Antecedent: rule.Antecedent, Consequent: rule.Consequent, Values: make([]float64, len(periods))}
+		antecedent := toItemSet(rule.Antecedent)
+		consequent := toItemSet(rule.Consequent)
+		both := toItemSet(append(append([]string{}, rule.Antecedent...), rule.Consequent...))
+		low, high := math.Inf(1), math.Inf(-1)
+		for i, miner := range miners {
+			// Counts outside the frequent sets fall back to scanning the period
+			a := miner.calculateSupport(antecedent)
+			if a == 0 {
+				sr.Values[i] = math.NaN()
+				continue
+			}
+			v := miner.calculateSupport(both) / a
+			if measure == RankLift {
+				if c := miner.calculateSupport(consequent); c > 0 {
+					v /= c
+				}
+			}
+			sr.Values[i] = v
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+		if high >= low {
+			sr.Spread = high - low
+		}
+		out = append(out, sr)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Spread > out[j].Spread })
+	return out
+}
+
+// writeSeasonsCSV writes one line per rule with a column per period
+func writeSeasonsCSV(path string, periods []calendarPeriod, rules []SeasonalRule) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header := []string{"Antecedent", "Consequent"}
+	for _, p := range periods {
+		header = append(header, p.Label)
+	}
+	fmt.Fprintln(f, strings.Join(append(header, "Spread"), ","))
+	for _, r := range rules {
+		fmt.Fprintf(f, "\"%s\",\"%s\"", strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","))
+		for _, v := range r.Values {
+			if math.IsNaN(v) {
+				fmt.Fprint(f, ",")
+			} else {
+				fmt.Fprintf(f, ",%f", v)
+			}
+		}
+		fmt.Fprintf(f, ",%f\n", r.Spread)
+	}
+	return f.Close()
+}
+
+// runSeasons implements the seasons subcommand, which mines each calendar
+// period of a timestamped dataset and compares the rules across periods
+func runSeasons(args []string) error {
+	fs := flag.NewFlagSet("seasons", flag.ExitOnError)
+	by := fs.String("by", "month", "calendar period: month or quarter")
+	measure := fs.String("measure", RankConfidence, "value compared across periods: confidence or lift")
+	minSupport := fs.Float64("minsupport", 0.05, "minimum support within a period")
+	minConfidence := fs.Float64("minconfidence", 0.5, "minimum confidence within a period")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_seasons.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: seasons [flags] <timestamped dataset>")
+	}
+	if *by != "month" && *by != "quarter" {
+		return fmt.Errorf("by must be month or quarter")
+	}
+	if *measure != RankConfidence && *measure != RankLift {
+		return fmt.Errorf("measure must be %s or %s", RankConfidence, RankLift)
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	filename := fs.Arg(0)
+	dataset, times, err := LoadTimestampedDataset(filename)
+	if err != nil {
+		return err
+	}
+	periods := splitCalendar(dataset, times, *by)
+	if len(periods) < 2 {
+		return fmt.Errorf("%s spans a single %s; nothing to compare", filename, *by)
+	}
+	for _, p := range periods {
+		fmt.Printf("%s: %d transactions\n", p.Label, len(p.Dataset))
+	}
+	rules := compareSeasons(periods, *minSupport, *minConfidence, *measure)
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, getOutputBasename(filename)+"_seasons.csv")
+	if err := writeSeasonsCSV(path, periods, rules); err != nil {
+		return err
+	}
+	fmt.Printf("Compared %d rules across %d periods; written to %s\n", len(rules), len(periods), path)
+	return nil
+}
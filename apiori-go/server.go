@@ -0,0 +1,771 @@
+//go:build !js
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Job statuses reported by the server
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+	// JobInterrupted marks a job stopped by shutdown; its partial results are
+	// kept and it is queued again when the server restarts
+	JobInterrupted = "interrupted"
+)
+
+// errDraining is returned for new work once the server is shutting down
+var errDraining = errors.New("server is shutting down")
+
+var datasetNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// DatasetInfo describes a dataset registered with the server
+type DatasetInfo struct {
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	Bytes        int64     `json:"bytes"`
+	Transactions int       `json:"transactions"`
+	SHA256       string    `json:"sha256"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+// JobRequest holds the parameters of a mining job submitted to the server
+type JobRequest struct {
+	Dataset    string  `json:"dataset"`
+	MinSupport float64 `json:"minSupport"`
+	WebhookURL string  `json:"webhookUrl,omitempty"`
+
+	// submitter names the API key submitting the job, if any
+	submitter string
+}
+
+// Job tracks a single mining run started through the server
+type Job struct {
+	ID          string        `json:"id"`
+	Namespace   string        `json:"namespace"`
+	Dataset     string        `json:"dataset"`
+	MinSupport  float64       `json:"minSupport"`
+	Status      string        `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	SubmittedAt time.Time     `json:"submittedAt"`
+	StartedAt   *time.Time    `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time    `json:"finishedAt,omitempty"`
+	Metrics     TimingMetrics `json:"metrics"`
+	Itemsets    int           `json:"itemsets"`
+	Cached      bool          `json:"cached,omitempty"`
+	WebhookURL  string        `json:"webhookUrl,omitempty"`
+	SubmittedBy string        `json:"submittedBy,omitempty"`
+
+	results     []ItemsetResult
+	events      []JobEvent
+	subscribers []chan JobEvent
+}
+
+// Server exposes dataset registration and mining jobs over HTTP
+type Server struct {
+	dataDir string
+	// importRoot holds a directory per namespace of files that may be
+	// registered by path
+	importRoot string
+
+	mu         sync.Mutex
+	namespaces map[string]*Namespace
+	datasets   map[string]*DatasetInfo // keyed by datasetKey
+	jobs       map[string]*Job
+	pending    []*Job
+	wake       *sync.Cond
+	draining   bool
+
+	// defaultQuota applies to namespaces created without explicit limits
+	defaultQuota Quota
+	limits       Limits
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+
+	cache    *RedisCache
+	cacheTTL time.Duration
+
+	// publicURL prefixes result locations reported to webhooks
+	publicURL string
+}
+
+// NewServer creates a server storing its state below dataDir and running at
+// most maxConcurrent mining jobs at a time. Jobs left queued or running by a
+// previous process are resumed. New namespaces get defaultQuota.
+func NewServer(dataDir string, maxConcurrent int, defaultQuota Quota) (*Server, error) {
+	if maxConcurrent < 1 {
+		return nil, fmt.Errorf("max concurrent jobs must be at least 1")
+	}
+	for _, dir := range []string{"datasets", "results"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory: %v", err)
+		}
+	}
+	s := &Server{
+		dataDir:      dataDir,
+		importRoot:   filepath.Join(dataDir, "imports"),
+		namespaces:   make(map[string]*Namespace),
+		datasets:     make(map[string]*DatasetInfo),
+		jobs:         make(map[string]*Job),
+		defaultQuota: defaultQuota,
+	}
+	s.wake = sync.NewCond(&s.mu)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if err := s.loadState(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if _, ok := s.namespaces[DefaultNamespace]; !ok {
+		s.namespaces[DefaultNamespace] = &Namespace{Name: DefaultNamespace, Quota: defaultQuota, CreatedAt: time.Now().UTC()}
+	}
+	s.mu.Unlock()
+	for i := 0; i < maxConcurrent; i++ {
+		go s.worker()
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving the REST API. Dataset and job
+// routes exist both at the top level, which addresses the default namespace,
+// and below /namespaces/{ns}.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET /namespaces", s.handleListNamespaces)
+	mux.HandleFunc("GET /namespaces/{ns}", s.handleGetNamespace)
+	mux.HandleFunc("PUT /namespaces/{ns}", s.handlePutNamespace)
+	for _, prefix := range []string{"", "/namespaces/{ns}"} {
+		mux.HandleFunc("GET "+prefix+"/datasets", s.handleListDatasets)
+		mux.HandleFunc("POST "+prefix+"/datasets", s.handleRegisterDataset)
+		mux.HandleFunc("GET "+prefix+"/datasets/{name}", s.handleGetDataset)
+		mux.HandleFunc("PUT "+prefix+"/datasets/{name}", s.handleUploadDataset)
+		mux.HandleFunc("GET "+prefix+"/jobs", s.handleListJobs)
+		mux.HandleFunc("POST "+prefix+"/jobs", s.handleSubmitJob)
+		mux.HandleFunc("GET "+prefix+"/jobs/{id}", s.handleGetJob)
+		mux.HandleFunc("GET "+prefix+"/jobs/{id}/results", s.handleJobResults)
+		mux.HandleFunc("GET "+prefix+"/jobs/{id}/events", s.handleJobEvents)
+	}
+	return mux
+}
+
+// handleListDatasets lists all datasets registered in a namespace
+func (s *Server) handleListDatasets(w http.ResponseWriter, r *http.Request) {
+	list, err := s.listDatasets(namespaceFromRequest(r))
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// listDatasets returns copies of the datasets in ns ordered by name
+func (s *Server) listDatasets(ns string) ([]DatasetInfo, error) {
+	s.mu.Lock()
+	if _, ok := s.namespaces[ns]; !ok {
+		s.mu.Unlock()
+		return nil, errNamespaceNotFound
+	}
+	list := make([]DatasetInfo, 0)
+	for _, info := range s.datasets {
+		if info.Namespace == ns {
+			list = append(list, *info)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// handleGetDataset returns a single registered dataset
+func (s *Server) handleGetDataset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	info, ok := s.datasets[datasetKey(namespaceFromRequest(r), r.PathValue("name"))]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleUploadDataset stores the request body as a dataset file
+func (s *Server) handleUploadDataset(w http.ResponseWriter, r *http.Request) {
+	if s.limits.MaxDatasetBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.limits.MaxDatasetBytes)
+	}
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("dataset exceeds the limit of %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	info, err := s.uploadDataset(namespaceFromRequest(r), r.PathValue("name"), body)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, info)
+}
+
+// uploadDataset writes content into the namespace's data directory and registers it
+func (s *Server) uploadDataset(ns, name string, content []byte) (*DatasetInfo, error) {
+	if !datasetNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid dataset name")
+	}
+	if err := s.checkDatasetSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	err := s.checkDatasetQuotaLocked(ns, name, int64(len(content)))
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(s.dataDir, "datasets", ns)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	// The dataset already stored under name stays in place until the new
+	// content passes the quota check
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to store dataset: %v", err)
+	}
+	return s.installDataset(ns, name, tmp.Name(), filepath.Join(dir, name+".txt"))
+}
+
+// handleRegisterDataset registers a dataset file already present below the
+// namespace's import root
+func (s *Server) handleRegisterDataset(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	info, err := s.importDataset(namespaceFromRequest(r), req.Name, req.Path)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, info)
+}
+
+// importDataset registers the file at path below the import root of ns.
+// Relative paths are taken from that root, and paths leaving it, also
+// through symlinks, are rejected so a client can only register files the
+// operator placed there for its namespace.
+func (s *Server) importDataset(ns, name, path string) (*DatasetInfo, error) {
+	s.mu.Lock()
+	_, ok := s.namespaces[ns]
+	s.mu.Unlock()
+	if !ok {
+		return nil, errNamespaceNotFound
+	}
+	root, err := filepath.Abs(filepath.Join(s.importRoot, ns))
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+	if !withinDir(root, path) {
+		return nil, fmt.Errorf("dataset path must be below the import root of namespace %s", ns)
+	}
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s has no import directory", ns)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset: %v", err)
+	}
+	if !withinDir(resolvedRoot, resolved) {
+		return nil, fmt.Errorf("dataset path must be below the import root of namespace %s", ns)
+	}
+	return s.registerDataset(ns, name, resolved)
+}
+
+// withinDir reports whether the clean path lies below dir
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// registerDataset validates that the file loads and records it under name in
+// ns. path is trusted; client-supplied paths go through importDataset.
+func (s *Server) registerDataset(ns, name, path string) (*DatasetInfo, error) {
+	return s.installDataset(ns, name, path, path)
+}
+
+// installDataset validates the file at src and records it under name in ns
+// at dst, renaming src to dst under the lock once the quota check passes. A
+// src other than dst is removed when a check fails, leaving the dataset
+// already at dst and its recorded size and hash intact.
+func (s *Server) installDataset(ns, name, src, dst string) (info *DatasetInfo, err error) {
+	if src != dst {
+		defer func() {
+			if err != nil {
+				os.Remove(src)
+			}
+		}()
+	}
+	if !datasetNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid dataset name")
+	}
+	stat, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset: %v", err)
+	}
+	if err := s.checkDatasetSize(stat.Size()); err != nil {
+		return nil, err
+	}
+	dataset, err := LoadDataset(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset: %v", err)
+	}
+	sum, err := fileSHA256(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash dataset: %v", err)
+	}
+	info = &DatasetInfo{
+		Namespace:    ns,
+		Name:         name,
+		Path:         dst,
+		Bytes:        stat.Size(),
+		Transactions: len(dataset),
+		SHA256:       sum,
+		RegisteredAt: time.Now().UTC(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkDatasetQuotaLocked(ns, name, info.Bytes); err != nil {
+		return nil, err
+	}
+	if src != dst {
+		if err := os.Rename(src, dst); err != nil {
+			return nil, fmt.Errorf("failed to store dataset: %v", err)
+		}
+	}
+	s.datasets[datasetKey(ns, name)] = info
+	if err := s.saveStateLocked(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// handleListJobs lists the jobs of a namespace ordered by submission time
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	list, err := s.listJobs(namespaceFromRequest(r))
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// listJobs returns snapshots of the jobs in ns ordered by submission time
+func (s *Server) listJobs(ns string) ([]Job, error) {
+	s.mu.Lock()
+	if _, ok := s.namespaces[ns]; !ok {
+		s.mu.Unlock()
+		return nil, errNamespaceNotFound
+	}
+	list := make([]Job, 0)
+	for _, job := range s.jobs {
+		if job.Namespace == ns {
+			list = append(list, *job)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].SubmittedAt.Before(list[j].SubmittedAt) })
+	return list, nil
+}
+
+// handleSubmitJob starts a mining job for a registered dataset
+func (s *Server) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if key := apiKeyFromContext(r.Context()); key != nil {
+		req.submitter = key.Name
+	}
+	job, err := s.submitJob(namespaceFromRequest(r), req)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// submitJob validates the request and queues the job for mining in ns
+func (s *Server) submitJob(ns string, req JobRequest) (Job, error) {
+	if !(req.MinSupport > 0 && req.MinSupport <= 1) {
+		return Job{}, fmt.Errorf("minSupport must be in (0,1]")
+	}
+	if req.WebhookURL != "" {
+		if err := validateWebhookURL(req.WebhookURL); err != nil {
+			return Job{}, err
+		}
+	}
+	s.mu.Lock()
+	if _, ok := s.namespaces[ns]; !ok {
+		s.mu.Unlock()
+		return Job{}, errNamespaceNotFound
+	}
+	info, ok := s.datasets[datasetKey(ns, req.Dataset)]
+	s.mu.Unlock()
+	if !ok {
+		return Job{}, fmt.Errorf("unknown dataset %q", req.Dataset)
+	}
+	// Look up the cache before taking the lock for good; Redis is remote
+	cached, hit := s.lookupCachedResult(*info, req.MinSupport)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		return Job{}, errDraining
+	}
+	if err := s.checkJobQuotaLocked(ns); err != nil {
+		return Job{}, err
+	}
+	if err := s.checkSubmitterLimitLocked(req.submitter); err != nil {
+		return Job{}, err
+	}
+	job := &Job{
+		ID:          newJobID(),
+		Namespace:   ns,
+		Dataset:     req.Dataset,
+		MinSupport:  req.MinSupport,
+		Status:      JobQueued,
+		SubmittedAt: time.Now().UTC(),
+		WebhookURL:  req.WebhookURL,
+		SubmittedBy: req.submitter,
+	}
+	if hit {
+		return s.completeFromCacheLocked(job, cached)
+	}
+	s.jobs[job.ID] = job
+	if err := s.saveStateLocked(); err != nil {
+		delete(s.jobs, job.ID)
+		return Job{}, err
+	}
+	s.enqueueLocked(job)
+	return *job, nil
+}
+
+// handleGetJob reports the status of a job
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.getJob(namespaceFromRequest(r), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// getJob returns a snapshot of the job with the given ID in ns
+func (s *Server) getJob(ns, id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || job.Namespace != ns {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// errJobNotFound is returned when a job ID is unknown
+var errJobNotFound = errors.New("job not found")
+
+// jobResults returns the itemsets of a finished job in ns
+func (s *Server) jobResults(ns, id string) ([]ItemsetResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || job.Namespace != ns {
+		return nil, errJobNotFound
+	}
+	if job.Status != JobDone && job.Status != JobInterrupted {
+		return nil, fmt.Errorf("job is %s", job.Status)
+	}
+	if job.results == nil {
+		results, err := s.loadJobResults(job.ID)
+		if err != nil {
+			return nil, err
+		}
+		job.results = results
+	}
+	return job.results, nil
+}
+
+// handleJobResults returns the frequent itemsets of a finished job as JSON or CSV
+func (s *Server) handleJobResults(w http.ResponseWriter, r *http.Request) {
+	results, err := s.jobResults(namespaceFromRequest(r), r.PathValue("id"))
+	if err == errJobNotFound {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		writeJSON(w, http.StatusOK, results)
+	case "csv":
+		var buf bytes.Buffer
+		if err := WriteResultsCSV(&buf, results); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write(buf.Bytes())
+	default:
+		writeError(w, http.StatusBadRequest, "format must be json or csv")
+	}
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 digest of the file at path
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newJobID returns a random identifier for a job
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeServerError maps errors returned by Server methods onto HTTP statuses
+func writeServerError(w http.ResponseWriter, err error) {
+	switch {
+	case err == errNamespaceNotFound || err == errJobNotFound:
+		writeError(w, http.StatusNotFound, err.Error())
+	case err == errDraining:
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case isQuotaError(err):
+		writeError(w, http.StatusForbidden, err.Error())
+	case isLimitError(err):
+		writeError(w, http.StatusTooManyRequests, err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// handleHealthz reports that the process is alive
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz reports whether the server accepts new jobs
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	draining := s.draining
+	s.mu.Unlock()
+	if draining {
+		writeError(w, http.StatusServiceUnavailable, errDraining.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// Shutdown stops accepting jobs, interrupts running mines at their next
+// level boundary and waits until they have saved their partial results
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.wake.Broadcast()
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runServe implements the serve subcommand
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	grpcAddr := fs.String("grpc-addr", "", "address for the gRPC API (disabled when empty)")
+	dataDir := fs.String("data-dir", "server_data", "directory for datasets, job state and results")
+	importRoot := fs.String("import-root", "", "directory holding a subdirectory per namespace of files that may be registered by path (default <data-dir>/imports)")
+	maxConcurrent := fs.Int("max-concurrent", 2, "maximum number of jobs mining at the same time")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 30*time.Second, "time allowed for running jobs to stop on shutdown")
+	redisAddr := fs.String("redis-addr", "", "Redis address for caching results (disabled when empty)")
+	redisPassword := fs.String("redis-password", "", "password for the Redis server")
+	redisDB := fs.Int("redis-db", 0, "Redis database number")
+	cacheTTL := fs.Duration("cache-ttl", 24*time.Hour, "how long cached results stay valid")
+	publicURL := fs.String("public-url", "", "externally reachable base URL used in webhook payloads (default http://localhost<addr>)")
+	var quota Quota
+	fs.IntVar(&quota.MaxDatasets, "default-max-datasets", 0, "default per-namespace dataset limit (0 = unlimited)")
+	fs.Int64Var(&quota.MaxStorageBytes, "default-max-storage", 0, "default per-namespace dataset storage limit in bytes (0 = unlimited)")
+	fs.IntVar(&quota.MaxActiveJobs, "default-max-active-jobs", 0, "default per-namespace limit of queued and running jobs (0 = unlimited)")
+	apiKeysPath := fs.String("api-keys", "", "JSON file of API keys; authentication is disabled when empty")
+	var limits Limits
+	fs.Int64Var(&limits.MaxDatasetBytes, "max-dataset-bytes", 1<<30, "largest dataset accepted in bytes (0 = unlimited)")
+	fs.IntVar(&limits.MaxJobsPerKey, "max-jobs-per-key", 0, "queued and running jobs allowed per API key (0 = unlimited)")
+	fs.Float64Var(&limits.RequestsPerSecond, "rate-limit", 0, "requests per second allowed per API key or client address (0 = unlimited)")
+	fs.IntVar(&limits.Burst, "rate-burst", 0, "requests allowed in a burst above the rate limit (default rate+1)")
+	fs.Parse(args)
+
+	var auth *apiKeyAuth
+	if *apiKeysPath != "" {
+		var err error
+		if auth, err = loadAPIKeys(*apiKeysPath); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("Warning: no -api-keys file given, the API is unauthenticated")
+	}
+
+	server, err := NewServer(*dataDir, *maxConcurrent, quota)
+	if err != nil {
+		return err
+	}
+	server.limits = limits
+	if *importRoot != "" {
+		server.importRoot = *importRoot
+	}
+	var limiter *rateLimiter
+	if limits.RequestsPerSecond > 0 {
+		limiter = newRateLimiter(limits.RequestsPerSecond, limits.Burst, auth)
+	}
+	server.publicURL = strings.TrimSuffix(*publicURL, "/")
+	if server.publicURL == "" {
+		server.publicURL = "http://localhost" + *addr
+	}
+	if *redisAddr != "" {
+		cache := NewRedisCache(*redisAddr, *redisPassword, *redisDB)
+		defer cache.Close()
+		server.SetCache(cache, *cacheTTL)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	errCh := make(chan error, 2)
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		grpcServer = newGRPCServer(server, auth, limiter)
+		go func() {
+			log.Printf("Serving Apriori gRPC API on %s", *grpcAddr)
+			errCh <- grpcServer.Serve(lis)
+		}()
+	}
+	handler := server.Handler()
+	if limiter != nil {
+		handler = limiter.Middleware(handler)
+	}
+	if auth != nil {
+		handler = auth.Middleware(handler)
+	}
+	httpServer := &http.Server{Addr: *addr, Handler: handler}
+	go func() {
+		log.Printf("Serving Apriori API on %s", *addr)
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down: waiting for running jobs to save partial results")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("running jobs did not stop in time: %v", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	return httpServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,44 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A replacement that fails the quota check under the lock, as when another
+// upload took the space after the early check, leaves the old dataset intact
+func TestFailedReplacementKeepsTheDataset(t *testing.T) {
+	s, err := NewServer(t.TempDir(), 1, Quota{MaxStorageBytes: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+	old, err := s.uploadDataset(DefaultNamespace, "d1", []byte("a b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.uploadDataset(DefaultNamespace, "d2", []byte("c d\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := filepath.Join(filepath.Dir(old.Path), "d1.replacement.tmp")
+	if err := os.WriteFile(tmp, []byte("a b c d\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.installDataset(DefaultNamespace, "d1", tmp, old.Path); !isQuotaError(err) {
+		t.Fatalf("installDataset returned %v, want a quota error", err)
+	}
+	if content, err := os.ReadFile(old.Path); err != nil || string(content) != "a b\n" {
+		t.Errorf("d1 now holds %q (%v), want the old content", content, err)
+	}
+	if sum, err := fileSHA256(old.Path); err != nil || sum != s.datasets[datasetKey(DefaultNamespace, "d1")].SHA256 {
+		t.Errorf("d1 no longer matches its recorded hash")
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("the rejected content was left at %s", tmp)
+	}
+}
@@ -0,0 +1,223 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event is a raw event row: a user acting on an item at a time
+type Event struct {
+	User string
+	Time time.Time
+	Item string
+}
+
+// Session is the distinct items of a user's consecutive events with no
+// inactivity gap between them, in order of first occurrence
+type Session struct {
+	User  string
+	Start time.Time
+	End   time.Time
+	Items []string
+}
+
+// LoadEvents reads delimited event rows, taking the user, timestamp and item
+// from the given 0-based columns. Timestamps are in any format of
+// timestamped datasets. With header set the first row is skipped.
+func LoadEvents(filename string, delimiter rune, header bool, userCol, timeCol, itemCol int) ([]Event, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var events []Event
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header && row == 1 {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) <= max(userCol, timeCol, itemCol) {
+			return nil, fmt.Errorf("%s:%d: expected at least %d columns", filename, row, max(userCol, timeCol, itemCol)+1)
+		}
+		t, err := parseTimestamp(strings.TrimSpace(record[timeCol]))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, row, err)
+		}
+		item := strings.Join(strings.Fields(record[itemCol]), "_")
+		if item == "" {
+			continue
+		}
+		events = append(events, Event{User: strings.TrimSpace(record[userCol]), Time: t, Item: item})
+	}
+	return events, nil
+}
+
+// sessionize groups the events of each user into sessions, starting a new one
+// whenever more than gap passes between consecutive events, and returns them
+// ordered by start time. The events need not be sorted.
+func sessionize(events []Event, gap time.Duration) []Session {
+	sorted := append([]Event{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].User != sorted[j].User {
+			return sorted[i].User < sorted[j].User
+		}
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	var sessions []Session
+	var seen map[string]bool
+	for i, e := range sorted {
+		current := len(sessions) - 1
+		if i == 0 || e.User != sorted[i-1].User || e.Time.Sub(sessions[current].End) > gap {
+			sessions = append(sessions, Session{User: e.User, Start: e.Time})
+			current++
+			seen = make(map[string]bool)
+		}
+		sessions[current].End = e.Time
+		if !seen[e.Item] {
+			seen[e.Item] = true
+			sessions[current].Items = append(sessions[current].Items, e.Item)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Start.Before(sessions[j].Start) })
+	return sessions
+}
+
+// writeSessions writes a transaction per session with at least minItems
+// items, led by the session's start time when timestamped so the file loads
+// as a timestamped dataset, and returns the number written
+func writeSessions(path string, sessions []Session, minItems int, timestamped bool) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	written := 0
+	for _, s := range sessions {
+		if len(s.Items) < minItems {
+			continue
+		}
+		if timestamped {
+			fmt.Fprintf(w, "%s ", s.Start.UTC().Format(time.RFC3339))
+		}
+		fmt.Fprintln(w, strings.Join(s.Items, " "))
+		written++
+	}
+	if err := w.Flush(); err != nil {
+		return written, err
+	}
+	return written, f.Close()
+}
+
+// runSessionize implements the sessionize subcommand, which turns raw event
+// rows, an access log or a clickstream into a dataset with a transaction per user session
+func runSessionize(args []string) error {
+	fs := flag.NewFlagSet("sessionize", flag.ExitOnError)
+	format := fs.String("format", "events", "input format: events, delimited user, timestamp and item rows; weblog, a Common or Combined Log Format access log; or clickstream, JSON Lines events")
+	gap := fs.Duration("gap", 30*time.Minute, "inactivity gap ending a session")
+	delimiter := fs.String("delimiter", ",", "column delimiter of the event file")
+	header := fs.Bool("header", false, "skip the first row of the event file")
+	userCol := fs.Int("user-col", 0, "0-based column of the user ID")
+	timeCol := fs.Int("time-col", 1, "0-based column of the timestamp")
+	itemCol := fs.Int("item-col", 2, "0-based column of the item")
+	minItems := fs.Int("min-items", 1, "fewest distinct items a session needs to be written")
+	timestamped := fs.Bool("timestamped", false, "lead every transaction with its session's start time")
+	keepQuery := fs.Bool("keep-query", false, "weblog: keep the query strings of URLs instead of stripping them")
+	keepParams := fs.String("keep-params", "", "weblog: comma-separated query parameters kept when stripping, e.g. id,page")
+	allStatuses := fs.Bool("all-statuses", false, "weblog: keep requests that failed with a 4xx or 5xx status")
+	byUserAgent := fs.Bool("by-user-agent", false, "weblog: tell apart visitors sharing an address by their user agent")
+	userField := fs.String("user-field", "user", "clickstream: field of the user ID; dotted names reach nested fields")
+	eventField := fs.String("event-field", "event", "clickstream: field of the event type")
+	itemField := fs.String("item-field", "item", "clickstream: field of the item")
+	timeField := fs.String("time-field", "ts", "clickstream: field of the timestamp")
+	eventTypes := fs.String("event-types", "", "clickstream: comma-separated event types kept, e.g. view,purchase (default all)")
+	tagEvent := fs.Bool("tag-event", false, "clickstream: prefix items with their event type, as in view:sku1")
+	output := fs.String("o", "", "dataset file written (default results/<events>_sessions.txt)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: sessionize [flags] <event file>")
+	}
+	if *gap <= 0 || *minItems < 1 {
+		return fmt.Errorf("gap must be positive and min-items at least 1")
+	}
+	if *userCol < 0 || *timeCol < 0 || *itemCol < 0 {
+		return fmt.Errorf("columns must be non-negative")
+	}
+	delim, err := parseDelimiter(*delimiter)
+	if err != nil {
+		return err
+	}
+
+	filename := fs.Arg(0)
+	var events []Event
+	switch *format {
+	case "events":
+		events, err = LoadEvents(filename, delim, *header, *userCol, *timeCol, *itemCol)
+	case "weblog":
+		opts := WebLogOptions{KeepQuery: *keepQuery, KeepParams: parseParamList(*keepParams),
+			AllStatuses: *allStatuses, ByUserAgent: *byUserAgent}
+		var skipped int
+		events, skipped, err = LoadWebLogEvents(filename, opts)
+		if skipped > 0 {
+			fmt.Printf("Skipped %d lines not in Common or Combined Log Format\n", skipped)
+		}
+	case "clickstream":
+		fields := ClickstreamFields{User: *userField, Event: *eventField, Item: *itemField, Time: *timeField, TagEvent: *tagEvent}
+		if *eventTypes != "" {
+			fields.EventTypes = make(map[string]bool)
+			for _, t := range parseParamList(*eventTypes) {
+				fields.EventTypes[t] = true
+			}
+		}
+		var skipped int
+		events, skipped, err = LoadClickstreamEvents(filename, fields)
+		if skipped > 0 {
+			fmt.Printf("Skipped %d events missing the user, item or timestamp\n", skipped)
+		}
+	default:
+		return fmt.Errorf("format must be events, weblog or clickstream")
+	}
+	if err != nil {
+		return err
+	}
+	sessions := sessionize(events, *gap)
+
+	path := *output
+	if path == "" {
+		if err := os.MkdirAll("results", 0755); err != nil {
+			return err
+		}
+		path = filepath.Join("results", getOutputBasename(filename)+"_sessions.txt")
+	}
+	written, err := writeSessions(path, sessions, *minItems, *timestamped)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Grouped %d events into %d sessions; wrote %d transactions to %s\n", len(events), len(sessions), written, path)
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// shardBoundaries splits a file of size bytes into up to shards byte ranges
+// starting at line boundaries, returned as the start offsets followed by size
+func shardBoundaries(file *os.File, size int64, shards int) ([]int64, error) {
+	bounds := []int64{0}
+	buf := make([]byte, 64*1024)
+	for i := 1; i < shards; i++ {
+		offset := max(size*int64(i)/int64(shards), bounds[len(bounds)-1])
+		// A shard starts after the first newline at or past offset-1, so a
+		// line beginning exactly at offset stays whole
+		start := size
+		for pos := max(offset-1, 0); pos < size; {
+			n, err := file.ReadAt(buf, pos)
+			if k := bytes.IndexByte(buf[:n], '\n'); k >= 0 {
+				start = pos + int64(k) + 1
+				break
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			pos += int64(n)
+		}
+		if start > bounds[len(bounds)-1] && start < size {
+			bounds = append(bounds, start)
+		}
+	}
+	return append(bounds, size), nil
+}
+
+// LoadDatasetParallel loads transactions as LoadDataset does, splitting the
+// file into shards byte ranges aligned to lines and parsing them
+// concurrently. The transactions keep the order of the file.
+func LoadDatasetParallel(filename string, shards int) (Dataset, error) {
+	if shards <= 1 {
+		return LoadDataset(filename)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := shardBoundaries(file, info.Size(), shards)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]Dataset, len(bounds)-1)
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	for i := range parts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := bufio.NewReader(io.NewSectionReader(file, bounds[i], bounds[i+1]-bounds[i]))
+			if i == 0 {
+				if start, _ := r.Peek(len(utf8BOM)); bytes.Equal(start, utf8BOM) {
+					r.Discard(len(utf8BOM))
+				}
+			}
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				parts[i] = append(parts[i], strings.Fields(scanner.Text()))
+			}
+			errs[i] = scanner.Err()
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for i, part := range parts {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		total += len(part)
+	}
+	dataset := make(Dataset, 0, total)
+	for _, part := range parts {
+		dataset = append(dataset, part...)
+	}
+	return dataset, nil
+}
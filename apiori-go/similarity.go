@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Item similarity measures
+const (
+	SimilarityJaccard = "jaccard"
+	SimilarityCosine  = "cosine"
+)
+
+// ItemSimilarity returns the frequent items and their pairwise similarity,
+// from the pair counts recorded during level-2 counting. Pairs that were never
+// counted, such as those pruned by the lift bound, are counted by a scan.
+func (am *AprioriMiner) ItemSimilarity(measure string) ([]string, [][]float64, error) {
+	if measure != SimilarityJaccard && measure != SimilarityCosine {
+		return nil, nil, fmt.Errorf("item similarity must be %s or %s", SimilarityJaccard, SimilarityCosine)
+	}
+	items := make([]string, 0, len(am.frequentSets[1]))
+	for _, set := range am.frequentSets[1] {
+		items = append(items, sortedItems(set)...)
+	}
+	sort.Strings(items)
+
+	counts := make([]float64, len(items))
+	for i, item := range items {
+		counts[i] = float64(am.countSupport(ItemSet{item: true}))
+	}
+	matrix := make([][]float64, len(items))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(items))
+		matrix[i][i] = 1
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			pair := ItemSet{items[i]: true, items[j]: true}
+			both, ok := am.pairCounts[itemsetKey(pair)]
+			if !ok {
+				both = am.countSupport(pair)
+			}
+			var s float64
+			if measure == SimilarityJaccard {
+				s = float64(both) / (counts[i] + counts[j] - float64(both))
+			} else {
+				s = float64(both) / math.Sqrt(counts[i]*counts[j])
+			}
+			matrix[i][j], matrix[j][i] = s, s
+		}
+	}
+	return items, matrix, nil
+}
+
+// writeSimilarityCSV writes a square similarity matrix with the items as
+// header row and first column
+func writeSimilarityCSV(path string, items []string, matrix [][]float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "Item,%s\n", strings.Join(items, ","))
+	for i, row := range matrix {
+		cells := make([]string, len(row))
+		for j, v := range row {
+			cells[j] = fmt.Sprintf("%f", v)
+		}
+		fmt.Fprintf(f, "%s,%s\n", items[i], strings.Join(cells, ","))
+	}
+	return f.Close()
+}
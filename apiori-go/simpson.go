@@ -0,0 +1,204 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StratumMeasures are the measures of a rule within one stratum
+type StratumMeasures struct {
+	Stratum      string  `json:"stratum"`
+	Transactions int     `json:"transactions"`
+	Support      float64 `json:"support"`
+	Confidence   float64 `json:"confidence"`
+	Lift         float64 `json:"lift"`
+}
+
+// StratifiedRule is a rule mined on all transactions together with its
+// measures within each stratum
+type StratifiedRule struct {
+	Rule
+	Strata []StratumMeasures `json:"strata"`
+	// Reversed counts the strata in which the association points the other
+	// way than overall: lift below 1 for an overall positive rule or above 1
+	// for an overall negative one
+	Reversed int `json:"reversed"`
+}
+
+// Paradox reports whether the association reverses in every stratum where
+// the rule can be measured, so the aggregate rule is an artefact of pooling
+func (r StratifiedRule) Paradox() bool {
+	return len(r.Strata) > 0 && r.Reversed == len(r.Strata)
+}
+
+// splitStrata removes the item starting with prefix from every transaction
+// and groups the transactions by its value. Transactions without such an item
+// are left out of the strata.
+func splitStrata(dataset Dataset, prefix string) (Dataset, map[string]Dataset) {
+	pooled := make(Dataset, 0, len(dataset))
+	strata := make(map[string]Dataset)
+	for _, transaction := range dataset {
+		stratum := ""
+		items := make(Transaction, 0, len(transaction))
+		for _, item := range transaction {
+			if strings.HasPrefix(item, prefix) {
+				stratum = strings.TrimPrefix(item, prefix)
+			} else {
+				items = append(items, item)
+			}
+		}
+		pooled = append(pooled, items)
+		if stratum != "" {
+			strata[stratum] = append(strata[stratum], items)
+		}
+	}
+	return pooled, strata
+}
+
+// stratifyRules measures every rule within each stratum. Strata in which
+// the antecedent or the consequent never occurs are skipped.
+func stratifyRules(rules []Rule, strata map[string]Dataset) []StratifiedRule {
+	names := make([]string, 0, len(strata))
+	for name := range strata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Unmined miners count supports by scanning their stratum
+	counters := make(map[string]*AprioriMiner, len(strata))
+	for _, name := range names {
+		counters[name] = NewAprioriMiner(strata[name], 1)
+	}
+
+	out := make([]StratifiedRule, 0, len(rules))
+	for _, rule := range rules {
+		sr := StratifiedRule{Rule: rule}
+		antecedent := toItemSet(rule.Antecedent)
+		consequent := toItemSet(rule.Consequent)
+		both := toItemSet(append(append([]string{}, rule.Antecedent...), rule.Consequent...))
+		for _, name := range names {
+			counter := counters[name]
+			n := float64(len(strata[name]))
+			a, c := counter.countSupport(antecedent), counter.countSupport(consequent)
+			if a == 0 || c == 0 {
+				continue
+			}
+			m := StratumMeasures{Stratum: name, Transactions: len(strata[name])}
+			m.Support = float64(counter.countSupport(both)) / n
+			m.Confidence = m.Support / (float64(a) / n)
+			m.Lift = m.Confidence / (float64(c) / n)
+			if (rule.Lift > 1 && m.Lift < 1) || (rule.Lift < 1 && m.Lift > 1) {
+				sr.Reversed++
+			}
+			sr.Strata = append(sr.Strata, m)
+		}
+		out = append(out, sr)
+	}
+	return out
+}
+
+// writeSimpsonCSVs writes one line per rule, flagging reversals, and one line
+// per rule and stratum
+func writeSimpsonCSVs(dir, base string, rules []StratifiedRule) error {
+	f, err := os.Create(filepath.Join(dir, base+"_simpson.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Antecedent,Consequent,Support,Confidence,Lift,Strata,ReversedStrata,MinStratumLift,MaxStratumLift,Flag")
+	for _, r := range rules {
+		minLift, maxLift := 0.0, 0.0
+		for i, m := range r.Strata {
+			if i == 0 || m.Lift < minLift {
+				minLift = m.Lift
+			}
+			if i == 0 || m.Lift > maxLift {
+				maxLift = m.Lift
+			}
+		}
+		label := ""
+		switch {
+		case r.Paradox():
+			label = "reversal"
+		case r.Reversed > 0:
+			label = "partial-reversal"
+		}
+		fmt.Fprintf(f, "\"%s\",\"%s\",%f,%f,%f,%d,%d,%f,%f,%s\n",
+			strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","), r.Support, r.Confidence, r.Lift,
+			len(r.Strata), r.Reversed, minLift, maxLift, label)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.Create(filepath.Join(dir, base+"_strata.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Antecedent,Consequent,Stratum,Transactions,Support,Confidence,Lift")
+	for _, r := range rules {
+		for _, m := range r.Strata {
+			fmt.Fprintf(f, "\"%s\",\"%s\",\"%s\",%d,%f,%f,%f\n",
+				strings.Join(r.Antecedent, ","), strings.Join(r.Consequent, ","),
+				m.Stratum, m.Transactions, m.Support, m.Confidence, m.Lift)
+		}
+	}
+	return f.Close()
+}
+
+// runSimpson implements the simpson subcommand, which mines rules on all
+// transactions and checks whether they hold within each stratum
+func runSimpson(args []string) error {
+	fs := flag.NewFlagSet("simpson", flag.ExitOnError)
+	prefix := fs.String("strata-prefix", "store=", "prefix of the item naming the stratum of a transaction")
+	minSupport := fs.Float64("minsupport", 0.1, "minimum support over all transactions")
+	minConfidence := fs.Float64("minconfidence", 0, "minimum confidence over all transactions")
+	outputDir := fs.String("output-dir", "results", "directory receiving <dataset>_simpson.csv and <dataset>_strata.csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: simpson [flags] <dataset>")
+	}
+	if *prefix == "" {
+		return fmt.Errorf("strata-prefix must not be empty")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	pooled, strata := splitStrata(dataset, *prefix)
+	if len(strata) == 0 {
+		return fmt.Errorf("no transaction has an item starting with %q", *prefix)
+	}
+
+	miner := NewAprioriMiner(pooled, *minSupport)
+	miner.Mine()
+	rules := stratifyRules(miner.GenerateRules(*minConfidence), strata)
+
+	reversals := 0
+	for _, r := range rules {
+		if r.Paradox() {
+			reversals++
+		}
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	base := getOutputBasename(filename)
+	if err := writeSimpsonCSVs(*outputDir, base, rules); err != nil {
+		return err
+	}
+	fmt.Printf("Checked %d rules across %d strata; %d reverse in every stratum\n", len(rules), len(strata), reversals)
+	fmt.Printf("Stratified rules written to %s\n", filepath.Join(*outputDir, base+"_simpson.csv"))
+	return nil
+}
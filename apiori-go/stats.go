@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// contingency is the 2x2 table of a rule X -> Y over n transactions, built
+// from the counts of X, Y and X∪Y
+type contingency struct {
+	n, x, y, xy int
+}
+
+// cells returns the counts of (X,Y), (X,¬Y), (¬X,Y) and (¬X,¬Y)
+func (c contingency) cells() (a, b, d, e float64) {
+	return float64(c.xy), float64(c.x - c.xy), float64(c.y - c.xy), float64(c.n - c.x - c.y + c.xy)
+}
+
+// chiSquare returns Pearson's chi-square statistic of the table against the
+// independence of X and Y, and its p-value with one degree of freedom. A
+// table with an empty row or column carries no evidence and yields (0, 1).
+func (c contingency) chiSquare() (float64, float64) {
+	a, b, d, e := c.cells()
+	n := float64(c.n)
+	denom := (a + b) * (d + e) * (a + d) * (b + e)
+	if denom == 0 {
+		return 0, 1
+	}
+	diff := a*e - b*d
+	stat := n * diff * diff / denom
+	// The chi-square CDF with one degree of freedom is erf(sqrt(x/2))
+	return stat, math.Erfc(math.Sqrt(stat / 2))
+}
+
+// fisherExact returns the one-sided p-value of Fisher's exact test for a
+// positive association of X and Y: the hypergeometric probability of at least
+// the observed count of X∪Y given the counts of X and Y. Unlike chi-square it
+// stays exact for small counts.
+func (c contingency) fisherExact() float64 {
+	hi := min(c.x, c.y)
+	lo := max(0, c.x+c.y-c.n)
+	if c.xy <= lo {
+		return 1
+	}
+	// log C(x,k) + log C(n-x,y-k) - log C(n,y) for each table with k
+	logChoose := func(n, k int) float64 {
+		a, _ := math.Lgamma(float64(n + 1))
+		b, _ := math.Lgamma(float64(k + 1))
+		d, _ := math.Lgamma(float64(n - k + 1))
+		return a - b - d
+	}
+	base := logChoose(c.n, c.y)
+	p := 0.0
+	for k := c.xy; k <= hi; k++ {
+		term := math.Exp(logChoose(c.x, k) + logChoose(c.n-c.x, c.y-k) - base)
+		p += term
+		// Past the mode the terms shrink geometrically, so the tail is negligible
+		if term < p*1e-16 {
+			break
+		}
+	}
+	return math.Min(p, 1)
+}
+
+// P-value adjustments for testing many rules at once
+const (
+	AdjustBonferroni        = "bonferroni"
+	AdjustBenjaminiHochberg = "bh"
+)
+
+// AdjustPValues sets the AdjustedPValue of every rule from its Fisher
+// p-value, correcting for the number of rules tested. Bonferroni controls
+// the family-wise error rate; Benjamini-Hochberg controls the false
+// discovery rate and is the default of GenerateRules.
+func AdjustPValues(rules []Rule, method string) error {
+	p := make([]float64, len(rules))
+	for i := range rules {
+		p[i] = rules[i].FisherPValue
+	}
+	adjusted, err := adjustPValues(p, method)
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		rules[i].AdjustedPValue = adjusted[i]
+	}
+	return nil
+}
+
+// adjustPValues corrects p-values for the number of tests by method
+func adjustPValues(p []float64, method string) ([]float64, error) {
+	m := float64(len(p))
+	adjusted := make([]float64, len(p))
+	switch method {
+	case AdjustBonferroni:
+		for i := range p {
+			adjusted[i] = math.Min(1, p[i]*m)
+		}
+	case AdjustBenjaminiHochberg:
+		order := make([]int, len(p))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return p[order[a]] < p[order[b]] })
+		// Walk from the largest p-value down, keeping the running minimum so
+		// adjusted values stay monotone in rank
+		running := 1.0
+		for rank := len(order); rank >= 1; rank-- {
+			i := order[rank-1]
+			running = math.Min(running, p[i]*m/float64(rank))
+			adjusted[i] = running
+		}
+	default:
+		return nil, fmt.Errorf("p-value adjustment must be %s or %s", AdjustBonferroni, AdjustBenjaminiHochberg)
+	}
+	return adjusted, nil
+}
+
+// nullInvariantMeasures returns the all-confidence, Kulczynski and cosine of
+// a pattern with the given support whose parts have partSupports: the items
+// of an itemset, or the antecedent and consequent of a rule. None of them
+// depends on the number of transactions containing no part, so unlike lift
+// they are not skewed by large, sparse datasets.
+func nullInvariantMeasures(support float64, partSupports []float64) (allConfidence, kulczynski, cosine float64) {
+	if len(partSupports) == 0 {
+		return 0, 0, 0
+	}
+	maxSupport, logSum := 0.0, 0.0
+	for _, s := range partSupports {
+		maxSupport = math.Max(maxSupport, s)
+		kulczynski += support / s
+		logSum += math.Log(s)
+	}
+	k := float64(len(partSupports))
+	// Cosine generalises to the geometric mean of the part supports
+	return support / maxSupport, kulczynski / k, support / math.Exp(logSum/k)
+}
+
+// leverageMeasures returns the leverage s(XY) - s(X)s(Y) of a rule and the
+// leverage divided by its largest possible magnitude given s(X) and s(Y),
+// which lies in [-1,1] and so compares across items of different frequency
+func leverageMeasures(support, antecedentSupport, consequentSupport float64) (leverage, normalized float64) {
+	expected := antecedentSupport * consequentSupport
+	leverage = support - expected
+	// The joint support is bounded by min(s(X),s(Y)) above and by
+	// max(0, s(X)+s(Y)-1) below
+	bound := math.Min(antecedentSupport, consequentSupport) - expected
+	if leverage < 0 {
+		bound = expected - math.Max(0, antecedentSupport+consequentSupport-1)
+	}
+	if bound <= 0 {
+		return leverage, 0
+	}
+	return leverage, leverage / bound
+}
+
+// MinReliableSupport returns the smallest support whose estimate from n
+// transactions has a normal-approximation confidence interval at level no
+// wider than relativeError times the support on either side. Below it the
+// support of an itemset is mostly sampling noise: from z·sqrt(p(1-p)/n) ≤ e·p
+// it follows that p ≥ z²/(n·e² + z²).
+func MinReliableSupport(n int, level, relativeError float64) float64 {
+	if n <= 0 {
+		return 1
+	}
+	z := math.Sqrt2 * math.Erfinv(level)
+	return z * z / (float64(n)*relativeError*relativeError + z*z)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Outcomes of a candidate itemset
+const (
+	// OutcomeCreated candidates have just been generated and are followed by
+	// a step with their final outcome
+	OutcomeCreated = "created"
+	// OutcomePruned candidates have a subset that is not frequent and are
+	// dropped before counting
+	OutcomePruned     = "pruned"
+	OutcomeInfrequent = "infrequent"
+	OutcomeFrequent   = "frequent"
+)
+
+// CandidateStep records what the miner decided about one candidate
+type CandidateStep struct {
+	Level   int      `json:"level"`
+	Items   []string `json:"items"`
+	Outcome string   `json:"outcome"`
+	// PrunedBy is the infrequent subset that pruned the candidate
+	PrunedBy []string `json:"prunedBy,omitempty"`
+	// Count and Support are zero for created and pruned candidates
+	Count   int     `json:"count"`
+	Support float64 `json:"support"`
+}
+
+// SetCandidateFunc registers a callback invoked for every candidate when it
+// is created and again when it is pruned or counted. It slows mining down and is meant for inspecting the
+// algorithm on small datasets.
+func (am *AprioriMiner) SetCandidateFunc(fn func(CandidateStep)) {
+	am.candidateHook = fn
+}
+
+// traceWriter writes candidate steps as JSON Lines, keeping the first error
+type traceWriter struct {
+	enc *json.Encoder
+	err error
+}
+
+func newTraceWriter(w io.Writer) *traceWriter {
+	return &traceWriter{enc: json.NewEncoder(w)}
+}
+
+// write records the final outcome of a candidate; creation steps are left out
+func (t *traceWriter) write(step CandidateStep) {
+	if t.err == nil && step.Outcome != OutcomeCreated {
+		t.err = t.enc.Encode(step)
+	}
+}
+
+// Events of an animation, in the order a candidate goes through them
+const (
+	EventCreated  = "created"
+	EventPruned   = "pruned"
+	EventCounted  = "counted"
+	EventAccepted = "accepted"
+	EventRejected = "rejected"
+)
+
+// AnimationEvent is one step of a mining run for replay by a visualization
+type AnimationEvent struct {
+	Seq int `json:"seq"`
+	// ElapsedMs is the time since mining started
+	ElapsedMs float64  `json:"elapsedMs"`
+	Event     string   `json:"event"`
+	Level     int      `json:"level"`
+	Items     []string `json:"items"`
+	PrunedBy  []string `json:"prunedBy,omitempty"`
+	Count     int      `json:"count,omitempty"`
+	Support   float64  `json:"support,omitempty"`
+}
+
+// Animation is a recorded mining run
+type Animation struct {
+	Dataset      string           `json:"dataset"`
+	Transactions int              `json:"transactions"`
+	MinSupport   float64          `json:"minSupport"`
+	Started      time.Time        `json:"started"`
+	Events       []AnimationEvent `json:"events"`
+}
+
+// newAnimation starts recording a run of the named dataset
+func newAnimation(dataset string, miner *AprioriMiner) *Animation {
+	return &Animation{Dataset: dataset, Transactions: miner.transactionLen, MinSupport: miner.minSupport,
+		Started: time.Now(), Events: []AnimationEvent{}}
+}
+
+// record turns a candidate step into events: counted candidates are
+// followed by their acceptance or rejection
+func (a *Animation) record(step CandidateStep) {
+	add := func(event string) {
+		a.Events = append(a.Events, AnimationEvent{Seq: len(a.Events) + 1,
+			ElapsedMs: float64(time.Since(a.Started).Microseconds()) / 1000, Event: event,
+			Level: step.Level, Items: step.Items, PrunedBy: step.PrunedBy, Count: step.Count, Support: step.Support})
+	}
+	switch step.Outcome {
+	case OutcomeCreated:
+		add(EventCreated)
+	case OutcomePruned:
+		add(EventPruned)
+	case OutcomeFrequent:
+		add(EventCounted)
+		add(EventAccepted)
+	case OutcomeInfrequent:
+		add(EventCounted)
+		add(EventRejected)
+	}
+}
+
+// WriteJSON writes the recorded run as one JSON document
+func (a *Animation) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// LevelPruning counts what became of the candidates of one level
+type LevelPruning struct {
+	Level     int `json:"level"`
+	Generated int `json:"generated"`
+	// Pruned candidates had an infrequent subset and were never counted
+	Pruned int `json:"pruned"`
+	// ConstraintPruned candidates were dropped by the -min-lift bound or an
+	// aggregate constraint before counting
+	ConstraintPruned int `json:"constraintPruned"`
+	Infrequent       int `json:"infrequent"`
+	Frequent         int `json:"frequent"`
+}
+
+// Counted is the number of candidates whose support was counted
+func (l LevelPruning) Counted() int {
+	return l.Infrequent + l.Frequent
+}
+
+// pruningCounter tallies candidate steps per level
+type pruningCounter map[int]*LevelPruning
+
+func (p pruningCounter) record(step CandidateStep) {
+	l, ok := p[step.Level]
+	if !ok {
+		l = &LevelPruning{Level: step.Level}
+		p[step.Level] = l
+	}
+	switch step.Outcome {
+	case OutcomeCreated:
+		l.Generated++
+	case OutcomePruned:
+		l.Pruned++
+	case OutcomeInfrequent:
+		l.Infrequent++
+	case OutcomeFrequent:
+		l.Frequent++
+	}
+}
+
+// Levels returns the tallies in level order. Candidates neither pruned nor
+// counted were dropped by a constraint; they do not occur in a level when
+// mining stopped before counting it.
+func (p pruningCounter) Levels() []LevelPruning {
+	out := make([]LevelPruning, 0, len(p))
+	for _, l := range p {
+		level := *l
+		if level.Counted() > 0 {
+			level.ConstraintPruned = level.Generated - level.Pruned - level.Counted()
+		}
+		out = append(out, level)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Level < out[j].Level })
+	return out
+}
+
+// WritePruningCSV writes the per-level tallies with the share of generated
+// candidates that subset pruning spared from counting
+func WritePruningCSV(w io.Writer, levels []LevelPruning) error {
+	fmt.Fprintln(w, "Level,Generated,Pruned,ConstraintPruned,Counted,Infrequent,Frequent,PrunedShare,FrequentShare")
+	for _, l := range levels {
+		prunedShare, frequentShare := 0.0, 0.0
+		if l.Generated > 0 {
+			prunedShare = float64(l.Pruned) / float64(l.Generated)
+			frequentShare = float64(l.Frequent) / float64(l.Generated)
+		}
+		_, err := fmt.Fprintf(w, "%d,%d,%d,%d,%d,%d,%d,%f,%f\n", l.Level, l.Generated, l.Pruned, l.ConstraintPruned,
+			l.Counted(), l.Infrequent, l.Frequent, prunedShare, frequentShare)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrunedItem is an item dropped by singleton filtering for falling below the
+// minimum support
+type PrunedItem struct {
+	Item    string  `json:"item"`
+	Count   int     `json:"count"`
+	Support float64 `json:"support"`
+}
+
+// PrunedItems returns the items below the minimum support of the first
+// level, most frequent first
+func (am *AprioriMiner) PrunedItems() []PrunedItem {
+	sort.Slice(am.prunedItems, func(i, j int) bool {
+		a, b := am.prunedItems[i], am.prunedItems[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Item < b.Item
+	})
+	return am.prunedItems
+}
+
+// WritePrunedItemsCSV writes the pruned items with the transactions each
+// lacked to reach minCount
+func WritePrunedItemsCSV(w io.Writer, items []PrunedItem, minCount int) error {
+	fmt.Fprintln(w, "Item,Count,Support,Shortfall")
+	for _, p := range items {
+		if _, err := fmt.Fprintf(w, "\"%s\",%d,%f,%d\n", p.Item, p.Count, p.Support, minCount-p.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
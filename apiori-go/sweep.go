@@ -0,0 +1,308 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sweepRun is the outcome of mining at one support threshold
+type sweepRun struct {
+	MinSupport     float64
+	Results        []ItemsetResult
+	ProcessingTime float64
+}
+
+// itemsetStability records at which thresholds of a sweep an itemset was
+// frequent
+type itemsetStability struct {
+	Size    int
+	Items   []string
+	Support float64
+	// Present lists the thresholds at which the itemset was found
+	Present []float64
+}
+
+// thresholdBand is the widest range of consecutive thresholds over which the
+// frequent itemsets of one size stay the same
+type thresholdBand struct {
+	Size       int
+	Low, High  float64
+	Thresholds int
+	Itemsets   int
+}
+
+// parseThresholds parses a comma-separated list of support thresholds and
+// returns them in ascending order
+func parseThresholds(s string) ([]float64, error) {
+	var thresholds []float64
+	for _, part := range strings.Split(s, ",") {
+		t, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || !(t > 0 && t <= 1) {
+			return nil, fmt.Errorf("invalid support threshold %q: must be in (0,1]", part)
+		}
+		thresholds = append(thresholds, t)
+	}
+	sort.Float64s(thresholds)
+	return thresholds, nil
+}
+
+// sweepStability collects every itemset found in the sweep together with the
+// thresholds at which it was frequent
+func sweepStability(runs []sweepRun) []itemsetStability {
+	byKey := make(map[string]*itemsetStability)
+	var keys []string
+	for _, run := range runs {
+		for _, r := range run.Results {
+			key := strings.Join(r.Items, ",")
+			s := byKey[key]
+			if s == nil {
+				s = &itemsetStability{Size: r.Size, Items: r.Items, Support: r.Support}
+				byKey[key] = s
+				keys = append(keys, key)
+			}
+			s.Present = append(s.Present, run.MinSupport)
+		}
+	}
+	out := make([]itemsetStability, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, *byKey[key])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].Present) != len(out[j].Present) {
+			return len(out[i].Present) > len(out[j].Present)
+		}
+		if out[i].Size != out[j].Size {
+			return out[i].Size < out[j].Size
+		}
+		return out[i].Support > out[j].Support
+	})
+	return out
+}
+
+// thresholdBands finds, for every itemset size, the longest run of
+// consecutive thresholds at which the same non-empty collection of itemsets
+// is frequent. Results inside the band do not hinge on the exact threshold.
+func thresholdBands(runs []sweepRun) []thresholdBand {
+	maxSize := 0
+	for _, run := range runs {
+		for _, r := range run.Results {
+			maxSize = max(maxSize, r.Size)
+		}
+	}
+	var bands []thresholdBand
+	for size := 1; size <= maxSize; size++ {
+		collections := make([]string, len(runs))
+		counts := make([]int, len(runs))
+		for i, run := range runs {
+			var keys []string
+			for _, r := range run.Results {
+				if r.Size == size {
+					keys = append(keys, strings.Join(r.Items, ","))
+				}
+			}
+			sort.Strings(keys)
+			collections[i] = strings.Join(keys, ";")
+			counts[i] = len(keys)
+		}
+		best := thresholdBand{Size: size}
+		for start := 0; start < len(runs); {
+			end := start
+			for end+1 < len(runs) && collections[end+1] == collections[start] {
+				end++
+			}
+			if counts[start] > 0 && end-start+1 > best.Thresholds {
+				best.Low, best.High = runs[start].MinSupport, runs[end].MinSupport
+				best.Thresholds = end - start + 1
+				best.Itemsets = counts[start]
+			}
+			start = end + 1
+		}
+		if best.Thresholds > 0 {
+			bands = append(bands, best)
+		}
+	}
+	return bands
+}
+
+// filterBySupport returns the results with a support of at least minSupport;
+// by the apriori property they are exactly the itemsets mined at it
+func filterBySupport(results []ItemsetResult, minSupport float64) []ItemsetResult {
+	var kept []ItemsetResult
+	for _, r := range results {
+		if r.Support >= minSupport {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// writeThresholdTags writes every itemset of the lowest threshold tagged
+// with the ascending thresholds it satisfies
+func writeThresholdTags(path string, results []ItemsetResult, thresholds []float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Size,Items,Support,Thresholds,HighestThreshold")
+	for _, r := range results {
+		var tags []string
+		highest := 0.0
+		for _, t := range thresholds {
+			if r.Support >= t {
+				tags = append(tags, strconv.FormatFloat(t, 'f', -1, 64))
+				highest = t
+			}
+		}
+		fmt.Fprintf(f, "%d,\"%s\",%f,\"%s\",%f\n", r.Size, strings.Join(r.Items, ","), r.Support, strings.Join(tags, ","), highest)
+	}
+	return f.Close()
+}
+
+// writeThresholdViews writes the itemsets of each threshold in the layout of
+// the summary file, as the run at that threshold alone would
+func writeThresholdViews(dir, base string, runs []sweepRun) error {
+	for _, run := range runs {
+		path := filepath.Join(dir, fmt.Sprintf("%s_support_%s_summary.csv", base, strconv.FormatFloat(run.MinSupport, 'f', -1, 64)))
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = WriteResultsCSV(f, run.Results)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSweepReports writes the per-threshold summary, the itemset stability
+// report and the recommended threshold bands to dir
+func writeSweepReports(dir, base string, runs []sweepRun, stableFraction float64) error {
+	f, err := os.Create(filepath.Join(dir, base+"_sweep.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "MinSupport,Itemsets,ProcessingSeconds")
+	for _, run := range runs {
+		fmt.Fprintf(f, "%f,%d,%f\n", run.MinSupport, len(run.Results), run.ProcessingTime)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.Create(filepath.Join(dir, base+"_stability.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Size,Items,Support,Thresholds,LowestThreshold,HighestThreshold,Presence,Status")
+	for _, s := range sweepStability(runs) {
+		presence := float64(len(s.Present)) / float64(len(runs))
+		status := "stable"
+		if presence < stableFraction {
+			status = "threshold-specific"
+		}
+		fmt.Fprintf(f, "%d,\"%s\",%f,%d,%f,%f,%f,%s\n", s.Size, strings.Join(s.Items, ","), s.Support,
+			len(s.Present), s.Present[0], s.Present[len(s.Present)-1], presence, status)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.Create(filepath.Join(dir, base+"_threshold_bands.csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Size,BandLow,BandHigh,Thresholds,Itemsets")
+	for _, b := range thresholdBands(runs) {
+		fmt.Fprintf(f, "%d,%f,%f,%d,%d\n", b.Size, b.Low, b.High, b.Thresholds, b.Itemsets)
+	}
+	return f.Close()
+}
+
+// runSweep implements the sweep subcommand, which mines a dataset at a range
+// of support thresholds and reports how stable the itemsets are across them
+func runSweep(args []string) error {
+	fs := flag.NewFlagSet("sweep", flag.ExitOnError)
+	supports := fs.String("supports", "0.1,0.2,0.3,0.4,0.5", "comma-separated minimum supports to mine at")
+	stableFraction := fs.Float64("stable-fraction", 0.5, "fraction of thresholds an itemset must appear at to count as stable")
+	reliabilityLevel := fs.Float64("reliability-level", 0.95, "confidence level of the minimum reliable support")
+	reliabilityError := fs.Float64("reliability-error", 0.5, "largest relative error of a support estimate considered reliable")
+	singlePass := fs.Bool("single-pass", false, "mine once at the lowest threshold and derive the others from it")
+	views := fs.Bool("views", false, "also write the itemsets of each threshold to <dataset>_support_<t>_summary.csv")
+	outputDir := fs.String("output-dir", "results", "directory receiving the sweep reports")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: sweep [flags] <dataset>")
+	}
+	thresholds, err := parseThresholds(*supports)
+	if err != nil {
+		return err
+	}
+	if !(*reliabilityLevel > 0 && *reliabilityLevel < 1) || !(*reliabilityError > 0) {
+		return fmt.Errorf("reliability-level must be in (0,1) and reliability-error positive")
+	}
+
+	filename := fs.Arg(0)
+	dataset, err := LoadDataset(filename)
+	if err != nil {
+		return err
+	}
+	reliable := MinReliableSupport(len(dataset), *reliabilityLevel, *reliabilityError)
+	fmt.Printf("Minimum reliable support: %.4f\n", reliable)
+	runs := make([]sweepRun, 0, len(thresholds))
+	var lowest []ItemsetResult
+	for i, t := range thresholds {
+		start := time.Now()
+		var results []ItemsetResult
+		if *singlePass && i > 0 {
+			// The lowest threshold's itemsets hold those of every higher one
+			results = filterBySupport(lowest, t)
+		} else {
+			miner := NewAprioriMiner(dataset, t)
+			miner.Mine()
+			results = miner.Results()
+		}
+		if i == 0 {
+			lowest = results
+		}
+		runs = append(runs, sweepRun{MinSupport: t, Results: results, ProcessingTime: time.Since(start).Seconds()})
+		note := ""
+		if t < reliable {
+			note = " (below the minimum reliable support)"
+		}
+		fmt.Printf("minsupport %.3f: %d frequent itemsets%s\n", t, len(results), note)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	base := getOutputBasename(filename)
+	if err := writeSweepReports(*outputDir, base, runs, *stableFraction); err != nil {
+		return err
+	}
+	if err := writeThresholdTags(filepath.Join(*outputDir, base+"_threshold_tags.csv"), lowest, thresholds); err != nil {
+		return err
+	}
+	if *views {
+		if err := writeThresholdViews(*outputDir, base, runs); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Sweep reports written to %s\n", filepath.Join(*outputDir, base+"_{sweep,stability,threshold_bands,threshold_tags}.csv"))
+	return nil
+}
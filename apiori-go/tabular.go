@@ -0,0 +1,337 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Table is a delimited file with a header row naming its columns
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// parseDelimiter returns the single-character column delimiter given on the
+// command line, accepting \t for a tab
+func parseDelimiter(s string) (rune, error) {
+	if s == `\t` {
+		return '\t', nil
+	}
+	delim := []rune(s)
+	if len(delim) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character")
+	}
+	return delim[0], nil
+}
+
+// ReadTable reads a delimited file in encoding (empty for UTF-8) whose first
+// row names the columns. Cells are trimmed and short rows padded with empty
+// cells.
+func ReadTable(filename string, delimiter rune, encoding string) (*Table, error) {
+	file, err := openInput(filename, encoding)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	table := &Table{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if table.Header == nil {
+			table.Header = record
+			continue
+		}
+		if len(record) == 1 && record[0] == "" {
+			continue
+		}
+		if len(record) > len(table.Header) {
+			return nil, fmt.Errorf("%s:%d: %d cells for %d columns", filename, row, len(record), len(table.Header))
+		}
+		for len(record) < len(table.Header) {
+			record = append(record, "")
+		}
+		table.Rows = append(table.Rows, record)
+	}
+	if table.Header == nil {
+		return nil, fmt.Errorf("%s has no header row", filename)
+	}
+	return table, nil
+}
+
+// WriteTable writes a table with its header row
+func WriteTable(path string, table *Table, delimiter rune) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Comma = delimiter
+	w.Write(table.Header)
+	w.WriteAll(table.Rows)
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// columnIndex returns the position of each named column
+func (t *Table) columnIndex(names []string) ([]int, error) {
+	at := make(map[string]int, len(t.Header))
+	for i, name := range t.Header {
+		at[name] = i
+	}
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		j, ok := at[name]
+		if !ok {
+			return nil, fmt.Errorf("no column %q", name)
+		}
+		indexes[i] = j
+	}
+	return indexes, nil
+}
+
+// column returns the cells of column i
+func (t *Table) column(i int) []string {
+	cells := make([]string, len(t.Rows))
+	for r, row := range t.Rows {
+		cells[r] = row[i]
+	}
+	return cells
+}
+
+// selectColumns returns the columns named by include, or every column when
+// include is empty, less those named by exclude
+func (t *Table) selectColumns(include, exclude []string) ([]int, error) {
+	if len(include) == 0 {
+		include = t.Header
+	}
+	selected, err := t.columnIndex(include)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := t.columnIndex(exclude)
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[int]bool, len(excluded))
+	for _, i := range excluded {
+		skip[i] = true
+	}
+	kept := selected[:0]
+	for _, i := range selected {
+		if !skip[i] {
+			kept = append(kept, i)
+		}
+	}
+	return kept, nil
+}
+
+// tableItem names the item of a cell as column<separator>value, joining
+// whitespace in either with underscores so items stay whitespace-free
+func tableItem(column, value, separator string) string {
+	return strings.Join(strings.Fields(column), "_") + separator + strings.Join(strings.Fields(value), "_")
+}
+
+// Policies for the missing cells of a table column
+const (
+	// MissingDrop leaves the cell's item out of the row's transaction
+	MissingDrop = "drop"
+	// MissingItem emits column=missing in its place
+	MissingItem = "missing"
+	// MissingDropRow drops the whole row
+	MissingDropRow = "drop-row"
+)
+
+// MissingSummary counts the missing cells of each converted column and the
+// rows dropped over them
+type MissingSummary struct {
+	Columns     []string
+	Policies    []string
+	Values      []int
+	RowsDropped int
+}
+
+// parseMissingPolicies returns the policy of each column: the one given for
+// it in spec, a comma-separated list of column:policy pairs, or fallback
+func parseMissingPolicies(table *Table, columns []int, fallback, spec string) ([]string, error) {
+	valid := func(policy string) bool {
+		return policy == MissingDrop || policy == MissingItem || policy == MissingDropRow
+	}
+	if !valid(fallback) {
+		return nil, fmt.Errorf("missing policy must be %s, %s or %s", MissingDrop, MissingItem, MissingDropRow)
+	}
+	byColumn := make(map[string]string)
+	for _, pair := range parseParamList(spec) {
+		column, policy, ok := strings.Cut(pair, ":")
+		if !ok || !valid(policy) {
+			return nil, fmt.Errorf("invalid column policy %q: want column:%s, column:%s or column:%s", pair, MissingDrop, MissingItem, MissingDropRow)
+		}
+		if _, err := table.columnIndex([]string{column}); err != nil {
+			return nil, err
+		}
+		byColumn[column] = policy
+	}
+	policies := make([]string, len(columns))
+	for j, i := range columns {
+		policies[j] = fallback
+		if policy, ok := byColumn[table.Header[i]]; ok {
+			policies[j] = policy
+		}
+	}
+	return policies, nil
+}
+
+// TableTransactions turns every row into a transaction of column=value
+// items from the given columns, treating missing cells by the policy of
+// their column
+func TableTransactions(table *Table, columns []int, policies []string, separator string) (Dataset, MissingSummary) {
+	summary := MissingSummary{Policies: policies, Values: make([]int, len(columns))}
+	for _, i := range columns {
+		summary.Columns = append(summary.Columns, table.Header[i])
+	}
+	dataset := make(Dataset, 0, len(table.Rows))
+rows:
+	for _, row := range table.Rows {
+		t := make(Transaction, 0, len(columns))
+		// Rows are dropped on their first drop-row column, leaving the
+		// later columns' counts to the rows kept
+		for j, i := range columns {
+			if !isMissing(row[i]) {
+				t = append(t, tableItem(table.Header[i], row[i], separator))
+				continue
+			}
+			summary.Values[j]++
+			switch policies[j] {
+			case MissingItem:
+				t = append(t, tableItem(table.Header[i], MissingItem, separator))
+			case MissingDropRow:
+				summary.RowsDropped++
+				continue rows
+			}
+		}
+		dataset = append(dataset, t)
+	}
+	return dataset, summary
+}
+
+// writeMissingCSV writes the missing cells counted for each column
+func writeMissingCSV(path string, summary MissingSummary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintln(f, "Column,Policy,MissingValues")
+	for j, column := range summary.Columns {
+		fmt.Fprintf(f, "\"%s\",%s,%d\n", column, summary.Policies[j], summary.Values[j])
+	}
+	return f.Close()
+}
+
+// writeTransactions writes a dataset in the space-separated input format
+func writeTransactions(path string, dataset Dataset) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	for _, t := range dataset {
+		fmt.Fprintln(w, strings.Join(t, " "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// runOneHot implements the onehot subcommand, which converts a categorical
+// table with a row per entity into a dataset with a transaction per row of
+// column=value items
+func runOneHot(args []string) error {
+	fs := flag.NewFlagSet("onehot", flag.ExitOnError)
+	include := fs.String("include", "", "comma-separated columns to convert (default: all)")
+	exclude := fs.String("exclude", "", "comma-separated columns to leave out, such as row IDs")
+	separator := fs.String("separator", "=", "text between column and value in an item")
+	delimiter := fs.String("delimiter", ",", "column delimiter of the table")
+	encoding := fs.String("encoding", "", "character encoding of the table, such as latin1 or gbk (default UTF-8)")
+	missing := fs.String("missing", MissingDrop, "policy for empty or NA cells: drop (the item), missing (emit column=missing) or drop-row")
+	columnMissing := fs.String("missing-policy", "", "comma-separated column:policy pairs overriding -missing for those columns")
+	output := fs.String("o", "", "dataset file (default: results/<table>_transactions.txt, with missing value counts in _missing.csv)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: onehot [flags] <table.csv>")
+	}
+	if strings.TrimSpace(*separator) != *separator || *separator == "" {
+		return fmt.Errorf("separator must be non-empty without whitespace")
+	}
+	delim, err := parseDelimiter(*delimiter)
+	if err != nil {
+		return err
+	}
+
+	filename := fs.Arg(0)
+	table, err := ReadTable(filename, delim, *encoding)
+	if err != nil {
+		return err
+	}
+	columns, err := table.selectColumns(parseParamList(*include), parseParamList(*exclude))
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns left to convert")
+	}
+	policies, err := parseMissingPolicies(table, columns, *missing, *columnMissing)
+	if err != nil {
+		return err
+	}
+	dataset, summary := TableTransactions(table, columns, policies, *separator)
+
+	path := *output
+	if path == "" {
+		if err := os.MkdirAll("results", 0755); err != nil {
+			return err
+		}
+		path = filepath.Join("results", getOutputBasename(filename)+"_transactions.txt")
+	}
+	if err := writeTransactions(path, dataset); err != nil {
+		return err
+	}
+	missingPath := strings.TrimSuffix(path, filepath.Ext(path)) + "_missing.csv"
+	if err := writeMissingCSV(missingPath, summary); err != nil {
+		return err
+	}
+	fmt.Printf("Converted %d rows of %d columns into %d distinct items\n", len(dataset), len(columns), len(distinctItems(dataset)))
+	affected := make(map[string]int)
+	for j, n := range summary.Values {
+		affected[summary.Policies[j]] += n
+	}
+	fmt.Printf("Missing values: %d dropped as items, %d emitted as %s items, %d dropping %d rows\n",
+		affected[MissingDrop], affected[MissingItem], MissingItem, affected[MissingDropRow], summary.RowsDropped)
+	fmt.Printf("Transactions written to %s, missing value counts to %s\n", path, missingPath)
+	return nil
+}
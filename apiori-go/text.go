@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// englishStopwords are the common English words dropped from documents
+// unless other stopwords are given
+var englishStopwords = strings.Fields(`a about above after again against all am an and any are as at be because
+been before being below between both but by can could did do does doing down during each few for from
+further had has have having he her here hers herself him himself his how i if in into is it its itself
+just me more most my myself no nor not now of off on once only or other our ours ourselves out over own
+same she should so some such than that the their theirs them themselves then there these they this those
+through to too under until up very was we were what when where which while who whom why will with would
+you your yours yourself yourselves`)
+
+// TextOptions controls how documents are tokenized into transactions
+type TextOptions struct {
+	// MinLength drops tokens with fewer characters
+	MinLength int
+	// Stopwords are dropped, compared in lower case
+	Stopwords map[string]bool
+	// FilePerDocument reads every file of a directory as one document
+	// instead of every line of a file
+	FilePerDocument bool
+}
+
+// LoadStopwords reads whitespace-separated stopwords; lines starting with #
+// are ignored
+func LoadStopwords(filename string) (map[string]bool, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	stopwords := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			stopwords[strings.ToLower(word)] = true
+		}
+	}
+	return stopwords, nil
+}
+
+// tokenize returns the distinct lower-cased terms of a document in order of
+// first occurrence. Terms are runs of letters and digits, with apostrophes
+// inside words dropped so "don't" becomes "dont".
+func tokenize(document string, opts TextOptions) Transaction {
+	seen := make(map[string]bool)
+	var terms Transaction
+	fields := strings.FieldsFunc(strings.ToLower(document), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+	for _, field := range fields {
+		term := strings.NewReplacer("'", "", "’", "").Replace(field)
+		if len([]rune(term)) < opts.MinLength || term == "" || opts.Stopwords[term] || opts.Stopwords[field] || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// LoadTextDataset reads documents as transactions of their terms: a
+// document per line of path, or with FilePerDocument a document per regular
+// file of the directory path, in name order. Documents left without terms
+// are kept as empty transactions so supports stay relative to every document.
+func LoadTextDataset(path string, opts TextOptions) (Dataset, error) {
+	var dataset Dataset
+	if opts.FilePerDocument {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			dataset = append(dataset, tokenize(string(data), opts))
+		}
+		if len(dataset) == 0 {
+			return nil, fmt.Errorf("%s holds no documents", path)
+		}
+		return dataset, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	// Documents run far longer than the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		dataset = append(dataset, tokenize(scanner.Text(), opts))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
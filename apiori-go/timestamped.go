@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the textual timestamp layouts accepted in timestamped
+// datasets, tried in order
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTimestamp parses an RFC 3339 time, a date-time without zone taken as
+// UTC, a date, or Unix seconds or milliseconds
+func parseTimestamp(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
+
+// LoadTimestampedDataset reads a dataset whose lines start with the time of
+// the transaction followed by its items, and returns the transactions with
+// their times
+func LoadTimestampedDataset(filename string) (Dataset, []time.Time, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var dataset Dataset
+	var times []time.Time
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		t, err := parseTimestamp(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+		dataset = append(dataset, fields[1:])
+		times = append(times, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return dataset, times, nil
+}
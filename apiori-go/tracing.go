@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records spans for the mining phases. It is a no-op until
+// setupTracing installs an exporting provider.
+var tracer = otel.Tracer("algo-project/apriori")
+
+// traced runs fn inside a span called name, recording its error
+func traced(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) error {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	defer span.End()
+	err := fn(ctx)
+	endSpanError(span, err)
+	return err
+}
+
+// endSpanError marks span as failed when err is set
+func endSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
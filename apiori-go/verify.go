@@ -0,0 +1,119 @@
+//go:build !js
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// resultDifference is one way two itemset results disagree
+type resultDifference struct {
+	Items    []string
+	Expected *ItemsetResult
+	Actual   *ItemsetResult
+}
+
+func (d resultDifference) String() string {
+	items := "{" + strings.Join(d.Items, ",") + "}"
+	switch {
+	case d.Actual == nil:
+		return fmt.Sprintf("missing    %s (expected support %f)", items, d.Expected.Support)
+	case d.Expected == nil:
+		return fmt.Sprintf("unexpected %s (support %f)", items, d.Actual.Support)
+	default:
+		return fmt.Sprintf("support    %s: expected %f, got %f", items, d.Expected.Support, d.Actual.Support)
+	}
+}
+
+// compareResults returns the itemsets missing from or unexpected in actual,
+// and those whose supports differ by more than tolerance. Both sides must be
+// sorted by sortResults.
+func compareResults(expected, actual []ItemsetResult, tolerance float64) []resultDifference {
+	var diffs []resultDifference
+	key := func(r ItemsetResult) string { return strings.Join(r.Items, "\x00") }
+	less := func(a, b ItemsetResult) bool {
+		if a.Size != b.Size {
+			return a.Size < b.Size
+		}
+		return key(a) < key(b)
+	}
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case j == len(actual) || (i < len(expected) && less(expected[i], actual[j])):
+			diffs = append(diffs, resultDifference{Items: expected[i].Items, Expected: &expected[i]})
+			i++
+		case i == len(expected) || less(actual[j], expected[i]):
+			diffs = append(diffs, resultDifference{Items: actual[j].Items, Actual: &actual[j]})
+			j++
+		default:
+			if math.Abs(expected[i].Support-actual[j].Support) > tolerance {
+				diffs = append(diffs, resultDifference{Items: actual[j].Items, Expected: &expected[i], Actual: &actual[j]})
+			}
+			i++
+			j++
+		}
+	}
+	return diffs
+}
+
+// runVerify implements the verify subcommand, which mines a dataset and
+// compares the frequent itemsets with a stored golden result
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	golden := fs.String("golden", "", "golden result in the layout of <dataset>_summary.csv")
+	minSupport := fs.Float64("minsupport", 0.4, "minimum support the golden result was mined at")
+	tolerance := fs.Float64("tolerance", 1e-6, "largest support difference accepted")
+	update := fs.Bool("update", false, "write the mined result to the golden file instead of comparing")
+	fs.Parse(args)
+	if fs.NArg() != 1 || *golden == "" {
+		return fmt.Errorf("usage: verify -golden file [flags] <dataset>")
+	}
+	if !(*minSupport > 0 && *minSupport <= 1) {
+		return fmt.Errorf("minsupport must be in (0,1]")
+	}
+
+	dataset, err := LoadDataset(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	miner := NewAprioriMiner(dataset, *minSupport)
+	miner.Mine()
+	actual := miner.Results()
+
+	if *update {
+		f, err := os.Create(*golden)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := WriteResultsCSV(f, actual); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d itemsets to golden file %s\n", len(actual), *golden)
+		return f.Close()
+	}
+
+	f, err := os.Open(*golden)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	expected, err := ReadResultsCSV(f)
+	if err != nil {
+		return fmt.Errorf("failed to read golden file %s: %v", *golden, err)
+	}
+	diffs := compareResults(expected, actual, *tolerance)
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d differences between the mined result and %s", len(diffs), *golden)
+	}
+	fmt.Printf("Mined result matches %s (%d itemsets)\n", *golden, len(expected))
+	return nil
+}
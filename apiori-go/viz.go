@@ -0,0 +1,127 @@
+//go:build !js
+
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// vizPage renders the lattice and rule graph from the JSON endpoints of runViz
+//
+//go:embed viz.html
+var vizPage []byte
+
+// LatticeNode is a frequent itemset of the lattice
+type LatticeNode struct {
+	ID      int      `json:"id"`
+	Items   []string `json:"items"`
+	Size    int      `json:"size"`
+	Support float64  `json:"support"`
+}
+
+// LatticeEdge links an itemset to a superset one item larger
+type LatticeEdge struct {
+	Source int `json:"source"`
+	Target int `json:"target"`
+}
+
+// Lattice is the Hasse diagram of the frequent itemsets
+type Lattice struct {
+	Nodes []LatticeNode `json:"nodes"`
+	Edges []LatticeEdge `json:"edges"`
+}
+
+// buildLattice links every itemset to the frequent itemsets one item larger
+// containing it
+func buildLattice(results []ItemsetResult) Lattice {
+	lattice := Lattice{Nodes: make([]LatticeNode, len(results)), Edges: []LatticeEdge{}}
+	ids := make(map[string]int, len(results))
+	for i, r := range results {
+		lattice.Nodes[i] = LatticeNode{ID: i, Items: r.Items, Size: r.Size, Support: r.Support}
+		ids[itemsetKey(toItemSet(r.Items))] = i
+	}
+	for i, r := range results {
+		if r.Size < 2 {
+			continue
+		}
+		for skip := range r.Items {
+			subset := make([]string, 0, len(r.Items)-1)
+			subset = append(subset, r.Items[:skip]...)
+			subset = append(subset, r.Items[skip+1:]...)
+			if id, ok := ids[itemsetKey(toItemSet(subset))]; ok {
+				lattice.Edges = append(lattice.Edges, LatticeEdge{Source: id, Target: i})
+			}
+		}
+	}
+	return lattice
+}
+
+// runViz implements the viz subcommand, which serves a local page drawing the
+// itemset lattice and rule graph of a saved result
+func runViz(args []string) error {
+	fs := flag.NewFlagSet("viz", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8090", "address to listen on")
+	transactions := fs.Int("transactions", 0, "transactions of the mined dataset (default: from the run manifest)")
+	minConfidence := fs.Float64("minconfidence", 0.5, "default minimum confidence of the rule graph")
+	maxRules := fs.Int("max-rules", 100, "default number of rules drawn, best by lift")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: viz [flags] results/<run>")
+	}
+	path := modelSummaryPath(fs.Arg(0))
+	miner, results, err := loadSavedItemsets(path, *transactions)
+	if err != nil {
+		return err
+	}
+	lattice := buildLattice(results)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(vizPage)
+	})
+	mux.HandleFunc("GET /api/summary", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"result": path, "transactions": miner.transactionLen, "itemsets": len(results),
+			"minConfidence": *minConfidence, "maxRules": *maxRules,
+		})
+	})
+	mux.HandleFunc("GET /api/lattice", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, lattice)
+	})
+	mux.HandleFunc("GET /api/rules", func(w http.ResponseWriter, r *http.Request) {
+		confidence, limit := *minConfidence, *maxRules
+		var err error
+		if v := r.URL.Query().Get("minconfidence"); v != "" {
+			if confidence, err = strconv.ParseFloat(v, 64); err != nil || confidence < 0 || confidence > 1 {
+				writeError(w, http.StatusBadRequest, "minconfidence must be a number in [0,1]")
+				return
+			}
+		}
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+				writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+				return
+			}
+		}
+		rules := miner.GenerateRules(confidence)
+		SortRulesBy(rules, RankLift)
+		if limit > 0 && len(rules) > limit {
+			rules = rules[:limit]
+		}
+		writeJSON(w, http.StatusOK, rules)
+	})
+
+	url := *addr
+	if strings.HasPrefix(url, ":") {
+		url = "localhost" + url
+	}
+	log.Printf("Serving %s (%d itemsets) on http://%s", path, len(results), url)
+	return http.ListenAndServe(*addr, mux)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WarmCounts are the counts of the first levels of a dataset, kept between
+// runs so that re-mining the same transactions at another threshold skips
+// the scans counting them. Item counts cover every item; pair counts cover
+// the level-2 candidates counted so far, which serve every later run whose
+// candidates they include.
+type WarmCounts struct {
+	// Dataset identifies the transactions mined, as DatasetKey returns it
+	Dataset      string         `json:"dataset"`
+	Transactions int            `json:"transactions"`
+	Items        map[string]int `json:"items"`
+	Pairs        map[string]int `json:"pairs,omitempty"`
+}
+
+// WarmStartStats reports which levels a run took from warm counts
+type WarmStartStats struct {
+	ItemsReused bool
+	PairsReused bool
+}
+
+// DatasetKey identifies the transactions the miner counts by the contents of
+// filename and the loading options that shaped them. Hashing the file's
+// bytes costs far less than hashing the parsed transactions. Without a
+// regular file, such as for the example dataset or a directory of documents,
+// the transactions themselves are hashed.
+func (am *AprioriMiner) DatasetKey(filename string, options []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "multiset=%t\n", am.multiset)
+	for _, option := range options {
+		io.WriteString(h, option+"\n")
+	}
+	info, err := os.Stat(filename)
+	if filename != "" && err == nil && !info.IsDir() {
+		file, err := os.Open(filename)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		if _, err := io.Copy(h, file); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	for _, transaction := range am.dataset {
+		io.WriteString(h, strings.Join(transaction, "\x00")+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// warmCountsPath is the file of the warm counts of a dataset key in dir
+func warmCountsPath(dir, key string) string {
+	return filepath.Join(dir, key+".json")
+}
+
+// loadWarmCounts returns the warm counts saved in dir for a dataset key, or
+// empty counts for the key when none were saved
+func loadWarmCounts(dir, key string, transactions int) (*WarmCounts, error) {
+	fresh := &WarmCounts{Dataset: key, Transactions: transactions}
+	data, err := os.ReadFile(warmCountsPath(dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var w WarmCounts
+	if err := json.Unmarshal(data, &w); err != nil {
+		log.Printf("ignoring corrupt warm counts: %v", err)
+		return fresh, nil
+	}
+	if w.Dataset != key || w.Transactions != transactions {
+		return fresh, nil
+	}
+	return &w, nil
+}
+
+// saveWarmCounts writes warm counts into dir for the next run
+func saveWarmCounts(dir string, w *WarmCounts) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(warmCountsPath(dir, w.Dataset), data)
+}
+
+// SetWarmCounts makes the miner take the item counts, and the pair counts
+// when they cover every level-2 candidate, from w instead of scanning the
+// dataset. Counts it scans are added to w, pairs only with recordPairs, so
+// w can be saved for the next run. w must come from the same transactions.
+func (am *AprioriMiner) SetWarmCounts(w *WarmCounts, recordPairs bool) {
+	am.warm = w
+	am.warmPairs = recordPairs
+}
+
+// WarmStart returns which levels mining took from the warm counts
+func (am *AprioriMiner) WarmStart() WarmStartStats {
+	return am.warmStats
+}
+
+// warmItemCounts returns the cached item counts, or nil when the items must
+// be counted
+func (am *AprioriMiner) warmItemCounts() map[string]int {
+	if am.warm == nil || am.warm.Items == nil {
+		return nil
+	}
+	am.warmStats.ItemsReused = true
+	return am.warm.Items
+}
+
+// warmPairCounts returns the cached counts of level-2 candidates when every
+// one of them is cached, or nil
+func (am *AprioriMiner) warmPairCounts(candidates []ItemSet) []int {
+	if am.warm == nil || am.warm.Pairs == nil || len(candidates) == 0 || len(candidates[0]) != 2 {
+		return nil
+	}
+	counts := make([]int, len(candidates))
+	for i, candidate := range candidates {
+		count, ok := am.warm.Pairs[itemsetKey(candidate)]
+		if !ok {
+			return nil
+		}
+		counts[i] = count
+	}
+	am.warmStats.PairsReused = true
+	return counts
+}
+
+// recordWarmCounts adds the scanned counts of level-2 candidates to the
+// warm pair counts
+func (am *AprioriMiner) recordWarmCounts(candidates []ItemSet, counts []int) {
+	if am.warm == nil || !am.warmPairs || len(candidates) == 0 || len(candidates[0]) != 2 {
+		return
+	}
+	if am.warm.Pairs == nil {
+		am.warm.Pairs = make(map[string]int, len(candidates))
+	}
+	for i, candidate := range candidates {
+		am.warm.Pairs[itemsetKey(candidate)] = counts[i]
+	}
+}
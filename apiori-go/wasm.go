@@ -0,0 +1,151 @@
+//go:build js && wasm
+
+// The WebAssembly build exposes the miner to JavaScript for running Apriori
+// client-side. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o apriori.wasm .
+//
+// and load it next to $(go env GOROOT)/lib/wasm/wasm_exec.js. Once the module
+// runs, a global apriori object provides:
+//
+//	apriori.mine(text, {minSupport, format})                 -> itemsets
+//	apriori.rules(text, {minSupport, minConfidence, format}) -> rules
+//
+// text holds one transaction per line, with whitespace-separated items or,
+// when format is "csv", comma-separated cells. Results are plain objects in
+// the JSON layout of the server API; invalid input returns {error: message}.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"syscall/js"
+)
+
+// wasmOptions are the options accepted by the JavaScript API
+type wasmOptions struct {
+	MinSupport    float64
+	MinConfidence float64
+	Format        string
+}
+
+// parseWasmOptions reads options from a JavaScript object, applying the CLI defaults
+func parseWasmOptions(v js.Value) (wasmOptions, error) {
+	opts := wasmOptions{MinSupport: 0.4, MinConfidence: 0.6, Format: "lines"}
+	if v.Type() != js.TypeObject {
+		return opts, nil
+	}
+	if s := v.Get("minSupport"); s.Type() == js.TypeNumber {
+		opts.MinSupport = s.Float()
+	}
+	if c := v.Get("minConfidence"); c.Type() == js.TypeNumber {
+		opts.MinConfidence = c.Float()
+	}
+	if f := v.Get("format"); f.Type() == js.TypeString {
+		opts.Format = f.String()
+	}
+	if !(opts.MinSupport > 0 && opts.MinSupport <= 1) {
+		return opts, fmt.Errorf("minSupport must be in (0,1]")
+	}
+	if !(opts.MinConfidence >= 0 && opts.MinConfidence <= 1) {
+		return opts, fmt.Errorf("minConfidence must be in [0,1]")
+	}
+	return opts, nil
+}
+
+// parseTransactions reads the uploaded text in the given format
+func parseTransactions(text, format string) (Dataset, error) {
+	var dataset Dataset
+	switch format {
+	case "lines":
+		for _, line := range strings.Split(text, "\n") {
+			if items := strings.Fields(line); len(items) > 0 {
+				dataset = append(dataset, items)
+			}
+		}
+	case "csv":
+		r := csv.NewReader(strings.NewReader(text))
+		r.FieldsPerRecord = -1
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			var items Transaction
+			for _, cell := range record {
+				if cell = strings.TrimSpace(cell); cell != "" {
+					items = append(items, cell)
+				}
+			}
+			if len(items) > 0 {
+				dataset = append(dataset, items)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("format must be lines or csv")
+	}
+	return dataset, nil
+}
+
+// toJS converts v to a JavaScript value through its JSON encoding
+func toJS(v interface{}) js.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return jsError(err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+func jsError(err error) js.Value {
+	return js.ValueOf(map[string]interface{}{"error": err.Error()})
+}
+
+// wasmMiner parses the arguments of an API call and mines the transactions
+func wasmMiner(args []js.Value) (*AprioriMiner, wasmOptions, error) {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return nil, wasmOptions{}, fmt.Errorf("expected the transactions as a string")
+	}
+	var optsArg js.Value
+	if len(args) > 1 {
+		optsArg = args[1]
+	}
+	opts, err := parseWasmOptions(optsArg)
+	if err != nil {
+		return nil, opts, err
+	}
+	dataset, err := parseTransactions(args[0].String(), opts.Format)
+	if err != nil {
+		return nil, opts, err
+	}
+	miner := NewAprioriMiner(dataset, opts.MinSupport)
+	miner.Mine()
+	return miner, opts, nil
+}
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("mine", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		miner, _, err := wasmMiner(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return toJS(miner.Results())
+	}))
+	api.Set("rules", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		miner, opts, err := wasmMiner(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return toJS(miner.GenerateRules(opts.MinConfidence))
+	}))
+	js.Global().Set("apriori", api)
+
+	// Keep the Go runtime alive so the callbacks stay valid
+	select {}
+}
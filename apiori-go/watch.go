@@ -0,0 +1,175 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchRunArgs returns the command line of one re-run under watch: the
+// flags given, then flags turning watching off, replacing the results under
+// the dataset's basename and naming the run, then the dataset
+func watchRunArgs(args []string, positional int, runID string) []string {
+	flags := args[:len(args)-positional]
+	out := append([]string(nil), flags...)
+	out = append(out, "-watch=false", "-overwrite", "-run-id", runID)
+	return append(out, args[len(args)-positional:]...)
+}
+
+// snapshotResults copies the result files of base in dir into a new
+// generation directory of watchDir, then points watchDir/LATEST at it. The
+// generation is complete before LATEST names it, so readers following
+// LATEST never see a run half written.
+func snapshotResults(dir, base, watchDir, generation string, keep int) error {
+	staging := filepath.Join(watchDir, "."+generation)
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	files := runResultFiles(dir, base)
+	if len(files) == 0 {
+		return fmt.Errorf("the run wrote no results for %s", base)
+	}
+	for _, name := range files {
+		if err := copyFile(filepath.Join(dir, name), filepath.Join(staging, name)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(staging, filepath.Join(watchDir, generation)); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(watchDir, "LATEST"), []byte(generation+"\n")); err != nil {
+		return err
+	}
+	return rotateGenerations(watchDir, keep)
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// runWatch mines filename with the CLI's flags whenever its content changes,
+// starting with a run on the current content. Each run is a child process
+// writing the usual results, which are then snapshotted as the latest
+// generation under results/watch/<dataset>. It returns when interrupted.
+func runWatch(filename string, args []string, positional int, debounce time.Duration, keep int) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("watch needs a dataset file, not the directory %s", filename)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// Editors and exporters often replace the file, so the directory is watched
+	absolute, err := filepath.Abs(filename)
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(absolute)); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	base := getOutputBasename(filename)
+	watchDir := filepath.Join("results", "watch", base)
+	if err := os.MkdirAll(watchDir, 0755); err != nil {
+		return err
+	}
+
+	lastHash := ""
+	mine := func() {
+		hash, err := fileSHA256(filename)
+		if os.IsNotExist(err) {
+			log.Printf("%s is gone; waiting for it to reappear", filename)
+			return
+		}
+		if err != nil {
+			log.Printf("Error reading %s: %v", filename, err)
+			return
+		}
+		if hash == lastHash {
+			return
+		}
+		runID := newRunID()
+		log.Printf("Mining %s (run %s)", filename, runID)
+		start := time.Now()
+		cmd := exec.CommandContext(ctx, executable, watchRunArgs(args, positional, runID)...)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		// An interrupted run still writes its complete levels
+		cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+		cmd.WaitDelay = 30 * time.Second
+		if err := cmd.Run(); err != nil {
+			log.Printf("Run %s failed: %v; the latest snapshot is unchanged", runID, err)
+			return
+		}
+		if err := snapshotResults("results", base, watchDir, runID, keep); err != nil {
+			log.Printf("Error snapshotting run %s: %v", runID, err)
+			return
+		}
+		lastHash = hash
+		log.Printf("Run %s finished in %.2f seconds; %s now names it", runID, time.Since(start).Seconds(), filepath.Join(watchDir, "LATEST"))
+	}
+
+	mine()
+	log.Printf("Watching %s for changes", filename)
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopped watching %s", filename)
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Name != absolute || event.Op == fsnotify.Chmod {
+				continue
+			}
+			// Wait until writes stop before mining
+			settle = time.After(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Watch error: %v", err)
+		case <-settle:
+			settle = nil
+			mine()
+		}
+	}
+}
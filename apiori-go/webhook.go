@@ -0,0 +1,95 @@
+//go:build !js
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// webhookAttempts is the number of delivery attempts made for each webhook
+const webhookAttempts = 3
+
+// WebhookPayload is POSTed to a job's webhook URL once the job finishes
+type WebhookPayload struct {
+	JobID      string            `json:"jobId"`
+	Namespace  string            `json:"namespace"`
+	Dataset    string            `json:"dataset"`
+	MinSupport float64           `json:"minSupport"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Itemsets   int               `json:"itemsets"`
+	Metrics    TimingMetrics     `json:"metrics"`
+	FinishedAt *time.Time        `json:"finishedAt,omitempty"`
+	Results    map[string]string `json:"results,omitempty"`
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute HTTP(S) URL
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhookUrl must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// notifyWebhook delivers the completion payload for job in the background
+func (s *Server) notifyWebhook(job Job) {
+	if job.WebhookURL == "" {
+		return
+	}
+	payload := WebhookPayload{
+		JobID:      job.ID,
+		Namespace:  job.Namespace,
+		Dataset:    job.Dataset,
+		MinSupport: job.MinSupport,
+		Status:     job.Status,
+		Error:      job.Error,
+		Itemsets:   job.Itemsets,
+		Metrics:    job.Metrics,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.Status == JobDone || job.Status == JobInterrupted {
+		// The top-level job routes only address the default namespace
+		resultsURL := fmt.Sprintf("%s/jobs/%s/results", s.publicURL, job.ID)
+		if job.Namespace != DefaultNamespace {
+			resultsURL = fmt.Sprintf("%s/namespaces/%s/jobs/%s/results", s.publicURL, job.Namespace, job.ID)
+		}
+		payload.Results = map[string]string{
+			"json": resultsURL + "?format=json",
+			"csv":  resultsURL + "?format=csv",
+		}
+	}
+	go deliverWebhook(job.WebhookURL, payload)
+}
+
+// deliverWebhook POSTs payload to target, retrying with backoff on failure
+func deliverWebhook(target string, payload WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("failed to encode webhook for job %s: %v", payload.JobID, err)
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	backoff := time.Second
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		log.Printf("webhook for job %s failed (attempt %d/%d): %v", payload.JobID, attempt, webhookAttempts, err)
+		if attempt < webhookAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
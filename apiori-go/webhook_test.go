@@ -0,0 +1,40 @@
+//go:build !js
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookResultsAddressTheJobNamespace(t *testing.T) {
+	payloads := make(chan WebhookPayload, 2)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		payloads <- payload
+	}))
+	defer hook.Close()
+
+	s := &Server{publicURL: "https://miner.example"}
+	want := map[string]string{
+		DefaultNamespace: "https://miner.example/jobs/j1/results?format=csv",
+		"team":           "https://miner.example/namespaces/team/jobs/j1/results?format=csv",
+	}
+	for ns, csvURL := range want {
+		s.notifyWebhook(Job{ID: "j1", Namespace: ns, Status: JobDone, WebhookURL: hook.URL})
+		select {
+		case payload := <-payloads:
+			if payload.Namespace != ns || payload.Results["csv"] != csvURL {
+				t.Errorf("namespace %s: payload namespace %q, csv results %q, want %q", ns, payload.Namespace, payload.Results["csv"], csvURL)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("namespace %s: no webhook delivered", ns)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//go:build !js
+
+package main
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webLogPattern matches the Common Log Format, with the referer and user
+// agent of the Combined Log Format when present
+var webLogPattern = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+)(?: [^"]*)?" (\d{3}) \S+(?: "([^"]*)" "([^"]*)")?`)
+
+// webLogTimeLayout is the timestamp layout of access logs
+const webLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// WebLogOptions controls how access log lines become events
+type WebLogOptions struct {
+	// KeepQuery keeps the whole query string of every URL
+	KeepQuery bool
+	// KeepParams keeps only these query parameters, sorted by name, so that
+	// /item?id=3&utm_source=x and /item?id=3 are the same page
+	KeepParams []string
+	// AllStatuses keeps requests that failed with a 4xx or 5xx status
+	AllStatuses bool
+	// ByUserAgent tells apart the visitors sharing an address by their user
+	// agent, when the log has them
+	ByUserAgent bool
+}
+
+// normalizeURL strips the query string of a request target as configured
+func (o WebLogOptions) normalizeURL(target string) string {
+	path, query, _ := strings.Cut(target, "?")
+	if o.KeepQuery {
+		return target
+	}
+	if len(o.KeepParams) == 0 || query == "" {
+		return path
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return path
+	}
+	kept := url.Values{}
+	for _, name := range o.KeepParams {
+		if v, ok := values[name]; ok {
+			kept[name] = v
+		}
+	}
+	if len(kept) == 0 {
+		return path
+	}
+	// Encode sorts by name
+	return path + "?" + kept.Encode()
+}
+
+// LoadWebLogEvents reads an access log in Common or Combined Log Format into
+// events of a visitor requesting a URL. A logged-in user names the visitor,
+// otherwise the client address does. Lines that do not parse are counted in
+// skipped rather than failing the load, as logs routinely hold a few.
+func LoadWebLogEvents(filename string, opts WebLogOptions) (events []Event, skipped int, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		m := webLogPattern.FindStringSubmatch(line)
+		if m == nil {
+			skipped++
+			continue
+		}
+		t, err := time.Parse(webLogTimeLayout, m[3])
+		if err != nil {
+			skipped++
+			continue
+		}
+		if status, _ := strconv.Atoi(m[6]); status >= 400 && !opts.AllStatuses {
+			continue
+		}
+		visitor := m[1]
+		if m[2] != "-" {
+			visitor = m[2]
+		}
+		if opts.ByUserAgent && m[8] != "" {
+			visitor += " " + m[8]
+		}
+		events = append(events, Event{User: visitor, Time: t.UTC(), Item: opts.normalizeURL(m[5])})
+	}
+	return events, skipped, scanner.Err()
+}
+
+// parseParamList splits a comma-separated list of names
+func parseParamList(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// SetWorkers sets the number of goroutines counting the support of a level's
+// candidates. Each candidate's count is an integer computed by a single
+// worker and consumed in candidate order, so the results are identical for
+// any number of workers.
+func (am *AprioriMiner) SetWorkers(workers int) {
+	am.workers = workers
+}
+
+// countCandidates returns the support count of every candidate, in the order
+// of candidates, splitting the work into contiguous chunks across the workers
+func (am *AprioriMiner) countCandidates(ctx context.Context, candidates []ItemSet) ([]int, error) {
+	counts := make([]int, len(candidates))
+	workers := min(max(am.workers, 1), len(candidates))
+	if workers <= 1 {
+		for i, candidate := range candidates {
+			if i%1024 == 0 && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			counts[i] = am.countSupport(candidate)
+		}
+		return counts, nil
+	}
+
+	// Workers only read the miner; counts are written at disjoint indices
+	chunk := (len(candidates) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(candidates); start += chunk {
+		end := min(start+chunk, len(candidates))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				if (i-start)%1024 == 0 && ctx.Err() != nil {
+					return
+				}
+				counts[i] = am.countSupport(candidates[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// workerRun holds everything a run writes or reports
+type workerRun struct {
+	levels map[int][]string
+	counts map[string]int
+	rules  []byte
+	files  map[string][]byte
+}
+
+// mineWithWorkers mines dataset counting with the given number of workers
+func mineWithWorkers(t *testing.T, dataset Dataset, workers int) workerRun {
+	t.Helper()
+	miner := NewAprioriMiner(dataset, 0.75)
+	miner.SetWorkers(workers)
+	miner.Mine()
+
+	run := workerRun{levels: make(map[int][]string), counts: miner.supportCounts, files: make(map[string][]byte)}
+	for k, sets := range miner.frequentSets {
+		for _, set := range sets {
+			run.levels[k] = append(run.levels[k], itemsetKey(set))
+		}
+	}
+	var rules bytes.Buffer
+	if err := WriteRulesCSV(&rules, miner.GenerateRules(0.9)); err != nil {
+		t.Fatal(err)
+	}
+	run.rules = rules.Bytes()
+
+	dir := t.TempDir()
+	if err := miner.OutputResultsTo(dir, "sample", TimingMetrics{}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		run.files[entry.Name()] = content
+	}
+	return run
+}
+
+func TestWorkersMatchSequentialCounting(t *testing.T) {
+	dataset, err := LoadDataset("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mineWithWorkers(t, dataset, 1)
+	if len(want.levels) < 2 || len(want.rules) == 0 {
+		t.Fatalf("sequential run found %d levels and %d bytes of rules; the comparison needs more", len(want.levels), len(want.rules))
+	}
+	for _, workers := range []int{4, 8} {
+		got := mineWithWorkers(t, dataset, workers)
+		if !reflect.DeepEqual(got.levels, want.levels) {
+			t.Errorf("%d workers: frequent sets differ from 1 worker", workers)
+		}
+		if !reflect.DeepEqual(got.counts, want.counts) {
+			t.Errorf("%d workers: support counts differ from 1 worker", workers)
+		}
+		if !bytes.Equal(got.rules, want.rules) {
+			t.Errorf("%d workers: rules differ from 1 worker", workers)
+		}
+		if len(got.files) != len(want.files) {
+			t.Errorf("%d workers: wrote %d result files, 1 worker wrote %d", workers, len(got.files), len(want.files))
+		}
+		for name, content := range want.files {
+			if !bytes.Equal(got.files[name], content) {
+				t.Errorf("%d workers: %s differs from 1 worker", workers, name)
+			}
+		}
+	}
+}